@@ -0,0 +1,697 @@
+package main
+
+import (
+	"math/rand"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"vm-chan/internal/service"
+)
+
+// Closed-class words of the grammar.
+var (
+	singularDeterminers = strings.Fields("a an every each another this that the no any some")
+	pluralDeterminers   = strings.Fields("the these those some both all no any")
+	quantifiers         = strings.Fields("many several few more most enough")
+	possessives         = strings.Fields("my your his her its our their")
+	subjects            = strings.Fields("I you he she it we they")
+	objects             = strings.Fields("me you him her it us them")
+	indefinites         = strings.Fields("someone somebody something anyone anybody anything everyone everybody everything nobody nothing")
+	modals              = strings.Fields("will would can could shall should may might must")
+	adpositions         = strings.Fields("in on at by for with from of about into over under after before between through during " +
+		"without within against among across behind near toward upon around along despite inside outside as")
+	subordinators = strings.Fields("because if although though while unless until whether since once")
+	coordinators  = strings.Fields("and or but")
+	plainAdverbs  = strings.Fields("very really quite too also often always never sometimes usually already still just soon now " +
+		"then here there again almost rather perhaps maybe even only ever later recently together " +
+		"instead however therefore anyway else once twice")
+	intensifiers   = strings.Fields("very really quite too so extremely pretty rather")
+	interjections  = strings.Fields("yes no oh hi hello hey please wow okay ok sorry well")
+	dayNames       = strings.Fields("Monday Tuesday Wednesday Thursday Friday Saturday Sunday")
+	monthNames     = strings.Fields("January February March April May June July August September October November December")
+	timeNouns      = strings.Fields("week month year day morning night weekend summer winter time quarter")
+	particles      = strings.Fields("out up down off back away over in on")
+	motionVerbs    = strings.Fields("go come walk run drive travel move return fly ride head")
+	mannerAdverbs  = strings.Fields("late early hard fast well home")
+	likingVerbs    = strings.Fields("like love hate prefer enjoy need want")
+	numberWords    = strings.Fields("two three four five six seven eight nine ten twenty fifty hundred thousand million")
+	sayingVerbs    = strings.Fields("think say know believe feel hope guess mean expect suppose")
+	frequencyWords = strings.Fields("often always never sometimes usually just also really still")
+	controlVerbs   = strings.Fields("want need try plan hope decide like start")
+)
+
+// Verbs doubling their final consonant before -ed and -ing, and verbs that
+// may or may not double their final l.
+var (
+	doubling = wordSet(`incur occur deter defer infer confer recur concur repel emit abet allot admit bat beg chat chip
+clap commit control drag drip drop equip flip grab grin hop hug jog knit map nod omit pat permit pin plan plug prefer
+refer regret rob rub scan shop shrug skip slip snap step stir stop strip submit tag tap tip transfer transmit trim
+trip wrap ban dip dot jam pop rip ship spot sob stab swap top trap wed zip compel expel propel rebel excel patrol pad
+plot knot net pet rot bag blog hum ram slam scrap scrub fulfil log debug`)
+	doublingL = wordSet("travel label cancel model signal total level fuel dial channel counsel equal marvel quarrel tunnel panel")
+)
+
+var (
+	negations = map[string]string{
+		"do": "don't", "does": "doesn't", "did": "didn't", "is": "isn't", "are": "aren't", "was": "wasn't",
+		"were": "weren't", "have": "haven't", "has": "hasn't", "had": "hadn't", "will": "won't", "would": "wouldn't",
+		"can": "can't", "could": "couldn't", "should": "shouldn't", "must": "mustn't",
+	}
+	// contractions of a subject pronoun and the auxiliary following it,
+	// tagged like the pronoun.
+	contractions = map[[2]string]string{
+		{"I", "am"}: "I'm", {"I", "have"}: "I've", {"I", "will"}: "I'll", {"I", "would"}: "I'd",
+		{"you", "are"}: "you're", {"you", "have"}: "you've", {"you", "will"}: "you'll",
+		{"he", "is"}: "he's", {"she", "is"}: "she's", {"it", "is"}: "it's",
+		{"we", "are"}: "we're", {"we", "have"}: "we've", {"we", "will"}: "we'll",
+		{"they", "are"}: "they're", {"they", "have"}: "they've", {"they", "will"}: "they'll",
+		{"he", "will"}: "he'll", {"she", "will"}: "she'll", {"it", "will"}: "it'll",
+	}
+	sibilantEnding  = regexp.MustCompile(`(s|x|z|ch|sh)$`)
+	shortClosedStem = regexp.MustCompile(`[^aeiou][aeiou][bdgmnpt]$`)
+	keptE           = regexp.MustCompile(`(ee|ye|oe)$`)
+)
+
+// person is the grammatical person a verb agrees with: "1", "2", "3" or "p"
+// for the plural.
+type person string
+
+var subjectPersons = map[string]person{"I": "1", "you": "2", "he": "3", "she": "3", "it": "3", "we": "p", "they": "p"}
+
+type words = []service.TaggedWord
+
+// generator writes random sentences of a small English grammar, tagged with
+// Universal Dependencies parts of speech.
+type generator struct {
+	*lexicon
+	rng        *rand.Rand
+	irregulars []string
+}
+
+func newGenerator(lex *lexicon, rng *rand.Rand) *generator {
+	g := &generator{lexicon: lex, rng: rng}
+	for verb := range lex.irregular {
+		g.irregulars = append(g.irregulars, verb)
+	}
+	sort.Strings(g.irregulars)
+	return g
+}
+
+func (g *generator) pick(choices []string) string {
+	return choices[g.rng.Intn(len(choices))]
+}
+
+func (g *generator) chance(p float64) bool {
+	return g.rng.Float64() < p
+}
+
+func tagged(word, tag string) service.TaggedWord {
+	return service.TaggedWord{Word: word, Tag: tag}
+}
+
+// verbForms are the forms of a verb.
+type verbForms struct {
+	base, third, past, participle, gerund string
+}
+
+func (g *generator) verb(base string) verbForms {
+	forms, ok := g.irregular[base]
+	if !ok {
+		return verbForms{base, thirdPerson(base), g.pastForm(base), g.pastForm(base), g.gerund(base)}
+	}
+
+	var gerunds, thirds, pasts []string
+	for _, form := range forms {
+		switch {
+		case strings.HasSuffix(form, "ing"):
+			gerunds = append(gerunds, form)
+		case form == base+"s" || form == base+"es" || form == thirdPerson(base):
+			thirds = append(thirds, form)
+		default:
+			pasts = append(pasts, form)
+		}
+	}
+	if len(gerunds) == 0 {
+		gerunds = []string{g.gerund(base)}
+	}
+	if len(thirds) == 0 {
+		thirds = []string{thirdPerson(base)}
+	}
+	if len(pasts) == 0 {
+		pasts = []string{g.pastForm(base)}
+	}
+	return verbForms{base, thirds[0], g.pick(pasts), g.pick(pasts), gerunds[0]}
+}
+
+// agreeing returns the form of a verb agreeing with its subject in a tense.
+func (f verbForms) agreeing(p person, past bool) string {
+	switch {
+	case past:
+		return f.past
+	case p == "3":
+		return f.third
+	}
+	return f.base
+}
+
+func endsInConsonantY(word string) bool {
+	return len(word) > 1 && strings.HasSuffix(word, "y") && !strings.ContainsRune("aeiou", rune(word[len(word)-2]))
+}
+
+func thirdPerson(word string) string {
+	switch {
+	case endsInConsonantY(word):
+		return word[:len(word)-1] + "ies"
+	case sibilantEnding.MatchString(word):
+		return word + "es"
+	}
+	return word + "s"
+}
+
+func (g *generator) pastForm(word string) string {
+	switch {
+	case doubling[word]:
+		return word + word[len(word)-1:] + "ed"
+	case doublingL[word]:
+		return g.pick([]string{word + "ed", word + "led"})
+	case strings.HasSuffix(word, "e"):
+		return word + "d"
+	case endsInConsonantY(word):
+		return word[:len(word)-1] + "ied"
+	}
+	return word + "ed"
+}
+
+func (g *generator) gerund(word string) string {
+	switch {
+	case doubling[word]:
+		return word + word[len(word)-1:] + "ing"
+	case doublingL[word]:
+		return g.pick([]string{word + "ing", word + "ling"})
+	case strings.HasSuffix(word, "ie"):
+		return word[:len(word)-2] + "ying"
+	case strings.HasSuffix(word, "e") && !keptE.MatchString(word) && len(word) > 2:
+		return word[:len(word)-1] + "ing"
+	}
+	return word + "ing"
+}
+
+func adverbOf(word string) string {
+	switch {
+	case endsInConsonantY(word):
+		return word[:len(word)-1] + "ily"
+	case strings.HasSuffix(word, "le"):
+		return word[:len(word)-1] + "y"
+	case strings.HasSuffix(word, "ic"):
+		return word + "ally"
+	case strings.HasSuffix(word, "ue"):
+		return word[:len(word)-1] + "ly"
+	case strings.HasSuffix(word, "ll"):
+		return word + "y"
+	}
+	return word + "ly"
+}
+
+func comparative(word string) string {
+	switch {
+	case strings.HasSuffix(word, "e"):
+		return word + "r"
+	case endsInConsonantY(word):
+		return word[:len(word)-1] + "ier"
+	case shortClosedStem.MatchString(word) && len(word) <= 4:
+		return word + word[len(word)-1:] + "er"
+	}
+	return word + "er"
+}
+
+func (g *generator) noun(plural bool) service.TaggedWord {
+	noun := g.pick(g.nouns)
+	if !plural {
+		return tagged(noun, "NOUN")
+	}
+	if irregular, ok := g.plurals[noun]; ok {
+		return tagged(irregular, "NOUN")
+	}
+	return tagged(thirdPerson(noun), "NOUN")
+}
+
+func (g *generator) adjective() words {
+	r := g.rng.Float64()
+	adjective := g.pick(g.adjectives)
+	switch {
+	case r < 0.1 && len(g.comparables) > 0:
+		return words{tagged(comparative(g.pick(g.comparables)), "ADJ")}
+	case r < 0.15:
+		return words{tagged("un"+g.pick(g.negatables), "ADJ")}
+	case r < 0.25:
+		return words{tagged(g.pick(intensifiers), "ADV"), tagged(adjective, "ADJ")}
+	case r < 0.3:
+		return words{tagged("more", "ADV"), tagged(adjective, "ADJ")}
+	}
+	return words{tagged(adjective, "ADJ")}
+}
+
+func (g *generator) adverb() service.TaggedWord {
+	if g.chance(0.45) {
+		return tagged(adverbOf(g.pick(g.adverbs)), "ADV")
+	}
+	return tagged(g.pick(plainAdverbs), "ADV")
+}
+
+// nounPhrase returns a subject or object noun phrase and the person a verb
+// agrees with it in.
+func (g *generator) nounPhrase(subject bool) (words, person) {
+	r := g.rng.Float64()
+	switch {
+	case r < 0.16:
+		if subject {
+			pronoun := g.pick(subjects)
+			return words{tagged(pronoun, "PRON")}, subjectPersons[pronoun]
+		}
+		return words{tagged(g.pick(objects), "PRON")}, "3"
+	case r < 0.2:
+		return words{tagged(g.pick(indefinites), "PRON")}, "3"
+	case r < 0.3:
+		phrase := words{tagged(g.pick(g.propers), "PROPN")}
+		if g.chance(0.3) {
+			phrase = append(phrase, tagged(g.pick(g.propers), "PROPN"))
+		}
+		if g.chance(0.1) {
+			phrase = words{tagged(g.pick(g.acronyms), "PROPN")}
+		}
+		return phrase, "3"
+	}
+
+	plural := g.chance(0.35)
+	var phrase words
+	switch {
+	case r < 0.33:
+		phrase = words{tagged(g.pick(possessives), "PRON")}
+	case r < 0.36:
+		phrase, plural = words{tagged(g.pick(quantifiers), "ADJ")}, true
+	case r < 0.42:
+		number := strconv.Itoa(2 + g.rng.Intn(998))
+		if g.chance(0.5) {
+			number = g.pick(numberWords)
+		}
+		phrase, plural = words{tagged(number, "NUM")}, true
+	case r < 0.47:
+		plural = true
+	case r < 0.52:
+		if g.chance(0.5) {
+			phrase = words{tagged(g.pick(g.propers)+"'s", "PROPN")}
+		} else {
+			phrase = words{tagged("the", "DET"), tagged(g.pick(g.nouns)+"'s", "NOUN")}
+		}
+	case r < 0.55:
+		phrase = words{tagged(g.pick([]string{"what", "which"}), "DET")}
+	case plural:
+		phrase = words{tagged(g.pick(pluralDeterminers), "DET")}
+	default:
+		phrase = words{tagged(g.pick(singularDeterminers), "DET")}
+	}
+
+	adjectives := 0
+	if r := g.rng.Float64(); r >= 0.9 {
+		adjectives = 2
+	} else if r >= 0.55 {
+		adjectives = 1
+	}
+	for i := 0; i < adjectives; i++ {
+		phrase = append(phrase, g.adjective()...)
+	}
+	if g.chance(0.06) {
+		phrase = append(phrase, g.noun(false))
+	}
+	phrase = append(phrase, g.noun(plural))
+	if first := phrase[0].Word; (first == "a" || first == "an") && len(phrase) > 1 {
+		phrase[0].Word = "a"
+		if strings.ContainsRune("aeiou", rune(phrase[1].Word[0])) {
+			phrase[0].Word = "an"
+		}
+	}
+	if g.chance(0.15) {
+		phrase = append(phrase, g.prepositionalPhrase()...)
+	}
+
+	agreement := person("3")
+	if plural {
+		agreement = "p"
+	}
+	if g.chance(0.05) {
+		phrase = append(phrase, tagged(g.pick([]string{"who", "that", "which"}), "PRON"))
+		phrase = append(phrase, g.verbPhrase(agreement)...)
+	}
+	return phrase, agreement
+}
+
+func (g *generator) object() words {
+	phrase, _ := g.nounPhrase(false)
+	return phrase
+}
+
+func (g *generator) timePhrase() words {
+	r := g.rng.Float64()
+	switch {
+	case r < 0.3:
+		return words{tagged(g.pick([]string{"next", "last"}), "ADJ"), tagged(g.pick(timeNouns), "NOUN")}
+	case r < 0.45:
+		return words{tagged("this", "DET"), tagged(g.pick(timeNouns), "NOUN")}
+	case r < 0.7:
+		adposition := g.pick([]string{"on", "since", "until", "by", "before", "after"})
+		return words{tagged(adposition, "ADP"), tagged(g.pick(dayNames), "PROPN")}
+	case r < 0.85:
+		return words{tagged(g.pick([]string{"yesterday", "today", "tomorrow", "tonight"}), "NOUN")}
+	}
+	return words{tagged("in", "ADP"), tagged(g.pick(monthNames), "PROPN")}
+}
+
+func (g *generator) prepositionalPhrase() words {
+	if g.chance(0.15) {
+		return g.timePhrase()
+	}
+	adposition := g.pick(adpositions)
+	if g.chance(0.005) {
+		adposition = "like"
+	}
+	return append(words{tagged(adposition, "ADP")}, g.object()...)
+}
+
+func be(p person, past bool) string {
+	switch {
+	case past && (p == "1" || p == "3"):
+		return "was"
+	case past:
+		return "were"
+	case p == "1":
+		return "am"
+	case p == "3":
+		return "is"
+	}
+	return "are"
+}
+
+// negated returns the negative contraction of an auxiliary, or the auxiliary
+// when it has none.
+func negated(auxiliary string) string {
+	if negation, ok := negations[auxiliary]; ok {
+		return negation
+	}
+	return auxiliary
+}
+
+// complement returns what follows a verb: an object, an infinitive or a
+// clause, with optional prepositional phrase and adverb.
+func (g *generator) complement() words {
+	var phrase words
+	if g.chance(0.1) {
+		phrase = append(phrase, tagged(g.pick(particles), "ADP"))
+	}
+	switch r := g.rng.Float64(); {
+	case r < 0.65:
+		phrase = append(phrase, g.object()...)
+	case r < 0.72:
+		phrase = append(phrase, tagged("to", "PART"), tagged(g.pick(g.verbs), "VERB"))
+		phrase = append(phrase, g.object()...)
+	case r < 0.78:
+		phrase = append(phrase, tagged("that", "SCONJ"))
+		phrase = append(phrase, g.clause()...)
+	}
+	if g.chance(0.3) {
+		phrase = append(phrase, g.prepositionalPhrase()...)
+	}
+	if g.chance(0.15) {
+		phrase = append(phrase, g.adverb())
+	} else if g.chance(0.05) {
+		phrase = append(phrase, tagged(g.pick(mannerAdverbs), "ADV"))
+	}
+	return phrase
+}
+
+func (g *generator) verbPhrase(p person) words {
+	lemma := g.pick(g.verbs)
+	if g.chance(0.3) {
+		lemma = g.pick(g.irregulars)
+	}
+	v := g.verb(lemma)
+	r := g.rng.Float64()
+	past := g.chance(0.5)
+
+	var phrase words
+	if g.chance(0.1) {
+		phrase = append(phrase, tagged(g.pick(frequencyWords), "ADV"))
+	}
+	switch {
+	case r < 0.06:
+		motion := g.verb(g.pick(motionVerbs))
+		phrase = append(phrase, tagged(motion.agreeing(p, past), "VERB"))
+		switch rr := g.rng.Float64(); {
+		case rr < 0.5:
+			phrase = append(phrase, tagged("to", "ADP"))
+			phrase = append(phrase, g.object()...)
+		case rr < 0.75:
+			phrase = append(phrase, tagged(g.pick([]string{"home", "here", "there", "away", "back", "abroad", "outside"}), "ADV"))
+		default:
+			phrase = append(phrase, tagged(g.pick(particles), "ADP"))
+		}
+		if g.chance(0.4) {
+			phrase = append(phrase, g.timePhrase()...)
+		}
+	case r < 0.1:
+		liking := g.verb(g.pick(likingVerbs))
+		phrase = append(phrase, tagged(liking.agreeing(p, past), "VERB"))
+		if !g.chance(0.7) {
+			phrase = append(phrase, tagged(v.gerund, "VERB"))
+		}
+		phrase = append(phrase, g.object()...)
+	case r < 0.36:
+		phrase = append(phrase, tagged(v.agreeing(p, past), "VERB"))
+		phrase = append(phrase, g.complement()...)
+	case r < 0.42:
+		modal := g.pick(modals)
+		if g.chance(0.2) {
+			if negation, ok := negations[modal]; ok {
+				phrase = append(phrase, tagged(negation, "AUX"))
+			} else {
+				phrase = append(phrase, tagged(modal, "AUX"), tagged("not", "PART"))
+			}
+		} else {
+			phrase = append(phrase, tagged(modal, "AUX"))
+		}
+		switch {
+		case g.chance(0.2):
+			phrase = append(phrase, tagged("be", "AUX"), tagged(v.gerund, "VERB"))
+		case g.chance(0.15):
+			phrase = append(phrase, tagged("have", "AUX"), tagged(v.participle, "VERB"))
+		default:
+			phrase = append(phrase, tagged(v.base, "VERB"))
+		}
+		phrase = append(phrase, g.complement()...)
+	case r < 0.52:
+		auxiliary := be(p, past)
+		if g.chance(0.15) {
+			auxiliary = negated(auxiliary)
+		}
+		phrase = append(phrase, tagged(auxiliary, "AUX"), tagged(v.gerund, "VERB"))
+		phrase = append(phrase, g.complement()...)
+	case r < 0.6:
+		auxiliary := "had"
+		if !past {
+			auxiliary = "have"
+			if p == "3" {
+				auxiliary = "has"
+			}
+		}
+		if g.chance(0.15) {
+			auxiliary = negated(auxiliary)
+		}
+		phrase = append(phrase, tagged(auxiliary, "AUX"))
+		if g.chance(0.2) {
+			phrase = append(phrase, tagged(g.pick([]string{"already", "never"}), "ADV"))
+		}
+		phrase = append(phrase, tagged(v.participle, "VERB"))
+		phrase = append(phrase, g.complement()...)
+	case r < 0.66:
+		phrase = append(phrase, tagged(be(p, past), "AUX"), tagged(v.participle, "VERB"))
+		if g.chance(0.4) {
+			phrase = append(phrase, tagged("by", "ADP"))
+			phrase = append(phrase, g.object()...)
+		} else if g.chance(0.4) {
+			phrase = append(phrase, g.prepositionalPhrase()...)
+		}
+	case r < 0.82:
+		auxiliary := be(p, past)
+		if g.chance(0.15) {
+			auxiliary = negated(auxiliary)
+		}
+		phrase = append(phrase, tagged(auxiliary, "AUX"))
+		switch rr := g.rng.Float64(); {
+		case rr < 0.55:
+			phrase = append(phrase, g.adjective()...)
+		case rr < 0.8:
+			phrase = append(phrase, g.object()...)
+		default:
+			phrase = append(phrase, g.prepositionalPhrase()...)
+		}
+	case r < 0.9:
+		auxiliary := "did"
+		if !past {
+			auxiliary = "do"
+			if p == "3" {
+				auxiliary = "does"
+			}
+		}
+		if g.chance(0.7) {
+			phrase = append(phrase, tagged(negations[auxiliary], "AUX"))
+		} else {
+			phrase = append(phrase, tagged(auxiliary, "AUX"), tagged("not", "PART"))
+		}
+		if g.chance(0.25) {
+			v = g.verb(g.pick(likingVerbs))
+		}
+		phrase = append(phrase, tagged(v.base, "VERB"))
+		phrase = append(phrase, g.complement()...)
+	case r < 0.95:
+		light := g.pick([]string{"have", "get", "do", "make", "take", "go"})
+		forms := g.verb(light)
+		switch light {
+		case "have":
+			forms = verbForms{base: "have", third: "has", past: "had"}
+		case "do":
+			forms = verbForms{base: "do", third: "does", past: "did"}
+		}
+		phrase = append(phrase, tagged(forms.agreeing(p, past), "VERB"))
+		phrase = append(phrase, g.object()...)
+	default:
+		control := g.verb(g.pick(controlVerbs))
+		phrase = append(phrase, tagged(control.agreeing(p, past), "VERB"), tagged("to", "PART"), tagged(v.base, "VERB"))
+		phrase = append(phrase, g.complement()...)
+	}
+	if g.chance(0.1) {
+		phrase = append(phrase, g.adverb())
+	}
+	return phrase
+}
+
+// clause returns a subject and its verb phrase, contracting a pronoun
+// subject with the auxiliary following it now and then.
+func (g *generator) clause() words {
+	subject, p := g.nounPhrase(true)
+	predicate := g.verbPhrase(p)
+	if len(subject) == 1 && subject[0].Tag == "PRON" && len(predicate) > 0 && predicate[0].Tag == "AUX" {
+		contraction, ok := contractions[[2]string{subject[0].Word, predicate[0].Word}]
+		if ok && g.chance(0.4) {
+			return append(words{tagged(contraction, "PRON")}, predicate[1:]...)
+		}
+	}
+	return append(subject, predicate...)
+}
+
+func (g *generator) sentence() words {
+	var sentence words
+	if g.chance(0.05) {
+		sentence = append(sentence, tagged(g.pick(interjections), "INTJ"))
+	}
+	switch r := g.rng.Float64(); {
+	case r < 0.4:
+		sentence = append(sentence, g.clause()...)
+	case r < 0.5:
+		sentence = append(sentence, g.clause()...)
+		sentence = append(sentence, tagged(g.pick(coordinators), "CCONJ"))
+		sentence = append(sentence, g.clause()...)
+	case r < 0.58:
+		sentence = append(sentence, tagged(g.pick(subordinators), "SCONJ"))
+		sentence = append(sentence, g.clause()...)
+		sentence = append(sentence, g.clause()...)
+	case r < 0.63:
+		sentence = append(sentence, g.clause()...)
+		sentence = append(sentence, tagged(g.pick(subordinators), "SCONJ"))
+		sentence = append(sentence, g.clause()...)
+	case r < 0.68:
+		subject, p := g.nounPhrase(true)
+		sentence = append(sentence, subject...)
+		sentence = append(sentence, tagged(g.verb(g.pick(sayingVerbs)).agreeing(p, false), "VERB"))
+		if g.chance(0.5) {
+			sentence = append(sentence, tagged("that", "SCONJ"))
+		}
+		sentence = append(sentence, g.clause()...)
+	case r < 0.74:
+		sentence = append(sentence, tagged(g.pick([]string{"do", "does", "did", "can", "will", "should", "could", "would"}), "AUX"))
+		subject, _ := g.nounPhrase(true)
+		sentence = append(sentence, subject...)
+		sentence = append(sentence, tagged(g.pick(g.verbs), "VERB"))
+		sentence = append(sentence, g.complement()...)
+	case r < 0.8:
+		question := g.pick([]string{"what", "where", "when", "why", "how", "who"})
+		tag := "ADV"
+		if question == "what" || question == "who" {
+			tag = "PRON"
+		}
+		sentence = append(sentence, tagged(question, tag),
+			tagged(g.pick([]string{"do", "does", "did", "can", "will", "should"}), "AUX"))
+		subject, _ := g.nounPhrase(true)
+		sentence = append(sentence, subject...)
+		sentence = append(sentence, tagged(g.pick(g.verbs), "VERB"))
+		if question != "what" && g.chance(0.6) {
+			sentence = append(sentence, g.object()...)
+		}
+	case r < 0.86:
+		if g.chance(0.3) {
+			sentence = append(sentence, tagged("please", "INTJ"))
+		}
+		if g.chance(0.2) {
+			sentence = append(sentence, tagged("don't", "AUX"))
+		}
+		sentence = append(sentence, tagged(g.pick(g.verbs), "VERB"))
+		sentence = append(sentence, g.complement()...)
+	case r < 0.9:
+		verb := g.pick([]string{"is", "was"})
+		if g.chance(0.4) {
+			verb = g.pick([]string{"are", "were"})
+		}
+		sentence = append(sentence, tagged("there", "PRON"), tagged(verb, "AUX"))
+		sentence = append(sentence, g.object()...)
+		if g.chance(0.5) {
+			sentence = append(sentence, g.prepositionalPhrase()...)
+		}
+	case r < 0.94:
+		sentence = append(sentence, tagged(g.pick([]string{"this", "that", "it"}), "PRON"), tagged(g.pick([]string{"is", "was"}), "AUX"))
+		if g.chance(0.5) {
+			sentence = append(sentence, g.adjective()...)
+		} else {
+			sentence = append(sentence, g.object()...)
+		}
+	case r < 0.95:
+		sentence = append(sentence, g.prepositionalPhrase()...)
+		sentence = append(sentence, g.clause()...)
+	case r < 0.97:
+		sentence = append(sentence, tagged("Thanks", "NOUN"), tagged("for", "ADP"))
+		if g.chance(0.5) {
+			sentence = append(sentence, tagged(g.verb(g.pick(g.verbs)).gerund, "VERB"))
+		}
+		sentence = append(sentence, g.object()...)
+	default:
+		sentence = append(sentence, g.adverb())
+		sentence = append(sentence, g.clause()...)
+	}
+
+	if first := sentence[0].Word; first != "I" {
+		sentence[0].Word = strings.ToUpper(first[:1]) + first[1:]
+	}
+	if g.chance(0.01) {
+		for i := range sentence {
+			sentence[i].Word = strings.ToUpper(sentence[i].Word)
+		}
+	}
+	return sentence
+}
+
+func wordSet(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(text) {
+		set[word] = true
+	}
+	return set
+}
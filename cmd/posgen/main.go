@@ -0,0 +1,260 @@
+// Command posgen writes the CoNLL-U corpus the embedded part-of-speech model
+// is trained on. Its sentences are generated from a small English grammar
+// whose open-class words come from the embedded spelling dictionary and lemma
+// list, so that the model holds no third-party data:
+//
+//	go run ./cmd/posgen -o train.conllu
+//	go run ./cmd/postrain -o internal/service/data/pos/en.model.gz train.conllu
+//
+// Run it from the root of the repository, or point -dic and -lemmas to the
+// files. The same seed and sentence count always yield the same corpus.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"vm-chan/internal/service"
+)
+
+func main() {
+	output := flag.String("o", "train.conllu", "path of the corpus to write")
+	count := flag.Int("n", 60000, "number of sentences to generate")
+	seed := flag.Int64("seed", 7, "seed of the random generator")
+	dicPath := flag.String("dic", "internal/service/data/spelling/en.dic", "Hunspell dictionary the words are taken from")
+	lemmaPath := flag.String("lemmas", "internal/service/data/lemmas/en.txt", "lemma list the irregular forms are taken from")
+	flag.Parse()
+
+	lexicon, err := loadLexicon(*dicPath, *lemmaPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	generator := newGenerator(lexicon, rand.New(rand.NewSource(*seed)))
+
+	file, err := os.Create(*output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	out := bufio.NewWriter(file)
+	for i := 1; i <= *count; i++ {
+		writeSentence(out, i, generator.sentence())
+	}
+	if err := out.Flush(); err != nil {
+		_ = file.Close()
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := file.Close(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("generated %d sentences, corpus written to %s\n", *count, *output)
+}
+
+// writeSentence writes a sentence ended by a period in CoNLL-U, leaving every
+// field but the form and the universal part of speech empty.
+func writeSentence(out *bufio.Writer, id int, words []service.TaggedWord) {
+	fmt.Fprintf(out, "# sent_id = gen-%d\n", id)
+	words = append(words, service.TaggedWord{Word: ".", Tag: "PUNCT"})
+	for i, word := range words {
+		fmt.Fprintf(out, "%d\t%s\t_\t%s\t_\t_\t_\t_\t_\t_\n", i+1, word.Word, word.Tag)
+	}
+	fmt.Fprintln(out)
+}
+
+// lexicon holds the open-class words of the grammar, sorted so that the
+// corpus only depends on the seed.
+type lexicon struct {
+	nouns       []string
+	verbs       []string
+	adjectives  []string
+	comparables []string
+	adverbs     []string // adjectives taking -ly
+	negatables  []string // adjectives taking un-
+	propers     []string
+	acronyms    []string
+	irregular   map[string][]string // irregular forms of verbs
+	plurals     map[string]string   // irregular plurals of nouns
+}
+
+var (
+	lowercaseWord   = regexp.MustCompile(`^[a-z]+$`)
+	capitalizedWord = regexp.MustCompile(`^[A-Z][a-z]+$`)
+	acronymWord     = regexp.MustCompile(`^[A-Z]{2,}$`)
+)
+
+// irregularVerbs are the verbs whose forms are read from the lemma list even
+// when the dictionary does not flag them as verbs.
+var irregularVerbs = strings.Fields(`make take see come give find think tell become leave feel bring begin keep
+hold write stand hear let meet run pay sit speak lie lead read grow lose fall send build understand draw break
+spend cut rise drive buy wear choose seek throw catch deal win forget sell fight teach eat sing fly hang hide shake
+shoot sleep steal swim wake ride ring sink bite feed freeze hurt kneel know say put set shut spread split bet quit
+mean sweep weep light slide stick sting strike swing tear bind bend bleed blow breed creep dig fling flee forgive
+grind lay lend shine shrink slay sling spin spit stink stride string strive swear swell tread wind wring arise
+awake bear beat cling dream drink burn learn smell spell spill spoil go get`)
+
+// loadLexicon sorts the words of a Hunspell dictionary into classes by their
+// affix flags: nouns take the possessive (M), verbs -ed and -ing (D and G),
+// and adjectives -er and -est (R and T), -ly (Y), -ness (P) or un- (U).
+// Capitalized words taking the possessive are proper nouns.
+func loadLexicon(dicPath, lemmaPath string) (*lexicon, error) {
+	flags, err := readDictionary(dicPath)
+	if err != nil {
+		return nil, err
+	}
+	forms, err := readLemmas(lemmaPath)
+	if err != nil {
+		return nil, err
+	}
+
+	nouns, verbs, adjectives := map[string]bool{}, map[string]bool{}, map[string]bool{}
+	comparables, adverbs, negatables := map[string]bool{}, map[string]bool{}, map[string]bool{}
+	var capitalized, acronyms []string
+	for word, flag := range flags {
+		switch {
+		case lowercaseWord.MatchString(word):
+			has := func(f string) bool { return strings.ContainsAny(flag, f) }
+			if has("M") {
+				nouns[word] = true
+			}
+			if has("D") && has("G") {
+				verbs[word] = true
+			}
+			if has("R") && has("T") {
+				comparables[word] = true
+			}
+			if has("RTYPU") {
+				adjectives[word] = true
+			}
+			if has("Y") {
+				adverbs[word] = true
+			}
+			if has("U") {
+				negatables[word] = true
+			}
+		case capitalizedWord.MatchString(word) && strings.Contains(flag, "M"):
+			capitalized = append(capitalized, word)
+		case acronymWord.MatchString(word):
+			acronyms = append(acronyms, word)
+		}
+	}
+
+	lex := &lexicon{
+		acronyms:  acronyms,
+		irregular: map[string][]string{},
+		plurals:   map[string]string{},
+	}
+	inflected := map[string]bool{}
+	for lemma, lemmaForms := range forms {
+		for _, form := range lemmaForms {
+			inflected[form] = true
+		}
+		if verbs[lemma] || slices.Contains(irregularVerbs, lemma) {
+			lex.irregular[lemma] = lemmaForms
+			verbs[lemma] = true
+		}
+	}
+	for _, auxiliary := range []string{"be", "have", "do"} {
+		delete(verbs, auxiliary)
+		delete(lex.irregular, auxiliary)
+	}
+	for noun := range nouns {
+		if stem, ok := strings.CutSuffix(noun, "ing"); ok && (verbs[stem] || verbs[stem+"e"]) {
+			delete(nouns, noun)
+		}
+	}
+	for lemma, lemmaForms := range forms {
+		if !nouns[lemma] {
+			continue
+		}
+		for _, form := range lemmaForms {
+			if !strings.HasSuffix(form, "ing") && !strings.HasSuffix(form, "ed") {
+				lex.plurals[lemma] = form
+			}
+		}
+	}
+	for _, word := range capitalized {
+		lower := strings.ToLower(word)
+		if !nouns[lower] && !verbs[lower] && !adjectives[lower] && !inflected[lower] {
+			lex.propers = append(lex.propers, word)
+		}
+	}
+
+	lex.nouns = sortedSet(nouns)
+	lex.verbs = sortedSet(verbs)
+	lex.adjectives = sortedSet(adjectives)
+	lex.comparables = sortedSet(comparables)
+	lex.adverbs = sortedSet(adverbs)
+	lex.negatables = sortedSet(negatables)
+	sort.Strings(lex.propers)
+	sort.Strings(lex.acronyms)
+	for _, lemmaForms := range lex.irregular {
+		sort.Strings(lemmaForms)
+	}
+	return lex, nil
+}
+
+// readDictionary returns the words of a Hunspell .dic file with their flags.
+func readDictionary(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	flags := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if line == 1 || text == "" {
+			continue
+		}
+		word, flag, _ := strings.Cut(text, "/")
+		flags[word] += flag
+	}
+	if err := scanner.Err(); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return flags, file.Close()
+}
+
+// readLemmas returns the inflected forms of every lemma of a lemma list.
+func readLemmas(path string) (map[string][]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	forms := make(map[string][]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		form, lemma, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "\t")
+		if !ok || strings.HasPrefix(form, "#") {
+			continue
+		}
+		forms[lemma] = append(forms[lemma], form)
+	}
+	if err := scanner.Err(); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return forms, file.Close()
+}
+
+func sortedSet(set map[string]bool) []string {
+	words := make([]string, 0, len(set))
+	for word := range set {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	return words
+}
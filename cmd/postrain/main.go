@@ -0,0 +1,128 @@
+// Command postrain trains the part-of-speech tagger of the pos analyzer on
+// CoNLL-U treebanks, such as those of Universal Dependencies:
+//
+//	go run ./cmd/postrain -o en.model.gz en_ewt-ud-train.conllu
+//
+// The embedded model is trained on the corpus written by cmd/posgen; see
+// internal/service/data/pos/README.md.
+//
+// Words are read the way the analyzer tokenizes text: punctuation is skipped
+// and contractions split by the treebank, such as "don't", are joined back
+// into one word tagged like its first part.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"vm-chan/internal/service"
+)
+
+func main() {
+	output := flag.String("o", "en.model.gz", "path of the model to write")
+	iterations := flag.Int("iterations", 5, "number of training iterations")
+	seed := flag.Int64("seed", 1, "seed of the order sentences are visited in")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: postrain [-o model] [-iterations n] [-seed n] treebank.conllu...")
+		os.Exit(2)
+	}
+
+	var sentences [][]service.TaggedWord
+	for _, path := range flag.Args() {
+		read, err := readCoNLLU(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			os.Exit(1)
+		}
+		sentences = append(sentences, read...)
+	}
+
+	tagger := service.TrainPerceptronTagger(sentences, *iterations, *seed)
+
+	file, err := os.Create(*output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := tagger.Save(file); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := file.Close(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("trained on %d sentences, model written to %s\n", len(sentences), *output)
+}
+
+// readCoNLLU returns the sentences of a CoNLL-U file with their universal
+// part-of-speech tags.
+func readCoNLLU(path string) ([][]service.TaggedWord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var sentences [][]service.TaggedWord
+	var sentence []service.TaggedWord
+	// joined is the surface form of a multiword token whose first part is
+	// read next, and skip the number of its remaining parts.
+	joined, skip := "", 0
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if text == "" {
+			if len(sentence) > 0 {
+				sentences = append(sentences, sentence)
+			}
+			sentence = nil
+			continue
+		}
+		if strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Split(text, "\t")
+		if len(fields) != 10 {
+			return nil, fmt.Errorf("line %d: expected 10 fields, got %d", line, len(fields))
+		}
+		id, form, tag := fields[0], fields[1], fields[3]
+		switch {
+		case strings.Contains(id, "."):
+			continue
+		case strings.Contains(id, "-"):
+			var first, last int
+			if _, err := fmt.Sscanf(id, "%d-%d", &first, &last); err != nil {
+				return nil, fmt.Errorf("line %d: invalid range %q", line, id)
+			}
+			joined, skip = form, last-first+1
+			continue
+		case skip > 0:
+			skip--
+			if joined == "" {
+				continue
+			}
+			form, joined = joined, ""
+		}
+
+		if strings.IndexFunc(form, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) < 0 {
+			continue
+		}
+		sentence = append(sentence, service.TaggedWord{Word: form, Tag: tag})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(sentence) > 0 {
+		sentences = append(sentences, sentence)
+	}
+	return sentences, nil
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"net/http"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"vm-chan/internal/config"
 	"vm-chan/internal/domain"
 	"vm-chan/internal/handler"
+	"vm-chan/internal/logging"
 	"vm-chan/internal/middleware"
 	"vm-chan/internal/repository"
 	"vm-chan/internal/service"
@@ -32,7 +34,7 @@ func main() {
 		panic(fmt.Sprintf("Failed to load config: %v", err))
 	}
 
-	logger := initLogger(cfg.Logging.Level)
+	logger := initLogger(cfg.Logging)
 	defer func(logger *zap.Logger) {
 		if syncErr := logger.Sync(); syncErr != nil {
 			fmt.Printf("Error syncing logger: %v\n", syncErr)
@@ -42,13 +44,82 @@ func main() {
 	logger.Info("Starting VM-Chan microservice", zap.String("version", "1.0.0"))
 
 	userRepo := repository.NewUserRepository(logger)
+	dictionaryRepo := repository.NewDictionaryRepository(logger)
+	fingerprintRepo, err := newFingerprintRepository(cfg.Analysis, logger)
+	if err != nil {
+		logger.Fatal("Failed to load fingerprint store", zap.Error(err))
+	}
+	fingerprintIndex := service.NewFingerprintIndex(fingerprintRepo)
+	db, err := openDatabase(cfg.Storage)
+	if err != nil {
+		logger.Fatal("Failed to open database", zap.Error(err))
+	}
+	if db != nil {
+		defer db.Close()
+	}
+	documentRepo, err := newDocumentRepository(db, logger)
+	if err != nil {
+		logger.Fatal("Failed to create document store", zap.Error(err))
+	}
+	historyRepo, err := newHistoryRepository(db, logger)
+	if err != nil {
+		logger.Fatal("Failed to create history store", zap.Error(err))
+	}
+	searchIndex, err := newSearchIndex(cfg.Storage)
+	if err != nil {
+		logger.Fatal("Failed to load search index", zap.Error(err))
+	}
+	indexed, removed, err := searchIndex.Sync(context.Background(), documentRepo)
+	if err != nil {
+		logger.Fatal("Failed to synchronize search index", zap.Error(err))
+	}
+	logger.Info("Search index synchronized", zap.Int("indexed", indexed), zap.Int("removed", removed))
 	authService := service.NewAuthService(userRepo, cfg.Auth.JWTSecret, logger)
-	textAnalysisService := service.NewTextAnalysisService(logger)
+	analyzerResources, err := loadAnalyzerResources(cfg.Analysis)
+	if err != nil {
+		logger.Fatal("Failed to load analyzer resources", zap.Error(err))
+	}
+	analyzerResources.CustomWords = dictionaryRepo
+	analyzerResources.Fingerprints = fingerprintIndex
+	analyzerResources.SearchIndex = searchIndex
+	analyzerRegistry, err := service.NewAnalyzerRegistry(service.DefaultAnalyzers(analyzerResources)...)
+	if err != nil {
+		logger.Fatal("Failed to register analyzers", zap.Error(err))
+	}
+	textAnalysisService := service.NewTextAnalysisService(analyzerRegistry, service.NewTokenizer(), fingerprintIndex, logger)
+	morphologyService := service.NewMorphologyService(service.NewTokenizer(), logger)
+	comparisonService := service.NewComparisonService(service.NewTokenizer(), logger)
+	normalizationService := service.NewNormalizationService(logger)
+	summarizationService := service.NewSummarizationService(service.NewTokenizer(), logger)
+	dictionaryService := service.NewDictionaryService(dictionaryRepo, service.NewTokenizer(), logger)
+	duplicatesService := service.NewDuplicatesService(fingerprintIndex, logger)
+	documentService := service.NewDocumentService(documentRepo, textAnalysisService, searchIndex, fingerprintIndex, logger)
+	searchService := service.NewSearchService(searchIndex, documentRepo, logger)
+	historyService := service.NewHistoryService(historyRepo, logger)
+	batchAnalysisService := service.NewBatchAnalysisService(textAnalysisService, historyService,
+		cfg.Analysis.BatchMaxTexts, cfg.Analysis.BatchWorkers, logger)
+	redactionService, err := newRedactionService(cfg.Redaction, logger)
+	if err != nil {
+		logger.Fatal("Failed to load redaction name list", zap.Error(err))
+	}
 
 	authHandler := handler.NewAuthHandler(authService, logger)
-	textAnalysisHandler := handler.NewTextAnalysisHandler(textAnalysisService, logger)
+	textAnalysisHandler := handler.NewTextAnalysisHandler(textAnalysisService, historyService, logger)
+	morphologyHandler := handler.NewMorphologyHandler(morphologyService, logger)
+	redactionHandler := handler.NewRedactionHandler(redactionService, logger)
+	comparisonHandler := handler.NewComparisonHandler(comparisonService, logger)
+	dictionaryHandler := handler.NewDictionaryHandler(dictionaryService, logger)
+	normalizationHandler := handler.NewNormalizationHandler(normalizationService, logger)
+	summarizationHandler := handler.NewSummarizationHandler(summarizationService, logger)
+	duplicatesHandler := handler.NewDuplicatesHandler(duplicatesService, logger)
+	documentHandler := handler.NewDocumentHandler(documentService, logger)
+	searchHandler := handler.NewSearchHandler(searchService, logger)
+	historyHandler := handler.NewHistoryHandler(historyService, logger)
+	batchAnalysisHandler := handler.NewBatchAnalysisHandler(batchAnalysisService, logger)
 
-	router := setupRouter(cfg, logger, authHandler, textAnalysisHandler, authService)
+	router := setupRouter(cfg, logger, authHandler, textAnalysisHandler, batchAnalysisHandler, morphologyHandler, redactionHandler,
+		comparisonHandler, dictionaryHandler, normalizationHandler, summarizationHandler, duplicatesHandler, documentHandler,
+		searchHandler, historyHandler, authService)
 
 	srv := &http.Server{
 		Addr:         ":" + cfg.Server.Port,
@@ -84,6 +155,17 @@ func setupRouter(
 	logger *zap.Logger,
 	authHandler *handler.AuthHandler,
 	textAnalysisHandler *handler.TextAnalysisHandler,
+	batchAnalysisHandler *handler.BatchAnalysisHandler,
+	morphologyHandler *handler.MorphologyHandler,
+	redactionHandler *handler.RedactionHandler,
+	comparisonHandler *handler.ComparisonHandler,
+	dictionaryHandler *handler.DictionaryHandler,
+	normalizationHandler *handler.NormalizationHandler,
+	summarizationHandler *handler.SummarizationHandler,
+	duplicatesHandler *handler.DuplicatesHandler,
+	documentHandler *handler.DocumentHandler,
+	searchHandler *handler.SearchHandler,
+	historyHandler *handler.HistoryHandler,
 	authService domain.AuthService,
 ) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
@@ -120,13 +202,135 @@ func setupRouter(
 	apiGroup := router.Group("/api/v1")
 	apiGroup.Use(middleware.AuthMiddleware(authService, logger))
 	apiGroup.POST("/analyze", textAnalysisHandler.AnalyzeText)
+	apiGroup.POST("/analyze/batch", batchAnalysisHandler.AnalyzeBatch)
+	apiGroup.GET("/analyzers", textAnalysisHandler.ListAnalyzers)
+	apiGroup.POST("/stem", morphologyHandler.Stem)
+	apiGroup.POST("/redact", redactionHandler.Redact)
+	apiGroup.POST("/compare", comparisonHandler.Compare)
+	apiGroup.GET("/dictionary", dictionaryHandler.Words)
+	apiGroup.POST("/dictionary", dictionaryHandler.AddWords)
+	apiGroup.DELETE("/dictionary/:word", dictionaryHandler.RemoveWord)
+	apiGroup.POST("/normalize", normalizationHandler.Normalize)
+	apiGroup.POST("/summarize", summarizationHandler.Summarize)
+	apiGroup.GET("/duplicates", duplicatesHandler.Clusters)
+	apiGroup.POST("/documents", documentHandler.Create)
+	apiGroup.GET("/documents", documentHandler.List)
+	apiGroup.GET("/documents/:id", documentHandler.Get)
+	apiGroup.PUT("/documents/:id", documentHandler.Update)
+	apiGroup.DELETE("/documents/:id", documentHandler.Delete)
+	apiGroup.GET("/search", searchHandler.Search)
+	apiGroup.GET("/history", historyHandler.List)
 
 	return router
 }
 
-func initLogger(level string) *zap.Logger {
+func loadAnalyzerResources(cfg config.AnalysisConfig) (service.AnalyzerResources, error) {
+	var resources service.AnalyzerResources
+
+	if cfg.SentimentLexicon != "" {
+		lexicon, err := service.LoadSentimentLexicon(cfg.SentimentLexicon)
+		if err != nil {
+			return resources, err
+		}
+		resources.SentimentLexicon = lexicon
+	}
+
+	if cfg.SpellDictionaryDir != "" {
+		dictionaries, err := service.LoadSpellDictionaries(cfg.SpellDictionaryDir)
+		if err != nil {
+			return resources, err
+		}
+		resources.SpellDictionaries = dictionaries
+	}
+
+	if cfg.ModerationWordlistDir != "" {
+		wordlists, err := service.LoadModerationWordlists(cfg.ModerationWordlistDir)
+		if err != nil {
+			return resources, err
+		}
+		resources.ModerationWordlists = wordlists
+	}
+
+	if cfg.TenantWordlistDir != "" {
+		wordlists, err := service.LoadTenantWordlists(cfg.TenantWordlistDir)
+		if err != nil {
+			return resources, err
+		}
+		resources.TenantWordlists = wordlists
+	}
+
+	if cfg.POSModel != "" {
+		tagger, err := service.LoadPOSTagger(cfg.POSModel)
+		if err != nil {
+			return resources, err
+		}
+		resources.POSTagger = tagger
+	}
+
+	return resources, nil
+}
+
+// newFingerprintRepository returns the store of the fingerprints of analyzed
+// texts, kept in a file when one is configured and in memory otherwise.
+func newFingerprintRepository(cfg config.AnalysisConfig, logger *zap.Logger) (domain.FingerprintRepository, error) {
+	if cfg.FingerprintStore == "" {
+		return repository.NewFingerprintRepository(logger), nil
+	}
+	return repository.NewFileFingerprintRepository(cfg.FingerprintStore, logger)
+}
+
+// openDatabase opens the configured SQLite database, or returns nil when none
+// is configured.
+func openDatabase(cfg config.StorageConfig) (*sql.DB, error) {
+	if cfg.Database == "" {
+		return nil, nil
+	}
+	return repository.OpenSQLite(cfg.Database)
+}
+
+// newDocumentRepository returns the store of documents, kept in the database
+// when one is open and in memory otherwise.
+func newDocumentRepository(db *sql.DB, logger *zap.Logger) (domain.DocumentRepository, error) {
+	if db == nil {
+		return repository.NewDocumentRepository(logger), nil
+	}
+	return repository.NewSQLiteDocumentRepository(db, logger)
+}
+
+// newHistoryRepository returns the store of analysis history, kept in the
+// database when one is open and in memory otherwise.
+func newHistoryRepository(db *sql.DB, logger *zap.Logger) (domain.HistoryRepository, error) {
+	if db == nil {
+		return repository.NewHistoryRepository(logger), nil
+	}
+	return repository.NewSQLiteHistoryRepository(db, logger)
+}
+
+// newSearchIndex returns the full-text index of stored documents, kept in a
+// file when one is configured and in memory otherwise. The tokenizer is the
+// one of text analysis, so that search agrees with word counts.
+func newSearchIndex(cfg config.StorageConfig) (*service.SearchIndex, error) {
+	if cfg.SearchIndex == "" {
+		return service.NewSearchIndex(service.NewTokenizer()), nil
+	}
+	return service.LoadSearchIndex(cfg.SearchIndex, service.NewTokenizer())
+}
+
+func newRedactionService(cfg config.RedactionConfig, logger *zap.Logger) (domain.RedactionService, error) {
+	var names []string
+	if cfg.NameList != "" {
+		var err error
+		if names, err = service.LoadNameList(cfg.NameList); err != nil {
+			return nil, err
+		}
+	}
+
+	return service.NewRedactionService(service.NewTokenizer(), names, cfg.PseudonymSecret, logger), nil
+}
+
+func initLogger(cfg config.LoggingConfig) *zap.Logger {
 	var zapLevel zapcore.Level
-	switch level {
+	switch cfg.Level {
 	case "debug":
 		zapLevel = zapcore.DebugLevel
 	case "warn":
@@ -147,5 +351,10 @@ func initLogger(level string) *zap.Logger {
 		panic(fmt.Sprintf("Failed to initialize logger: %v", err))
 	}
 
-	return logger
+	policy, err := logging.ParsePolicy(cfg.Privacy)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to initialize logger: %v", err))
+	}
+
+	return logging.WithPolicy(logger, policy, cfg.TruncateLength)
 }
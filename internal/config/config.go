@@ -8,10 +8,13 @@ import (
 )
 
 type Config struct {
-	Server  ServerConfig  `mapstructure:"server"`
-	Auth    AuthConfig    `mapstructure:"auth"`
-	Logging LoggingConfig `mapstructure:"logging"`
-	Metrics MetricsConfig `mapstructure:"metrics"`
+	Server    ServerConfig    `mapstructure:"server"`
+	Auth      AuthConfig      `mapstructure:"auth"`
+	Logging   LoggingConfig   `mapstructure:"logging"`
+	Metrics   MetricsConfig   `mapstructure:"metrics"`
+	Analysis  AnalysisConfig  `mapstructure:"analysis"`
+	Redaction RedactionConfig `mapstructure:"redaction"`
+	Storage   StorageConfig   `mapstructure:"storage"`
 }
 
 type ServerConfig struct {
@@ -25,14 +28,37 @@ type AuthConfig struct {
 }
 
 type LoggingConfig struct {
-	Level  string `mapstructure:"level"`
-	Format string `mapstructure:"format"`
+	Level          string `mapstructure:"level"`
+	Format         string `mapstructure:"format"`
+	Privacy        string `mapstructure:"privacy"`
+	TruncateLength int    `mapstructure:"truncate_length"`
 }
 
 type MetricsConfig struct {
 	Enabled bool `mapstructure:"enabled"`
 }
 
+type AnalysisConfig struct {
+	SentimentLexicon      string `mapstructure:"sentiment_lexicon"`
+	SpellDictionaryDir    string `mapstructure:"spell_dictionary_dir"`
+	ModerationWordlistDir string `mapstructure:"moderation_wordlist_dir"`
+	TenantWordlistDir     string `mapstructure:"tenant_wordlist_dir"`
+	POSModel              string `mapstructure:"pos_model"`
+	FingerprintStore      string `mapstructure:"fingerprint_store"`
+	BatchMaxTexts         int    `mapstructure:"batch_max_texts"`
+	BatchWorkers          int    `mapstructure:"batch_workers"`
+}
+
+type RedactionConfig struct {
+	PseudonymSecret string `mapstructure:"pseudonym_secret"`
+	NameList        string `mapstructure:"name_list"`
+}
+
+type StorageConfig struct {
+	Database    string `mapstructure:"database"`
+	SearchIndex string `mapstructure:"search_index"`
+}
+
 func LoadConfig() (*Config, error) {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
@@ -45,13 +71,40 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("auth.jwt_secret", "your-secret-key")
 	viper.SetDefault("logging.level", "info")
 	viper.SetDefault("logging.format", "json")
+	viper.SetDefault("logging.privacy", "length")
+	viper.SetDefault("logging.truncate_length", 16)
 	viper.SetDefault("metrics.enabled", true)
+	viper.SetDefault("analysis.sentiment_lexicon", "")
+	viper.SetDefault("analysis.spell_dictionary_dir", "")
+	viper.SetDefault("analysis.moderation_wordlist_dir", "")
+	viper.SetDefault("analysis.tenant_wordlist_dir", "")
+	viper.SetDefault("analysis.pos_model", "")
+	viper.SetDefault("analysis.fingerprint_store", "")
+	viper.SetDefault("analysis.batch_max_texts", 100)
+	viper.SetDefault("analysis.batch_workers", 4)
+	viper.SetDefault("redaction.pseudonym_secret", "")
+	viper.SetDefault("redaction.name_list", "")
+	viper.SetDefault("storage.database", "")
+	viper.SetDefault("storage.search_index", "")
 
 	viper.AutomaticEnv()
 	_ = viper.BindEnv("server.port", "PORT")
 	_ = viper.BindEnv("auth.jwt_secret", "JWT_SECRET")
 	_ = viper.BindEnv("logging.level", "LOG_LEVEL")
+	_ = viper.BindEnv("logging.privacy", "LOG_PRIVACY")
 	_ = viper.BindEnv("metrics.enabled", "METRICS_ENABLED")
+	_ = viper.BindEnv("analysis.sentiment_lexicon", "SENTIMENT_LEXICON")
+	_ = viper.BindEnv("analysis.spell_dictionary_dir", "SPELL_DICTIONARY_DIR")
+	_ = viper.BindEnv("analysis.moderation_wordlist_dir", "MODERATION_WORDLIST_DIR")
+	_ = viper.BindEnv("analysis.tenant_wordlist_dir", "TENANT_WORDLIST_DIR")
+	_ = viper.BindEnv("analysis.pos_model", "POS_MODEL")
+	_ = viper.BindEnv("analysis.fingerprint_store", "FINGERPRINT_STORE")
+	_ = viper.BindEnv("analysis.batch_max_texts", "BATCH_MAX_TEXTS")
+	_ = viper.BindEnv("analysis.batch_workers", "BATCH_WORKERS")
+	_ = viper.BindEnv("redaction.pseudonym_secret", "REDACTION_PSEUDONYM_SECRET")
+	_ = viper.BindEnv("redaction.name_list", "REDACTION_NAME_LIST")
+	_ = viper.BindEnv("storage.database", "DATABASE_PATH")
+	_ = viper.BindEnv("storage.search_index", "SEARCH_INDEX_PATH")
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
@@ -0,0 +1,16 @@
+package domain
+
+import "context"
+
+type userContextKey struct{}
+
+// WithUser returns a context carrying the authenticated user of a request.
+func WithUser(ctx context.Context, user *User) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
+// UserFromContext returns the authenticated user carried by ctx, if any.
+func UserFromContext(ctx context.Context) (*User, bool) {
+	user, ok := ctx.Value(userContextKey{}).(*User)
+	return user, ok && user != nil
+}
@@ -1,24 +1,588 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type TextAnalysisRequest struct {
-	Sentence string `json:"sentence" binding:"required" example:"Hello world!"`
+	Sentence  string          `json:"sentence" binding:"required" example:"Hello world!"`
+	Language  string          `json:"language,omitempty" example:"en"`
+	Analyses  []string        `json:"analyses,omitempty" example:"counts"`
+	Options   AnalysisOptions `json:"options,omitempty"`
+	Normalize []string        `json:"normalize,omitempty" example:"nfc"`
+	// SkipHistory keeps the analysis out of the history of the user.
+	SkipHistory bool `json:"skip_history,omitempty" example:"false"`
+	// KeepFingerprint stores the fingerprint of the text, so that the
+	// duplicates analyzer of later requests finds it.
+	KeepFingerprint bool `json:"keep_fingerprint,omitempty" example:"true"`
+	// DocumentID is the ID of the stored document the text belongs to, under
+	// which its fingerprint is recorded. It is not read from requests.
+	DocumentID string `json:"-"`
+}
+
+type AnalysisOptions struct {
+	TopN                int     `json:"top_n,omitempty" example:"10"`
+	FilterStopwords     bool    `json:"filter_stopwords,omitempty" example:"true"`
+	Stem                bool    `json:"stem,omitempty" example:"true"`
+	Censor              bool    `json:"censor,omitempty" example:"true"`
+	SimilarityThreshold float64 `json:"similarity_threshold,omitempty" example:"0.8"`
 }
 
 type TextAnalysisResponse struct {
-	Sentence       string `json:"sentence" example:"Hello world!"`
-	WordCount      int    `json:"word_count" example:"2"`
-	VowelCount     int    `json:"vowel_count" example:"3"`
-	ConsonantCount int    `json:"consonant_count" example:"7"`
+	Sentence         string `json:"sentence" example:"Hello world!"`
+	Language         string `json:"language,omitempty" example:"en"`
+	LanguageDetected bool   `json:"language_detected,omitempty" example:"true"`
+	WordCount        int    `json:"word_count" example:"2"`
+	VowelCount       int    `json:"vowel_count" example:"3"`
+	ConsonantCount   int    `json:"consonant_count" example:"7"`
+	OtherLetterCount int    `json:"other_letter_count" example:"0"`
+	DocumentID       string `json:"document_id,omitempty" example:"9f3c2a7be41d08c5"`
+
+	Results map[string]interface{} `json:"results,omitempty"`
+}
+
+type CountsResult struct {
+	WordCount        int `json:"word_count" example:"2"`
+	VowelCount       int `json:"vowel_count" example:"3"`
+	ConsonantCount   int `json:"consonant_count" example:"7"`
+	OtherLetterCount int `json:"other_letter_count" example:"0"`
+}
+
+type ReadabilityResult struct {
+	Sentences         int                `json:"sentences" example:"1"`
+	Words             int                `json:"words" example:"2"`
+	Syllables         int                `json:"syllables" example:"3"`
+	Characters        int                `json:"characters" example:"10"`
+	ComplexWords      int                `json:"complex_words" example:"0"`
+	PolysyllableWords int                `json:"polysyllable_words" example:"0"`
+	Scores            []ReadabilityScore `json:"scores"`
+	GradeLevel        float64            `json:"grade_level" example:"2.5"`
+}
+
+type ReadabilityScore struct {
+	Name       string  `json:"name" example:"flesch_reading_ease"`
+	Score      float64 `json:"score" example:"77.91"`
+	GradeLevel float64 `json:"grade_level" example:"7"`
+	Language   string  `json:"language,omitempty" example:"de"`
+}
+
+type SentencesResult struct {
+	SentenceCount  int                 `json:"sentence_count" example:"2"`
+	AverageLength  float64             `json:"average_length" example:"4.5"`
+	MedianLength   float64             `json:"median_length" example:"4.5"`
+	LongestLength  int                 `json:"longest_length" example:"6"`
+	LongestIndex   int                 `json:"longest_index" example:"0"`
+	ShortestLength int                 `json:"shortest_length" example:"3"`
+	Sentences      []SentenceBreakdown `json:"sentences"`
+}
+
+type SentenceBreakdown struct {
+	Text             string `json:"text" example:"Hello world!"`
+	Start            int    `json:"start" example:"0"`
+	End              int    `json:"end" example:"12"`
+	WordCount        int    `json:"word_count" example:"2"`
+	VowelCount       int    `json:"vowel_count" example:"3"`
+	ConsonantCount   int    `json:"consonant_count" example:"7"`
+	OtherLetterCount int    `json:"other_letter_count" example:"0"`
+}
+
+type FrequencyResult struct {
+	Letters           []FrequencyEntry `json:"letters"`
+	Words             []FrequencyEntry `json:"words"`
+	CharacterBigrams  []FrequencyEntry `json:"character_bigrams"`
+	CharacterTrigrams []FrequencyEntry `json:"character_trigrams"`
+	WordBigrams       []FrequencyEntry `json:"word_bigrams"`
+	WordTrigrams      []FrequencyEntry `json:"word_trigrams"`
+}
+
+type FrequencyEntry struct {
+	Term      string  `json:"term" example:"the"`
+	Count     int     `json:"count" example:"4"`
+	Frequency float64 `json:"frequency" example:"0.125"`
+}
+
+type LexicalResult struct {
+	WordCount           int      `json:"word_count" example:"9"`
+	UniqueWordCount     int      `json:"unique_word_count" example:"7"`
+	TypeTokenRatio      float64  `json:"type_token_ratio" example:"0.7778"`
+	MTLD                float64  `json:"mtld" example:"9"`
+	HDD                 *float64 `json:"hdd,omitempty" example:"0.81"`
+	HapaxLegomena       int      `json:"hapax_legomena" example:"5"`
+	DisLegomena         int      `json:"dis_legomena" example:"2"`
+	AverageWordLength   float64  `json:"average_word_length" example:"3.22"`
+	MaxWordLength       int      `json:"max_word_length" example:"5"`
+	CharacterEntropy    float64  `json:"character_entropy" example:"3.7"`
+	WordEntropy         float64  `json:"word_entropy" example:"2.73"`
+	ReadingTimeSeconds  float64  `json:"reading_time_seconds" example:"2.27"`
+	SpeakingTimeSeconds float64  `json:"speaking_time_seconds" example:"3.6"`
+}
+
+type LanguageResult struct {
+	Language   string              `json:"language,omitempty" example:"en"`
+	Confidence float64             `json:"confidence" example:"0.93"`
+	Candidates []LanguageCandidate `json:"candidates"`
+	Scripts    []ScriptShare       `json:"scripts"`
+}
+
+type LanguageCandidate struct {
+	Language   string  `json:"language" example:"en"`
+	Name       string  `json:"name" example:"English"`
+	Confidence float64 `json:"confidence" example:"0.93"`
+}
+
+type ScriptShare struct {
+	Script     string  `json:"script" example:"Latin"`
+	Letters    int     `json:"letters" example:"10"`
+	Proportion float64 `json:"proportion" example:"1"`
+}
+
+type SentimentResult struct {
+	Compound  float64             `json:"compound" example:"0.6588"`
+	Positive  float64             `json:"positive" example:"0.6"`
+	Negative  float64             `json:"negative" example:"0"`
+	Neutral   float64             `json:"neutral" example:"0.4"`
+	Label     string              `json:"label" example:"positive"`
+	Sentences []SentenceSentiment `json:"sentences"`
+}
+
+type SentenceSentiment struct {
+	Text     string  `json:"text" example:"I love this!"`
+	Start    int     `json:"start" example:"0"`
+	End      int     `json:"end" example:"12"`
+	Compound float64 `json:"compound" example:"0.6588"`
+	Positive float64 `json:"positive" example:"0.6"`
+	Negative float64 `json:"negative" example:"0"`
+	Neutral  float64 `json:"neutral" example:"0.4"`
+	Label    string  `json:"label" example:"positive"`
+}
+
+type KeywordsResult struct {
+	RAKE     []Keyword `json:"rake"`
+	TextRank []Keyword `json:"textrank"`
+	TFIDF    []Keyword `json:"tfidf,omitempty"`
+}
+
+type Keyword struct {
+	Phrase      string     `json:"phrase" example:"payment gateway"`
+	Score       float64    `json:"score" example:"4.5"`
+	Occurrences []TextSpan `json:"occurrences"`
+}
+
+type EntitiesResult struct {
+	Entities []Entity `json:"entities"`
+}
+
+// Entity is a structured value found in the text. Normalized holds it in a
+// canonical form: lowercase emails and hosts, E.164 phone numbers, ISO-8601
+// dates and times, an ISO 4217 code followed by the amount for money.
+type Entity struct {
+	Type       string `json:"type" example:"phone"`
+	Text       string `json:"text" example:"(555) 123-4567"`
+	Normalized string `json:"normalized" example:"+15551234567"`
+	Start      int    `json:"start" example:"8"`
+	End        int    `json:"end" example:"22"`
+}
+
+// TextSpan locates text in the analyzed sentence by Unicode code point
+// offsets. End is exclusive.
+type TextSpan struct {
+	Start int `json:"start" example:"4"`
+	End   int `json:"end" example:"19"`
+}
+
+type AnalyzerInfo struct {
+	Name        string `json:"name" example:"counts"`
+	Version     string `json:"version" example:"1.1.0"`
+	Description string `json:"description" example:"Word, vowel, consonant and other letter counts"`
+}
+
+type AnalyzersResponse struct {
+	Analyzers []AnalyzerInfo `json:"analyzers"`
+}
+
+type StemRequest struct {
+	Text     string `json:"text" binding:"required" example:"The runners were running"`
+	Language string `json:"language,omitempty" example:"en"`
+	Mode     string `json:"mode,omitempty" example:"stem"`
+}
+
+type StemResponse struct {
+	Language         string         `json:"language" example:"en"`
+	LanguageDetected bool           `json:"language_detected,omitempty" example:"true"`
+	Mode             string         `json:"mode" example:"stem"`
+	Tokens           []StemmedToken `json:"tokens"`
+}
+
+// StemmedToken is a word of the text with its stem or lemma, depending on the
+// requested mode.
+type StemmedToken struct {
+	Text  string `json:"text" example:"running"`
+	Form  string `json:"form" example:"run"`
+	Start int    `json:"start" example:"16"`
+	End   int    `json:"end" example:"23"`
+}
+
+type RedactionRequest struct {
+	Text     string   `json:"text" binding:"required" example:"Mail jane@example.com or call +44 20 7946 0958"`
+	Language string   `json:"language,omitempty" example:"en"`
+	Strategy string   `json:"strategy,omitempty" example:"type"`
+	Types    []string `json:"types,omitempty" example:"email"`
+}
+
+type RedactionResponse struct {
+	Text       string      `json:"text" example:"Mail [EMAIL] or call [PHONE]"`
+	Redactions []Redaction `json:"redactions"`
+}
+
+// Redaction locates personal data in the submitted text by Unicode code
+// point offsets, with the text that replaced it. End is exclusive.
+type Redaction struct {
+	Type        string `json:"type" example:"email"`
+	Start       int    `json:"start" example:"5"`
+	End         int    `json:"end" example:"21"`
+	Replacement string `json:"replacement" example:"[EMAIL]"`
+}
+
+type SpellResult struct {
+	Language     string        `json:"language" example:"en"`
+	Misspellings []Misspelling `json:"misspellings"`
+}
+
+// Misspelling is a word missing from the dictionary of the language and from
+// the custom dictionary of the user, with suggested corrections, closest
+// first. Offsets are in Unicode code points; End is exclusive.
+type Misspelling struct {
+	Text        string   `json:"text" example:"recieve"`
+	Start       int      `json:"start" example:"4"`
+	End         int      `json:"end" example:"11"`
+	Suggestions []string `json:"suggestions" example:"receive"`
+}
+
+// ModerationResult lists the blocked terms found in the text. Severity is the
+// highest severity of the matches, or none.
+type ModerationResult struct {
+	Flagged    bool              `json:"flagged" example:"true"`
+	Severity   string            `json:"severity" example:"moderate"`
+	Categories []string          `json:"categories" example:"profanity"`
+	Matches    []ModerationMatch `json:"matches"`
+	Censored   string            `json:"censored,omitempty" example:"What a **** day"`
+}
+
+// ModerationMatch is an occurrence of a blocked term, possibly obfuscated.
+// Offsets are in Unicode code points; End is exclusive.
+type ModerationMatch struct {
+	Text     string `json:"text" example:"sh1t"`
+	Term     string `json:"term" example:"shit"`
+	Category string `json:"category" example:"profanity"`
+	Severity string `json:"severity" example:"moderate"`
+	Start    int    `json:"start" example:"7"`
+	End      int    `json:"end" example:"11"`
+}
+
+// POSResult tags every word with its Universal Dependencies part of speech.
+// Nouns include proper nouns and verbs exclude auxiliaries. Lexical density is
+// the share of words that are nouns, verbs, adjectives or adverbs.
+type POSResult struct {
+	Tokens         []TaggedToken  `json:"tokens"`
+	TagCounts      map[string]int `json:"tag_counts"`
+	Nouns          int            `json:"nouns" example:"3"`
+	Verbs          int            `json:"verbs" example:"2"`
+	Adjectives     int            `json:"adjectives" example:"1"`
+	Adverbs        int            `json:"adverbs" example:"1"`
+	NounVerbRatio  float64        `json:"noun_verb_ratio" example:"1.5"`
+	LexicalDensity float64        `json:"lexical_density" example:"0.5833"`
+}
+
+// TaggedToken is a word of the text with its part-of-speech tag. Offsets are
+// in Unicode code points; End is exclusive.
+type TaggedToken struct {
+	Text  string `json:"text" example:"jumps"`
+	Tag   string `json:"tag" example:"VERB"`
+	Start int    `json:"start" example:"16"`
+	End   int    `json:"end" example:"21"`
+}
+
+// DuplicatesResult lists the previously analyzed texts of the tenant that are
+// near-duplicates of the text, most similar first.
+type DuplicatesResult struct {
+	Matches []DuplicateMatch `json:"matches"`
+}
+
+// DuplicateMatch is a previously analyzed text with the estimated Jaccard
+// similarity of its word shingles to the analyzed text, and the number of
+// differing bits of their SimHash fingerprints.
+type DuplicateMatch struct {
+	DocumentID      string    `json:"document_id" example:"9f3c2a7be41d08c5"`
+	Similarity      float64   `json:"similarity" example:"0.93"`
+	SimHashDistance int       `json:"simhash_distance" example:"2"`
+	SubmittedAt     time.Time `json:"submitted_at" example:"2024-05-01T12:00:00Z"`
+}
+
+type DictionaryRequest struct {
+	Words []string `json:"words" binding:"required" example:"Kubernetes"`
+}
+
+type DictionaryResponse struct {
+	Words []string `json:"words" example:"Kubernetes"`
+}
+
+type NormalizeRequest struct {
+	Text     string   `json:"text" binding:"required" example:"Don’t  say “café”"`
+	Language string   `json:"language,omitempty" example:"en"`
+	Steps    []string `json:"steps,omitempty" example:"nfc"`
+}
+
+type NormalizeResponse struct {
+	Text  string   `json:"text" example:"Do not say \"café\""`
+	Steps []string `json:"steps" example:"nfc"`
+}
+
+type SummarizeRequest struct {
+	Text      string  `json:"text" binding:"required" example:"The service was down for two hours. Engineers restored it by noon. Customers were refunded."`
+	Language  string  `json:"language,omitempty" example:"en"`
+	Sentences int     `json:"sentences,omitempty" example:"2"`
+	Ratio     float64 `json:"ratio,omitempty" example:"0.2"`
+	Algorithm string  `json:"algorithm,omitempty" example:"textrank"`
+}
+
+type SummarizeResponse struct {
+	Language         string            `json:"language,omitempty" example:"en"`
+	LanguageDetected bool              `json:"language_detected,omitempty" example:"true"`
+	Algorithm        string            `json:"algorithm" example:"textrank"`
+	SentenceCount    int               `json:"sentence_count" example:"3"`
+	Summary          string            `json:"summary" example:"The service was down for two hours. Customers were refunded."`
+	Sentences        []SummarySentence `json:"sentences"`
+}
+
+// SummarySentence is a sentence selected for the summary, in text order.
+// Offsets are in Unicode code points; End is exclusive.
+type SummarySentence struct {
+	Index int     `json:"index" example:"0"`
+	Text  string  `json:"text" example:"The service was down for two hours."`
+	Start int     `json:"start" example:"0"`
+	End   int     `json:"end" example:"35"`
+	Score float64 `json:"score" example:"1.1234"`
+}
+
+type CompareRequest struct {
+	Texts       []string `json:"texts" binding:"required" example:"The quick brown fox,The quick red fox"`
+	ShingleSize int      `json:"shingle_size,omitempty" example:"2"`
+}
+
+type CompareResponse struct {
+	Comparisons []TextComparison `json:"comparisons"`
+}
+
+// TextComparison compares the texts at indexes A and B of the request. Word
+// level metrics ignore case, punctuation and whitespace.
+type TextComparison struct {
+	A           int          `json:"a" example:"0"`
+	B           int          `json:"b" example:"1"`
+	Levenshtein LevelMetric  `json:"levenshtein"`
+	Damerau     LevelMetric  `json:"damerau"`
+	LCS         LevelMetric  `json:"lcs"`
+	Jaccard     float64      `json:"jaccard" example:"0.333"`
+	Cosine      float64      `json:"cosine" example:"0.75"`
+	Diff        []DiffChange `json:"diff"`
+}
+
+// LevelMetric is a metric measured on characters and on words.
+type LevelMetric struct {
+	Characters int `json:"characters" example:"4"`
+	Words      int `json:"words" example:"1"`
+}
+
+// DiffChange is a run of words equal in both texts, deleted from the first
+// or inserted by the second, spelled as in the text holding them.
+type DiffChange struct {
+	Op   string `json:"op" example:"equal"`
+	Text string `json:"text" example:"The quick"`
 }
 
 type User struct {
 	ID       string `json:"id"`
 	Username string `json:"username"`
+	TenantID string `json:"tenant_id,omitempty"`
 	Password string `json:"-"`
 }
 
+type DuplicatesResponse struct {
+	Threshold float64            `json:"threshold" example:"0.8"`
+	Clusters  []DuplicateCluster `json:"clusters"`
+}
+
+// DuplicateCluster is a group of analyzed texts linked by near-duplicate
+// pairs. Similarity is the lowest similarity of the pairs linking them.
+type DuplicateCluster struct {
+	Similarity float64             `json:"similarity" example:"0.86"`
+	Documents  []DuplicateDocument `json:"documents"`
+}
+
+type DuplicateDocument struct {
+	DocumentID  string    `json:"document_id" example:"9f3c2a7be41d08c5"`
+	UserID      string    `json:"user_id" example:"1"`
+	SubmittedAt time.Time `json:"submitted_at" example:"2024-05-01T12:00:00Z"`
+}
+
+// Fingerprint is the SimHash and MinHash signature of an analyzed text,
+// retained instead of the text to find its near-duplicates.
+type Fingerprint struct {
+	DocumentID  string    `json:"document_id"`
+	TenantID    string    `json:"tenant_id"`
+	UserID      string    `json:"user_id"`
+	SimHash     uint64    `json:"simhash"`
+	MinHash     []uint32  `json:"minhash"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+type DocumentRequest struct {
+	Title    string          `json:"title,omitempty" example:"Q2 report"`
+	Text     string          `json:"text" binding:"required" example:"Revenue grew by twelve percent."`
+	Language string          `json:"language,omitempty" example:"en"`
+	Analyses []string        `json:"analyses,omitempty" example:"counts"`
+	Options  AnalysisOptions `json:"options,omitempty"`
+}
+
+// Document is a text stored by a user with the result of its analysis, run
+// again whenever the document is updated.
+type Document struct {
+	ID        string                `json:"id" example:"4b1e9c0d27a8f356"`
+	UserID    string                `json:"-"`
+	Title     string                `json:"title,omitempty" example:"Q2 report"`
+	Text      string                `json:"text" example:"Revenue grew by twelve percent."`
+	Language  string                `json:"language,omitempty" example:"en"`
+	Analyses  []string              `json:"analyses,omitempty" example:"counts"`
+	Options   AnalysisOptions       `json:"options"`
+	Analysis  *TextAnalysisResponse `json:"analysis"`
+	CreatedAt time.Time             `json:"created_at" example:"2024-05-01T12:00:00Z"`
+	UpdatedAt time.Time             `json:"updated_at" example:"2024-05-01T12:00:00Z"`
+}
+
+type DocumentListResponse struct {
+	Documents []DocumentSummary `json:"documents"`
+	Total     int               `json:"total" example:"1"`
+}
+
+// DocumentSummary describes a stored document without its text and analysis.
+type DocumentSummary struct {
+	ID        string    `json:"id" example:"4b1e9c0d27a8f356"`
+	Title     string    `json:"title,omitempty" example:"Q2 report"`
+	Language  string    `json:"language,omitempty" example:"en"`
+	WordCount int       `json:"word_count" example:"5"`
+	CreatedAt time.Time `json:"created_at" example:"2024-05-01T12:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at" example:"2024-05-01T12:00:00Z"`
+}
+
+// BatchAnalysisRequest analyzes several texts with the same settings, as
+// many requests to POST /api/v1/analyze would.
+type BatchAnalysisRequest struct {
+	Texts       []string        `json:"texts" binding:"required" example:"Hello world!"`
+	Language    string          `json:"language,omitempty" example:"en"`
+	Analyses    []string        `json:"analyses,omitempty" example:"counts"`
+	Options     AnalysisOptions `json:"options,omitempty"`
+	Normalize   []string        `json:"normalize,omitempty" example:"nfc"`
+	SkipHistory bool            `json:"skip_history,omitempty" example:"false"`
+	// KeepFingerprint stores the fingerprint of every text, as in
+	// TextAnalysisRequest.
+	KeepFingerprint bool `json:"keep_fingerprint,omitempty" example:"true"`
+}
+
+type BatchAnalysisResponse struct {
+	Results   []BatchAnalysisResult `json:"results"`
+	Succeeded int                   `json:"succeeded" example:"1"`
+	Failed    int                   `json:"failed" example:"0"`
+}
+
+// BatchAnalysisResult is the analysis of a text of a batch, or the error
+// that prevented it, in the position of the text in the request.
+type BatchAnalysisResult struct {
+	Index  int                   `json:"index" example:"0"`
+	Result *TextAnalysisResponse `json:"result,omitempty"`
+	Error  *ErrorResponse        `json:"error,omitempty"`
+}
+
+// HistoryEntry is an analysis a user ran, with its request and response.
+type HistoryEntry struct {
+	ID        string                `json:"id" example:"9f2c4e1a0b7d5836"`
+	UserID    string                `json:"-"`
+	Request   TextAnalysisRequest   `json:"request"`
+	Analysis  *TextAnalysisResponse `json:"analysis"`
+	CreatedAt time.Time             `json:"created_at" example:"2024-05-01T12:00:00Z"`
+}
+
+// HistoryRequest selects history entries. From and To are RFC 3339 times or
+// dates, To being exclusive and a date covering the whole day. Filters are
+// metric thresholds such as word_count>100.
+type HistoryRequest struct {
+	Cursor  string   `form:"cursor"`
+	From    string   `form:"from" example:"2024-05-01"`
+	To      string   `form:"to" example:"2024-05-07"`
+	Filters []string `form:"filter" example:"word_count>100"`
+	Limit   int      `form:"limit" example:"20"`
+}
+
+type HistoryResponse struct {
+	Entries []*HistoryEntry `json:"entries"`
+	// NextCursor fetches the following entries, when there are any.
+	NextCursor string `json:"next_cursor,omitempty" example:"MTcxNDU2NDgwMDAwMDAwMDAwMDo5ZjJjNGUxYTBiN2Q1ODM2"`
+}
+
+// HistoryMetrics are the metrics history entries can be filtered by.
+var HistoryMetrics = []string{"word_count", "vowel_count", "consonant_count", "other_letter_count"}
+
+// MetricFilter keeps the entries whose metric compares to Value with
+// Operator, one of =, !=, <, <=, > and >=.
+type MetricFilter struct {
+	Metric   string
+	Operator string
+	Value    float64
+}
+
+// HistoryCursor is the position of the last entry of a page of history.
+type HistoryCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// HistoryQuery selects the entries of a user created in [From, To), zero
+// times leaving the range open, that pass all filters. When After is set,
+// only the entries listed after its position are selected.
+type HistoryQuery struct {
+	From    time.Time
+	To      time.Time
+	Filters []MetricFilter
+	After   *HistoryCursor
+	Limit   int
+}
+
+type SearchRequest struct {
+	Query string `form:"q" binding:"required" example:"\"quarterly report\" AND revenue*"`
+	Limit int    `form:"limit" example:"10"`
+}
+
+type SearchResponse struct {
+	Query   string         `json:"query" example:"\"quarterly report\" AND revenue*"`
+	Total   int            `json:"total" example:"1"`
+	Results []SearchResult `json:"results"`
+}
+
+// SearchResult is a stored document matching a query with its BM25 score
+// and a passage of its text. Highlights locate the matched words in the
+// snippet by Unicode code point offsets; End is exclusive.
+type SearchResult struct {
+	DocumentID string      `json:"document_id" example:"4b1e9c0d27a8f356"`
+	Title      string      `json:"title,omitempty" example:"Q2 report"`
+	Score      float64     `json:"score" example:"1.7512"`
+	Snippet    string      `json:"snippet" example:"Our quarterly report shows that revenue grew"`
+	Highlights []Highlight `json:"highlights"`
+}
+
+type Highlight struct {
+	Start int `json:"start" example:"4"`
+	End   int `json:"end" example:"13"`
+}
+
 type LoginRequest struct {
 	Username string `json:"username" binding:"required" example:"admin"`
 	Password string `json:"password" binding:"required" example:"password"`
@@ -30,7 +594,59 @@ type LoginResponse struct {
 }
 
 type TextAnalysisService interface {
-	AnalyzeText(ctx context.Context, sentence string) (*TextAnalysisResponse, error)
+	AnalyzeText(ctx context.Context, req *TextAnalysisRequest) (*TextAnalysisResponse, error)
+	ListAnalyzers(ctx context.Context) []AnalyzerInfo
+}
+
+type MorphologyService interface {
+	Stem(ctx context.Context, req *StemRequest) (*StemResponse, error)
+}
+
+type RedactionService interface {
+	Redact(ctx context.Context, req *RedactionRequest) (*RedactionResponse, error)
+}
+
+type DictionaryService interface {
+	Words(ctx context.Context) (*DictionaryResponse, error)
+	AddWords(ctx context.Context, req *DictionaryRequest) (*DictionaryResponse, error)
+	RemoveWord(ctx context.Context, word string) (*DictionaryResponse, error)
+}
+
+type NormalizationService interface {
+	Normalize(ctx context.Context, req *NormalizeRequest) (*NormalizeResponse, error)
+}
+
+type SummarizationService interface {
+	Summarize(ctx context.Context, req *SummarizeRequest) (*SummarizeResponse, error)
+}
+
+type DocumentService interface {
+	Create(ctx context.Context, req *DocumentRequest) (*Document, error)
+	Get(ctx context.Context, id string) (*Document, error)
+	List(ctx context.Context, limit, offset int) (*DocumentListResponse, error)
+	Update(ctx context.Context, id string, req *DocumentRequest) (*Document, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type BatchAnalysisService interface {
+	AnalyzeBatch(ctx context.Context, req *BatchAnalysisRequest) (*BatchAnalysisResponse, error)
+}
+
+type HistoryService interface {
+	Record(ctx context.Context, req *TextAnalysisRequest, response *TextAnalysisResponse) error
+	List(ctx context.Context, req *HistoryRequest) (*HistoryResponse, error)
+}
+
+type SearchService interface {
+	Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error)
+}
+
+type DuplicatesService interface {
+	Clusters(ctx context.Context, threshold float64) (*DuplicatesResponse, error)
+}
+
+type ComparisonService interface {
+	Compare(ctx context.Context, req *CompareRequest) (*CompareResponse, error)
 }
 
 type AuthService interface {
@@ -41,3 +657,44 @@ type AuthService interface {
 type UserRepository interface {
 	GetByUsername(ctx context.Context, username string) (*User, error)
 }
+
+// DictionaryRepository stores the words each user accepts as correctly
+// spelled.
+type DictionaryRepository interface {
+	Words(ctx context.Context, userID string) ([]string, error)
+	AddWords(ctx context.Context, userID string, words []string) error
+	RemoveWord(ctx context.Context, userID string, word string) error
+}
+
+// DocumentRepository stores the documents of each user. Documents of other
+// users are reported as not found.
+type DocumentRepository interface {
+	Create(ctx context.Context, document *Document) error
+	Get(ctx context.Context, userID, id string) (*Document, error)
+	// List returns the documents of a user, most recently created first,
+	// with the total number of documents of the user.
+	List(ctx context.Context, userID string, limit, offset int) ([]*Document, int, error)
+	Update(ctx context.Context, document *Document) error
+	Delete(ctx context.Context, userID, id string) error
+	// Walk calls fn with every stored document of every user, stopping at
+	// the first error fn returns.
+	Walk(ctx context.Context, fn func(*Document) error) error
+}
+
+// HistoryRepository stores the analyses each user ran.
+type HistoryRepository interface {
+	Add(ctx context.Context, entry *HistoryEntry) error
+	// List returns the entries of a user matching a query, most recent
+	// first, ties broken by descending ID.
+	List(ctx context.Context, userID string, query HistoryQuery) ([]*HistoryEntry, error)
+}
+
+// FingerprintRepository stores the fingerprints of analyzed texts by tenant,
+// in submission order, with at most one fingerprint per document.
+type FingerprintRepository interface {
+	// Add stores a fingerprint, replacing the previous fingerprint of its
+	// document.
+	Add(ctx context.Context, fingerprint *Fingerprint) error
+	Remove(ctx context.Context, tenantID, documentID string) error
+	List(ctx context.Context, tenantID string) ([]*Fingerprint, error)
+}
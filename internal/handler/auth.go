@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"vm-chan/internal/domain"
+	"vm-chan/internal/logging"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -35,7 +36,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	response, err := h.service.Login(c.Request.Context(), req.Username, req.Password)
 	if err != nil {
-		h.logger.Error("Login failed", zap.String("username", req.Username), zap.Error(err))
+		h.logger.Error("Login failed", logging.Sensitive("username", req.Username), zap.Error(err))
 		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{
 			Error:       "Invalid credentials",
 			Code:        "authentication_failed",
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"vm-chan/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type BatchAnalysisHandler struct {
+	service domain.BatchAnalysisService
+	logger  *zap.Logger
+}
+
+func NewBatchAnalysisHandler(service domain.BatchAnalysisService, logger *zap.Logger) *BatchAnalysisHandler {
+	return &BatchAnalysisHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *BatchAnalysisHandler) AnalyzeBatch(c *gin.Context) {
+	serveJSON(c, h.logger, h.service.AnalyzeBatch, "batch", "analyze batch")
+}
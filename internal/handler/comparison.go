@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"vm-chan/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ComparisonHandler struct {
+	service domain.ComparisonService
+	logger  *zap.Logger
+}
+
+func NewComparisonHandler(service domain.ComparisonService, logger *zap.Logger) *ComparisonHandler {
+	return &ComparisonHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *ComparisonHandler) Compare(c *gin.Context) {
+	serveJSON(c, h.logger, h.service.Compare, "comparison", "compare texts")
+}
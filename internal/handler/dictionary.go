@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"vm-chan/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type DictionaryHandler struct {
+	service domain.DictionaryService
+	logger  *zap.Logger
+}
+
+func NewDictionaryHandler(service domain.DictionaryService, logger *zap.Logger) *DictionaryHandler {
+	return &DictionaryHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *DictionaryHandler) Words(c *gin.Context) {
+	result, err := h.service.Words(c.Request.Context())
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *DictionaryHandler) AddWords(c *gin.Context) {
+	var req domain.DictionaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid dictionary request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
+			Error:       "Invalid request format",
+			Code:        "validation_error",
+			Description: "The request body does not match the expected format",
+		})
+		return
+	}
+
+	result, err := h.service.AddWords(c.Request.Context(), &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *DictionaryHandler) RemoveWord(c *gin.Context) {
+	result, err := h.service.RemoveWord(c.Request.Context(), c.Param("word"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *DictionaryHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
+			Error:       "Invalid dictionary request",
+			Code:        "validation_error",
+			Description: err.Error(),
+		})
+	case errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusNotFound, domain.ErrorResponse{
+			Error:       "Word not found",
+			Code:        "not_found",
+			Description: err.Error(),
+		})
+	default:
+		h.logger.Error("Failed to update custom dictionary", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{
+			Error:       "Failed to update custom dictionary",
+			Code:        "internal_error",
+			Description: "An unexpected error occurred while processing your request",
+		})
+	}
+}
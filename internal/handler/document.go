@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"vm-chan/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type DocumentHandler struct {
+	service domain.DocumentService
+	logger  *zap.Logger
+}
+
+func NewDocumentHandler(service domain.DocumentService, logger *zap.Logger) *DocumentHandler {
+	return &DocumentHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *DocumentHandler) Create(c *gin.Context) {
+	var req domain.DocumentRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	result, err := h.service.Create(c.Request.Context(), &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+func (h *DocumentHandler) List(c *gin.Context) {
+	var limit, offset int
+	for name, value := range map[string]*int{"limit": &limit, "offset": &offset} {
+		if query := c.Query(name); query != "" {
+			parsed, err := strconv.Atoi(query)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, domain.ErrorResponse{
+					Error:       "Invalid request format",
+					Code:        "validation_error",
+					Description: "The " + name + " query parameter must be an integer",
+				})
+				return
+			}
+			*value = parsed
+		}
+	}
+
+	result, err := h.service.List(c.Request.Context(), limit, offset)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *DocumentHandler) Get(c *gin.Context) {
+	result, err := h.service.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *DocumentHandler) Update(c *gin.Context) {
+	var req domain.DocumentRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	result, err := h.service.Update(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *DocumentHandler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *DocumentHandler) bind(c *gin.Context, req *domain.DocumentRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		h.logger.Error("Invalid document request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
+			Error:       "Invalid request format",
+			Code:        "validation_error",
+			Description: "The request body does not match the expected format",
+		})
+		return false
+	}
+	return true
+}
+
+func (h *DocumentHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
+			Error:       "Invalid document request",
+			Code:        "validation_error",
+			Description: err.Error(),
+		})
+	case errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusNotFound, domain.ErrorResponse{
+			Error:       "Document not found",
+			Code:        "not_found",
+			Description: err.Error(),
+		})
+	default:
+		h.logger.Error("Failed to process document request", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{
+			Error:       "Failed to process document request",
+			Code:        "internal_error",
+			Description: "An unexpected error occurred while processing your request",
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"vm-chan/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type DuplicatesHandler struct {
+	service domain.DuplicatesService
+	logger  *zap.Logger
+}
+
+func NewDuplicatesHandler(service domain.DuplicatesService, logger *zap.Logger) *DuplicatesHandler {
+	return &DuplicatesHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *DuplicatesHandler) Clusters(c *gin.Context) {
+	var threshold float64
+	if value := c.Query("threshold"); value != "" {
+		var err error
+		if threshold, err = strconv.ParseFloat(value, 64); err != nil {
+			c.JSON(http.StatusBadRequest, domain.ErrorResponse{
+				Error:       "Invalid request format",
+				Code:        "validation_error",
+				Description: "The threshold query parameter must be a number",
+			})
+			return
+		}
+	}
+
+	result, err := h.service.Clusters(c.Request.Context(), threshold)
+	if errors.Is(err, domain.ErrInvalidInput) {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
+			Error:       "Invalid duplicates request",
+			Code:        "validation_error",
+			Description: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to list duplicates", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{
+			Error:       "Failed to list duplicates",
+			Code:        "internal_error",
+			Description: "An unexpected error occurred while processing your request",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
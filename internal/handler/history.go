@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"vm-chan/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type HistoryHandler struct {
+	service domain.HistoryService
+	logger  *zap.Logger
+}
+
+func NewHistoryHandler(service domain.HistoryService, logger *zap.Logger) *HistoryHandler {
+	return &HistoryHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *HistoryHandler) List(c *gin.Context) {
+	serveQuery(c, h.logger, h.service.List, "history", "list history",
+		"The limit query parameter must be an integer")
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"vm-chan/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubHistoryService struct {
+	req *domain.HistoryRequest
+}
+
+func (s *stubHistoryService) Record(context.Context, *domain.TextAnalysisRequest, *domain.TextAnalysisResponse) error {
+	return nil
+}
+
+func (s *stubHistoryService) List(_ context.Context, req *domain.HistoryRequest) (*domain.HistoryResponse, error) {
+	s.req = req
+	return &domain.HistoryResponse{Entries: []*domain.HistoryEntry{}}, nil
+}
+
+func TestHistoryHandler(t *testing.T) {
+	service := &stubHistoryService{}
+	h := NewHistoryHandler(service, zap.NewNop())
+
+	t.Run("Without parameters", func(t *testing.T) {
+		recorder := serveRequest(http.MethodGet, "/history", "", h.List)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, &domain.HistoryRequest{}, service.req)
+	})
+
+	t.Run("Query parameters", func(t *testing.T) {
+		recorder := serveRequest(http.MethodGet,
+			"/history?limit=5&from=2024-05-01&filter=word_count>100&filter=language=en", "", h.List)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, &domain.HistoryRequest{
+			From:    "2024-05-01",
+			Filters: []string{"word_count>100", "language=en"},
+			Limit:   5,
+		}, service.req)
+	})
+
+	t.Run("Invalid limit", func(t *testing.T) {
+		recorder := serveRequest(http.MethodGet, "/history?limit=five", "", h.List)
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Equal(t, "The limit query parameter must be an integer", decodeError(t, recorder).Description)
+	})
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"vm-chan/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// serveJSON binds the JSON body of a request, passes it to a service call and
+// writes its result. Validation errors of the service are answered with 400
+// and "Invalid <subject> request", any other error with 500 and
+// "Failed to <action>".
+func serveJSON[Req, Resp any](c *gin.Context, logger *zap.Logger,
+	call func(context.Context, *Req) (Resp, error), subject, action string) {
+	var req Req
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rejectFormat(c, logger, subject, err, "The request body does not match the expected format")
+		return
+	}
+	respond(c, logger, call, &req, subject, action)
+}
+
+// serveQuery is serveJSON for requests read from the query parameters.
+// description tells the client which parameters are expected when they do
+// not bind.
+func serveQuery[Req, Resp any](c *gin.Context, logger *zap.Logger,
+	call func(context.Context, *Req) (Resp, error), subject, action, description string) {
+	var req Req
+	if err := c.ShouldBindQuery(&req); err != nil {
+		rejectFormat(c, logger, subject, err, description)
+		return
+	}
+	respond(c, logger, call, &req, subject, action)
+}
+
+func rejectFormat(c *gin.Context, logger *zap.Logger, subject string, err error, description string) {
+	logger.Error("Invalid "+subject+" request", zap.Error(err))
+	c.JSON(http.StatusBadRequest, domain.ErrorResponse{
+		Error:       "Invalid request format",
+		Code:        "validation_error",
+		Description: description,
+	})
+}
+
+func respond[Req, Resp any](c *gin.Context, logger *zap.Logger,
+	call func(context.Context, *Req) (Resp, error), req *Req, subject, action string) {
+	result, err := call(c.Request.Context(), req)
+	if errors.Is(err, domain.ErrInvalidInput) {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
+			Error:       "Invalid " + subject + " request",
+			Code:        "validation_error",
+			Description: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to "+action, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{
+			Error:       "Failed to " + action,
+			Code:        "internal_error",
+			Description: "An unexpected error occurred while processing your request",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
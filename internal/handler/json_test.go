@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"vm-chan/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// serveRequest routes a request to a handler and returns the response.
+func serveRequest(method, path, body string, handle gin.HandlerFunc) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Handle(method, strings.SplitN(path, "?", 2)[0], handle)
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func decodeError(t *testing.T, recorder *httptest.ResponseRecorder) domain.ErrorResponse {
+	var response domain.ErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	return response
+}
+
+type stubMorphologyService struct {
+	err error
+}
+
+func (s *stubMorphologyService) Stem(_ context.Context, req *domain.StemRequest) (*domain.StemResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &domain.StemResponse{Language: req.Language}, nil
+}
+
+func TestServeJSON(t *testing.T) {
+	stem := func(err error, body string) *httptest.ResponseRecorder {
+		h := NewMorphologyHandler(&stubMorphologyService{err: err}, zap.NewNop())
+		return serveRequest(http.MethodPost, "/stem", body, h.Stem)
+	}
+
+	t.Run("Result", func(t *testing.T) {
+		recorder := stem(nil, `{"text": "running", "language": "en"}`)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var response domain.StemResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		assert.Equal(t, "en", response.Language)
+	})
+
+	t.Run("Malformed body", func(t *testing.T) {
+		for _, body := range []string{`{"text": `, `{"language": "en"}`} {
+			recorder := stem(nil, body)
+			require.Equal(t, http.StatusBadRequest, recorder.Code, body)
+			assert.Equal(t, "Invalid request format", decodeError(t, recorder).Error, body)
+		}
+	})
+
+	t.Run("Invalid input", func(t *testing.T) {
+		recorder := stem(fmt.Errorf("%w: unknown mode", domain.ErrInvalidInput), `{"text": "running"}`)
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+		response := decodeError(t, recorder)
+		assert.Equal(t, "Invalid stem request", response.Error)
+		assert.Equal(t, "validation_error", response.Code)
+		assert.Contains(t, response.Description, "unknown mode")
+	})
+
+	t.Run("Internal error", func(t *testing.T) {
+		recorder := stem(errors.New("disk full"), `{"text": "running"}`)
+		require.Equal(t, http.StatusInternalServerError, recorder.Code)
+		response := decodeError(t, recorder)
+		assert.Equal(t, "Failed to stem text", response.Error)
+		assert.NotContains(t, response.Description, "disk full")
+	})
+}
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"vm-chan/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type MorphologyHandler struct {
+	service domain.MorphologyService
+	logger  *zap.Logger
+}
+
+func NewMorphologyHandler(service domain.MorphologyService, logger *zap.Logger) *MorphologyHandler {
+	return &MorphologyHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *MorphologyHandler) Stem(c *gin.Context) {
+	serveJSON(c, h.logger, h.service.Stem, "stem", "stem text")
+}
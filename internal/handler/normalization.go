@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"vm-chan/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type NormalizationHandler struct {
+	service domain.NormalizationService
+	logger  *zap.Logger
+}
+
+func NewNormalizationHandler(service domain.NormalizationService, logger *zap.Logger) *NormalizationHandler {
+	return &NormalizationHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *NormalizationHandler) Normalize(c *gin.Context) {
+	serveJSON(c, h.logger, h.service.Normalize, "normalization", "normalize text")
+}
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"vm-chan/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type RedactionHandler struct {
+	service domain.RedactionService
+	logger  *zap.Logger
+}
+
+func NewRedactionHandler(service domain.RedactionService, logger *zap.Logger) *RedactionHandler {
+	return &RedactionHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *RedactionHandler) Redact(c *gin.Context) {
+	serveJSON(c, h.logger, h.service.Redact, "redaction", "redact text")
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"vm-chan/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type SearchHandler struct {
+	service domain.SearchService
+	logger  *zap.Logger
+}
+
+func NewSearchHandler(service domain.SearchService, logger *zap.Logger) *SearchHandler {
+	return &SearchHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *SearchHandler) Search(c *gin.Context) {
+	serveQuery(c, h.logger, h.service.Search, "search", "search documents",
+		"The q query parameter is required and limit must be an integer")
+}
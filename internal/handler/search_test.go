@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"vm-chan/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubSearchService struct {
+	req *domain.SearchRequest
+}
+
+func (s *stubSearchService) Search(_ context.Context, req *domain.SearchRequest) (*domain.SearchResponse, error) {
+	s.req = req
+	return &domain.SearchResponse{Query: req.Query}, nil
+}
+
+func TestSearchHandler(t *testing.T) {
+	service := &stubSearchService{}
+	h := NewSearchHandler(service, zap.NewNop())
+
+	t.Run("Query parameters", func(t *testing.T) {
+		recorder := serveRequest(http.MethodGet, "/search?q=hello+world&limit=5", "", h.Search)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, &domain.SearchRequest{Query: "hello world", Limit: 5}, service.req)
+		var response domain.SearchResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		assert.Equal(t, "hello world", response.Query)
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		for _, path := range []string{"/search", "/search?q=hello&limit=ten"} {
+			recorder := serveRequest(http.MethodGet, path, "", h.Search)
+			require.Equal(t, http.StatusBadRequest, recorder.Code, path)
+			assert.Contains(t, decodeError(t, recorder).Description, "query parameter", path)
+		}
+	})
+}
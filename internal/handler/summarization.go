@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"vm-chan/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type SummarizationHandler struct {
+	service domain.SummarizationService
+	logger  *zap.Logger
+}
+
+func NewSummarizationHandler(service domain.SummarizationService, logger *zap.Logger) *SummarizationHandler {
+	return &SummarizationHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *SummarizationHandler) Summarize(c *gin.Context) {
+	serveJSON(c, h.logger, h.service.Summarize, "summarization", "summarize text")
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"vm-chan/internal/domain"
@@ -11,12 +12,14 @@ import (
 
 type TextAnalysisHandler struct {
 	service domain.TextAnalysisService
+	history domain.HistoryService
 	logger  *zap.Logger
 }
 
-func NewTextAnalysisHandler(service domain.TextAnalysisService, logger *zap.Logger) *TextAnalysisHandler {
+func NewTextAnalysisHandler(service domain.TextAnalysisService, history domain.HistoryService, logger *zap.Logger) *TextAnalysisHandler {
 	return &TextAnalysisHandler{
 		service: service,
+		history: history,
 		logger:  logger,
 	}
 }
@@ -42,7 +45,15 @@ func (h *TextAnalysisHandler) AnalyzeText(c *gin.Context) {
 		return
 	}
 
-	result, err := h.service.AnalyzeText(c.Request.Context(), req.Sentence)
+	result, err := h.service.AnalyzeText(c.Request.Context(), &req)
+	if errors.Is(err, domain.ErrInvalidInput) {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
+			Error:       "Invalid analysis request",
+			Code:        "validation_error",
+			Description: err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		h.logger.Error("Failed to analyze text", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{
@@ -53,5 +64,18 @@ func (h *TextAnalysisHandler) AnalyzeText(c *gin.Context) {
 		return
 	}
 
+	// A failure to record the analysis does not withhold its result.
+	if !req.SkipHistory {
+		if err := h.history.Record(c.Request.Context(), &req, result); err != nil {
+			h.logger.Error("Failed to record analysis history", zap.Error(err))
+		}
+	}
+
 	c.JSON(http.StatusOK, result)
 }
+
+func (h *TextAnalysisHandler) ListAnalyzers(c *gin.Context) {
+	c.JSON(http.StatusOK, domain.AnalyzersResponse{
+		Analyzers: h.service.ListAnalyzers(c.Request.Context()),
+	})
+}
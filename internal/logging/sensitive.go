@@ -0,0 +1,138 @@
+// Package logging keeps user content out of the logs. Values that may hold
+// customer text or identities are logged with Sensitive, and the logger is
+// wrapped with WithPolicy to decide how much of them is written.
+package logging
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Policy selects how sensitive values are written to the logs.
+type Policy string
+
+const (
+	// PolicyOff leaves sensitive values out of the logs.
+	PolicyOff Policy = "off"
+	// PolicyHash logs a short SHA-256 digest, so that equal values can be
+	// correlated without being readable.
+	PolicyHash Policy = "hash"
+	// PolicyTruncate logs the first characters of the value.
+	PolicyTruncate Policy = "truncate"
+	// PolicyLength logs the length of the value in characters.
+	PolicyLength Policy = "length"
+)
+
+const (
+	DefaultTruncateLength = 16
+	hashHexChars          = 16
+)
+
+// ParsePolicy returns the policy named by s; an empty s selects PolicyLength.
+func ParsePolicy(s string) (Policy, error) {
+	switch policy := Policy(s); policy {
+	case "":
+		return PolicyLength, nil
+	case PolicyOff, PolicyHash, PolicyTruncate, PolicyLength:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown logging privacy policy %q, expected %s, %s, %s or %s",
+		s, PolicyOff, PolicyHash, PolicyTruncate, PolicyLength)
+}
+
+// sensitive holds a value logged with Sensitive. When it reaches a logger not
+// wrapped with WithPolicy it is written as its length.
+type sensitive string
+
+func (s sensitive) String() string {
+	return "len:" + strconv.Itoa(utf8.RuneCountInString(string(s)))
+}
+
+// Sensitive returns a field for a value that must not be logged as is, such
+// as submitted text or a username.
+func Sensitive(key, value string) zap.Field {
+	return zap.Stringer(key, sensitive(value))
+}
+
+// WithPolicy returns a logger writing the fields made by Sensitive according
+// to policy. truncateLength is the number of characters PolicyTruncate keeps;
+// zero or less selects DefaultTruncateLength.
+func WithPolicy(logger *zap.Logger, policy Policy, truncateLength int) *zap.Logger {
+	if truncateLength <= 0 {
+		truncateLength = DefaultTruncateLength
+	}
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &policyCore{Core: core, policy: policy, truncateLength: truncateLength}
+	}))
+}
+
+type policyCore struct {
+	zapcore.Core
+	policy         Policy
+	truncateLength int
+}
+
+func (c *policyCore) With(fields []zapcore.Field) zapcore.Core {
+	return &policyCore{
+		Core:           c.Core.With(c.apply(fields)),
+		policy:         c.policy,
+		truncateLength: c.truncateLength,
+	}
+}
+
+// Check asks the wrapped core first, so that its sampling and level
+// decisions still apply, and writes the entries it accepts through c.
+func (c *policyCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Check(entry, nil) != nil {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *policyCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(entry, c.apply(fields))
+}
+
+func (c *policyCore) apply(fields []zapcore.Field) []zapcore.Field {
+	var applied []zapcore.Field
+	for i, field := range fields {
+		value, ok := field.Interface.(sensitive)
+		if !ok || field.Type != zapcore.StringerType {
+			if applied != nil {
+				applied = append(applied, field)
+			}
+			continue
+		}
+		if applied == nil {
+			applied = append(make([]zapcore.Field, 0, len(fields)), fields[:i]...)
+		}
+		applied = append(applied, c.field(field.Key, string(value)))
+	}
+	if applied == nil {
+		return fields
+	}
+	return applied
+}
+
+func (c *policyCore) field(key, value string) zapcore.Field {
+	switch c.policy {
+	case PolicyOff:
+		return zap.Skip()
+	case PolicyHash:
+		sum := sha256.Sum256([]byte(value))
+		return zap.String(key, "sha256:"+hex.EncodeToString(sum[:])[:hashHexChars])
+	case PolicyTruncate:
+		runes := []rune(value)
+		if len(runes) > c.truncateLength {
+			return zap.String(key, string(runes[:c.truncateLength])+"…")
+		}
+		return zap.String(key, value)
+	}
+	return zap.Int(key+"_length", utf8.RuneCountInString(value))
+}
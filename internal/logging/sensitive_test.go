@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+const sentence = "My card was stolen yesterday"
+
+func TestWithPolicy(t *testing.T) {
+	tests := []struct {
+		policy         Policy
+		truncateLength int
+		expected       map[string]interface{}
+	}{
+		{PolicyOff, 0, map[string]interface{}{"request_id": "r1"}},
+		{PolicyLength, 0, map[string]interface{}{"request_id": "r1", "sentence_length": int64(28)}},
+		{PolicyTruncate, 7, map[string]interface{}{"request_id": "r1", "sentence": "My card…"}},
+		{PolicyTruncate, 0, map[string]interface{}{"request_id": "r1", "sentence": "My card was stol…"}},
+		{PolicyTruncate, 40, map[string]interface{}{"request_id": "r1", "sentence": sentence}},
+		{PolicyHash, 0, map[string]interface{}{"request_id": "r1", "sentence": "sha256:c16dfb57adbe6402"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %d", tt.policy, tt.truncateLength), func(t *testing.T) {
+			t.Run("Write", func(t *testing.T) {
+				core, logs := observer.New(zap.InfoLevel)
+				logger := WithPolicy(zap.New(core), tt.policy, tt.truncateLength)
+
+				logger.Info("Text analysis completed", zap.String("request_id", "r1"), Sensitive("sentence", sentence))
+
+				require.Len(t, logs.All(), 1)
+				assert.Equal(t, tt.expected, logs.All()[0].ContextMap())
+			})
+
+			t.Run("With", func(t *testing.T) {
+				core, logs := observer.New(zap.InfoLevel)
+				logger := WithPolicy(zap.New(core), tt.policy, tt.truncateLength).
+					With(zap.String("request_id", "r1"), Sensitive("sentence", sentence))
+
+				logger.Info("Text analysis completed")
+				logger.With(zap.Int("attempt", 2)).Info("Text analysis retried")
+
+				require.Len(t, logs.All(), 2)
+				assert.Equal(t, tt.expected, logs.All()[0].ContextMap())
+				retried := logs.All()[1].ContextMap()
+				assert.Equal(t, int64(2), retried["attempt"])
+				delete(retried, "attempt")
+				assert.Equal(t, tt.expected, retried)
+			})
+		})
+	}
+
+	t.Run("Sampled logger", func(t *testing.T) {
+		core, logs := observer.New(zap.InfoLevel)
+		sampled := zapcore.NewSamplerWithOptions(core, time.Hour, 1, 0)
+		logger := WithPolicy(zap.New(sampled), PolicyLength, 0)
+
+		for i := 0; i < 3; i++ {
+			logger.Info("Text analysis completed", Sensitive("sentence", sentence))
+		}
+
+		require.Len(t, logs.All(), 1)
+		assert.Equal(t, int64(28), logs.All()[0].ContextMap()["sentence_length"])
+	})
+
+	t.Run("Level", func(t *testing.T) {
+		core, logs := observer.New(zap.WarnLevel)
+		logger := WithPolicy(zap.New(core), PolicyHash, 0)
+
+		logger.Info("Text analysis completed", Sensitive("sentence", sentence))
+
+		assert.Empty(t, logs.All())
+	})
+
+	t.Run("Unwrapped logger", func(t *testing.T) {
+		core, logs := observer.New(zap.InfoLevel)
+
+		zap.New(core).Info("Text analysis completed", Sensitive("sentence", sentence))
+
+		require.Len(t, logs.All(), 1)
+		assert.Equal(t, "len:28", logs.All()[0].ContextMap()["sentence"])
+	})
+}
+
+func TestParsePolicy(t *testing.T) {
+	for name, expected := range map[string]Policy{
+		"":         PolicyLength,
+		"off":      PolicyOff,
+		"hash":     PolicyHash,
+		"truncate": PolicyTruncate,
+		"length":   PolicyLength,
+	} {
+		policy, err := ParsePolicy(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, expected, policy, name)
+	}
+
+	_, err := ParsePolicy("plain")
+	assert.ErrorContains(t, err, `unknown logging privacy policy "plain"`)
+}
@@ -41,6 +41,7 @@ func AuthMiddleware(authService domain.AuthService, logger *zap.Logger) gin.Hand
 		}
 
 		c.Set("user", user)
+		c.Request = c.Request.WithContext(domain.WithUser(c.Request.Context(), user))
 		c.Next()
 	}
 }
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"vm-chan/internal/logging"
+
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -9,11 +11,12 @@ func LoggingMiddleware(logger *zap.Logger) gin.HandlerFunc {
 	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
 		logger.Info("HTTP Request",
 			zap.String("method", param.Method),
-			zap.String("path", param.Path),
+			zap.String("path", param.Request.URL.Path),
+			logging.Sensitive("query", param.Request.URL.RawQuery),
 			zap.Int("status", param.StatusCode),
 			zap.Duration("latency", param.Latency),
-			zap.String("client_ip", param.ClientIP),
-			zap.String("user_agent", param.Request.UserAgent()),
+			logging.Sensitive("client_ip", param.ClientIP),
+			logging.Sensitive("user_agent", param.Request.UserAgent()),
 			zap.Int("body_size", param.BodySize),
 		)
 		return ""
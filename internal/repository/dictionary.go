@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"vm-chan/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+type dictionaryRepository struct {
+	mu     sync.RWMutex
+	words  map[string]map[string]string
+	logger *zap.Logger
+}
+
+// NewDictionaryRepository returns an in-memory store of custom dictionaries.
+// Words are matched ignoring case; adding a word again keeps its latest
+// spelling.
+func NewDictionaryRepository(logger *zap.Logger) domain.DictionaryRepository {
+	return &dictionaryRepository{
+		words:  make(map[string]map[string]string),
+		logger: logger,
+	}
+}
+
+func (r *dictionaryRepository) Words(ctx context.Context, userID string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	words := make([]string, 0, len(r.words[userID]))
+	for _, word := range r.words[userID] {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	return words, nil
+}
+
+func (r *dictionaryRepository) AddWords(ctx context.Context, userID string, words []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.words[userID] == nil {
+		r.words[userID] = make(map[string]string)
+	}
+	for _, word := range words {
+		r.words[userID][strings.ToLower(word)] = word
+	}
+	return nil
+}
+
+func (r *dictionaryRepository) RemoveWord(ctx context.Context, userID string, word string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := strings.ToLower(word)
+	if _, exists := r.words[userID][key]; !exists {
+		return fmt.Errorf("%w: word %q is not in the dictionary", domain.ErrNotFound, word)
+	}
+	delete(r.words[userID], key)
+	return nil
+}
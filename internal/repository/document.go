@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"vm-chan/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+type documentRepository struct {
+	mu        sync.RWMutex
+	documents map[string]map[string]*domain.Document
+	logger    *zap.Logger
+}
+
+// NewDocumentRepository returns an in-memory store of documents, lost when
+// the service stops.
+func NewDocumentRepository(logger *zap.Logger) domain.DocumentRepository {
+	return &documentRepository{
+		documents: make(map[string]map[string]*domain.Document),
+		logger:    logger,
+	}
+}
+
+func (r *documentRepository) Create(ctx context.Context, document *domain.Document) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.documents[document.UserID] == nil {
+		r.documents[document.UserID] = make(map[string]*domain.Document)
+	}
+	if _, exists := r.documents[document.UserID][document.ID]; exists {
+		return fmt.Errorf("document %q already exists", document.ID)
+	}
+	stored := *document
+	r.documents[document.UserID][document.ID] = &stored
+	return nil
+}
+
+func (r *documentRepository) Get(ctx context.Context, userID, id string) (*domain.Document, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, exists := r.documents[userID][id]
+	if !exists {
+		return nil, documentNotFound(id)
+	}
+	document := *stored
+	return &document, nil
+}
+
+func (r *documentRepository) List(ctx context.Context, userID string, limit, offset int) ([]*domain.Document, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	documents := make([]*domain.Document, 0, len(r.documents[userID]))
+	for _, stored := range r.documents[userID] {
+		document := *stored
+		documents = append(documents, &document)
+	}
+	sort.Slice(documents, func(i, j int) bool {
+		if !documents[i].CreatedAt.Equal(documents[j].CreatedAt) {
+			return documents[i].CreatedAt.After(documents[j].CreatedAt)
+		}
+		return documents[i].ID < documents[j].ID
+	})
+
+	total := len(documents)
+	documents = documents[min(offset, total):]
+	return documents[:min(limit, len(documents))], total, nil
+}
+
+func (r *documentRepository) Update(ctx context.Context, document *domain.Document) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.documents[document.UserID][document.ID]; !exists {
+		return documentNotFound(document.ID)
+	}
+	stored := *document
+	r.documents[document.UserID][document.ID] = &stored
+	return nil
+}
+
+func (r *documentRepository) Delete(ctx context.Context, userID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.documents[userID][id]; !exists {
+		return documentNotFound(id)
+	}
+	delete(r.documents[userID], id)
+	return nil
+}
+
+func (r *documentRepository) Walk(ctx context.Context, fn func(*domain.Document) error) error {
+	r.mu.RLock()
+	documents := make([]*domain.Document, 0, len(r.documents))
+	for _, stored := range r.documents {
+		for _, document := range stored {
+			copied := *document
+			documents = append(documents, &copied)
+		}
+	}
+	r.mu.RUnlock()
+
+	for _, document := range documents {
+		if err := fn(document); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func documentNotFound(id string) error {
+	return fmt.Errorf("%w: document %q does not exist", domain.ErrNotFound, id)
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"vm-chan/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const documentSchema = `
+CREATE TABLE IF NOT EXISTS documents (
+	id         TEXT NOT NULL,
+	user_id    TEXT NOT NULL,
+	title      TEXT NOT NULL,
+	text       TEXT NOT NULL,
+	language   TEXT NOT NULL,
+	analyses   TEXT NOT NULL,
+	options    TEXT NOT NULL,
+	analysis   TEXT NOT NULL,
+	created_at INTEGER NOT NULL,
+	updated_at INTEGER NOT NULL,
+	PRIMARY KEY (user_id, id)
+);
+CREATE INDEX IF NOT EXISTS documents_user_created ON documents (user_id, created_at DESC);
+`
+
+const documentColumns = "id, user_id, title, text, language, analyses, options, analysis, created_at, updated_at"
+
+type sqliteDocumentRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewSQLiteDocumentRepository returns a store of documents kept in a SQLite
+// database opened with OpenSQLite, creating its table if needed. Analyses,
+// options and analysis results are stored as JSON.
+func NewSQLiteDocumentRepository(db *sql.DB, logger *zap.Logger) (domain.DocumentRepository, error) {
+	if _, err := db.Exec(documentSchema); err != nil {
+		return nil, fmt.Errorf("failed to create documents table: %w", err)
+	}
+	return &sqliteDocumentRepository{db: db, logger: logger}, nil
+}
+
+func (r *sqliteDocumentRepository) Create(ctx context.Context, document *domain.Document) error {
+	analyses, options, analysis, err := encodeDocument(document)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx,
+		"INSERT INTO documents ("+documentColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		document.ID, document.UserID, document.Title, document.Text, document.Language, analyses, options, analysis,
+		document.CreatedAt.UnixNano(), document.UpdatedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to insert document: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteDocumentRepository) Get(ctx context.Context, userID, id string) (*domain.Document, error) {
+	row := r.db.QueryRowContext(ctx,
+		"SELECT "+documentColumns+" FROM documents WHERE user_id = ? AND id = ?", userID, id)
+	document, err := scanDocument(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, documentNotFound(id)
+	}
+	return document, err
+}
+
+func (r *sqliteDocumentRepository) List(ctx context.Context, userID string, limit, offset int) ([]*domain.Document, int, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM documents WHERE user_id = ?", userID).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count documents: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT "+documentColumns+" FROM documents WHERE user_id = ? ORDER BY created_at DESC, id LIMIT ? OFFSET ?",
+		userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list documents: %w", err)
+	}
+	defer rows.Close()
+
+	documents := []*domain.Document{}
+	for rows.Next() {
+		document, err := scanDocument(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		documents = append(documents, document)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to list documents: %w", err)
+	}
+	return documents, total, nil
+}
+
+func (r *sqliteDocumentRepository) Update(ctx context.Context, document *domain.Document) error {
+	analyses, options, analysis, err := encodeDocument(document)
+	if err != nil {
+		return err
+	}
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE documents SET title = ?, text = ?, language = ?, analyses = ?, options = ?, analysis = ?, updated_at = ? "+
+			"WHERE user_id = ? AND id = ?",
+		document.Title, document.Text, document.Language, analyses, options, analysis, document.UpdatedAt.UnixNano(),
+		document.UserID, document.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update document: %w", err)
+	}
+	return requireAffected(result, document.ID)
+}
+
+func (r *sqliteDocumentRepository) Delete(ctx context.Context, userID, id string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM documents WHERE user_id = ? AND id = ?", userID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete document: %w", err)
+	}
+	return requireAffected(result, id)
+}
+
+func (r *sqliteDocumentRepository) Walk(ctx context.Context, fn func(*domain.Document) error) error {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+documentColumns+" FROM documents")
+	if err != nil {
+		return fmt.Errorf("failed to list documents: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		document, err := scanDocument(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(document); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to list documents: %w", err)
+	}
+	return nil
+}
+
+func requireAffected(result sql.Result, id string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return documentNotFound(id)
+	}
+	return nil
+}
+
+// encodeDocument returns the JSON encoding of the fields of a document
+// stored as JSON.
+func encodeDocument(document *domain.Document) (analyses, options, analysis string, err error) {
+	encoded := make([]string, 3)
+	for i, value := range []interface{}{document.Analyses, document.Options, document.Analysis} {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", "", "", fmt.Errorf("failed to encode document: %w", err)
+		}
+		encoded[i] = string(data)
+	}
+	return encoded[0], encoded[1], encoded[2], nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanDocument(row rowScanner) (*domain.Document, error) {
+	var (
+		document                    domain.Document
+		analyses, options, analysis string
+		createdAt, updatedAt        int64
+	)
+	err := row.Scan(&document.ID, &document.UserID, &document.Title, &document.Text, &document.Language,
+		&analyses, &options, &analysis, &createdAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(analyses), &document.Analyses); err != nil {
+		return nil, fmt.Errorf("document %q: invalid analyses: %w", document.ID, err)
+	}
+	if err := json.Unmarshal([]byte(options), &document.Options); err != nil {
+		return nil, fmt.Errorf("document %q: invalid options: %w", document.ID, err)
+	}
+	if err := json.Unmarshal([]byte(analysis), &document.Analysis); err != nil {
+		return nil, fmt.Errorf("document %q: invalid analysis: %w", document.ID, err)
+	}
+	document.CreatedAt = time.Unix(0, createdAt).UTC()
+	document.UpdatedAt = time.Unix(0, updatedAt).UTC()
+	return &document, nil
+}
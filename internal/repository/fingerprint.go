@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"vm-chan/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// fingerprintRepository holds the fingerprints of every tenant by document
+// ID.
+type fingerprintRepository struct {
+	mu           sync.RWMutex
+	fingerprints map[string]map[string]*domain.Fingerprint
+	path         string
+	logger       *zap.Logger
+}
+
+// NewFingerprintRepository returns an in-memory store of fingerprints, lost
+// when the service stops.
+func NewFingerprintRepository(logger *zap.Logger) domain.FingerprintRepository {
+	return &fingerprintRepository{
+		fingerprints: make(map[string]map[string]*domain.Fingerprint),
+		logger:       logger,
+	}
+}
+
+// fingerprintRecord is a line of a fingerprint file: a fingerprint, replacing
+// any earlier one of its document, or the removal of the fingerprint of a
+// document.
+type fingerprintRecord struct {
+	*domain.Fingerprint
+	Removed bool `json:"removed,omitempty"`
+}
+
+// NewFileFingerprintRepository returns a store of fingerprints kept in memory
+// and appended to a file, one JSON object per line, from which they are read
+// back when the store is created. The file is then compacted to one line per
+// stored fingerprint, dropping the replaced and removed ones. It is created if
+// it does not exist.
+func NewFileFingerprintRepository(path string, logger *zap.Logger) (domain.FingerprintRepository, error) {
+	r := &fingerprintRepository{
+		fingerprints: make(map[string]map[string]*domain.Fingerprint),
+		path:         path,
+		logger:       logger,
+	}
+
+	lines, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	count := 0
+	for _, fingerprints := range r.fingerprints {
+		count += len(fingerprints)
+	}
+	if lines > count {
+		if err := r.compact(); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Info("Fingerprint store loaded", zap.String("path", path), zap.Int("fingerprints", count))
+	return r, nil
+}
+
+// load replays the records of the file of the store, returning their number.
+func (r *fingerprintRepository) load() (int, error) {
+	file, err := os.Open(r.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to open fingerprint store: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	records := 0
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		record := fingerprintRecord{Fingerprint: &domain.Fingerprint{}}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return 0, fmt.Errorf("fingerprint store %s: line %d: %w", r.path, line, err)
+		}
+		if record.Removed {
+			r.remove(record.TenantID, record.DocumentID)
+		} else {
+			r.add(record.Fingerprint)
+		}
+		records++
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("fingerprint store %s: %w", r.path, err)
+	}
+	return records, nil
+}
+
+// compact rewrites the file of the store with one record per fingerprint.
+func (r *fingerprintRepository) compact() error {
+	temporary := r.path + ".tmp"
+	file, err := os.OpenFile(temporary, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write fingerprint store: %w", err)
+	}
+	out := bufio.NewWriter(file)
+	encoder := json.NewEncoder(out)
+	for _, fingerprints := range r.fingerprints {
+		for _, fingerprint := range fingerprints {
+			if err := encoder.Encode(fingerprintRecord{Fingerprint: fingerprint}); err != nil {
+				_ = file.Close()
+				return fmt.Errorf("failed to write fingerprint store: %w", err)
+			}
+		}
+	}
+	if err := out.Flush(); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write fingerprint store: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write fingerprint store: %w", err)
+	}
+	return os.Rename(temporary, r.path)
+}
+
+func (r *fingerprintRepository) Add(ctx context.Context, fingerprint *domain.Fingerprint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.path != "" {
+		if err := r.append(fingerprintRecord{Fingerprint: fingerprint}); err != nil {
+			return err
+		}
+	}
+	r.add(fingerprint)
+	return nil
+}
+
+func (r *fingerprintRepository) Remove(ctx context.Context, tenantID, documentID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.fingerprints[tenantID][documentID]; !ok {
+		return nil
+	}
+	if r.path != "" {
+		err := r.append(fingerprintRecord{
+			Fingerprint: &domain.Fingerprint{DocumentID: documentID, TenantID: tenantID},
+			Removed:     true,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	r.remove(tenantID, documentID)
+	return nil
+}
+
+func (r *fingerprintRepository) add(fingerprint *domain.Fingerprint) {
+	tenant, ok := r.fingerprints[fingerprint.TenantID]
+	if !ok {
+		tenant = make(map[string]*domain.Fingerprint)
+		r.fingerprints[fingerprint.TenantID] = tenant
+	}
+	tenant[fingerprint.DocumentID] = fingerprint
+}
+
+func (r *fingerprintRepository) remove(tenantID, documentID string) {
+	delete(r.fingerprints[tenantID], documentID)
+	if len(r.fingerprints[tenantID]) == 0 {
+		delete(r.fingerprints, tenantID)
+	}
+}
+
+func (r *fingerprintRepository) append(record fingerprintRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open fingerprint store: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write fingerprint store: %w", err)
+	}
+	return file.Close()
+}
+
+func (r *fingerprintRepository) List(ctx context.Context, tenantID string) ([]*domain.Fingerprint, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	fingerprints := make([]*domain.Fingerprint, 0, len(r.fingerprints[tenantID]))
+	for _, fingerprint := range r.fingerprints[tenantID] {
+		fingerprints = append(fingerprints, fingerprint)
+	}
+	sort.Slice(fingerprints, func(i, j int) bool {
+		a, b := fingerprints[i], fingerprints[j]
+		if !a.SubmittedAt.Equal(b.SubmittedAt) {
+			return a.SubmittedAt.Before(b.SubmittedAt)
+		}
+		return a.DocumentID < b.DocumentID
+	})
+	return fingerprints, nil
+}
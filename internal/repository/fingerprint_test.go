@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"vm-chan/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestFingerprintRepository(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "fingerprints.jsonl")
+	repo, err := NewFileFingerprintRepository(path, zap.NewNop())
+	require.NoError(t, err)
+
+	submitted := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	add := func(tenantID, documentID string, minutes int) {
+		require.NoError(t, repo.Add(ctx, &domain.Fingerprint{
+			DocumentID:  documentID,
+			TenantID:    tenantID,
+			SubmittedAt: submitted.Add(time.Duration(minutes) * time.Minute),
+		}))
+	}
+	ids := func(t *testing.T, repo domain.FingerprintRepository, tenantID string) []string {
+		fingerprints, err := repo.List(ctx, tenantID)
+		require.NoError(t, err)
+		found := []string{}
+		for _, fingerprint := range fingerprints {
+			found = append(found, fingerprint.DocumentID)
+		}
+		return found
+	}
+
+	add("acme", "b", 2)
+	add("acme", "a", 1)
+	add("acme", "c", 3)
+	add("globex", "a", 0)
+	add("acme", "a", 4)
+	require.NoError(t, repo.Remove(ctx, "acme", "c"))
+	require.NoError(t, repo.Remove(ctx, "acme", "missing"))
+
+	assert.Equal(t, []string{"b", "a"}, ids(t, repo, "acme"), "fingerprints are listed in submission order")
+	assert.Equal(t, []string{"a"}, ids(t, repo, "globex"))
+
+	reopened, err := NewFileFingerprintRepository(path, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "a"}, ids(t, reopened, "acme"))
+	assert.Equal(t, []string{"a"}, ids(t, reopened, "globex"))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(string(content), "\n"), "the file is compacted when it is loaded")
+	reopened, err = NewFileFingerprintRepository(path, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "a"}, ids(t, reopened, "acme"))
+}
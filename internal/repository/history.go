@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"vm-chan/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+type historyRepository struct {
+	mu      sync.RWMutex
+	entries map[string][]*domain.HistoryEntry
+	logger  *zap.Logger
+}
+
+// NewHistoryRepository returns an in-memory store of analysis history, lost
+// when the service stops.
+func NewHistoryRepository(logger *zap.Logger) domain.HistoryRepository {
+	return &historyRepository{
+		entries: make(map[string][]*domain.HistoryEntry),
+		logger:  logger,
+	}
+}
+
+func (r *historyRepository) Add(ctx context.Context, entry *domain.HistoryEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *entry
+	entries := append(r.entries[entry.UserID], &stored)
+	// Entries are kept oldest first; one recorded within the same instant
+	// as the last may need to move back to keep ties ordered by ID.
+	for i := len(entries) - 1; i > 0 && historyBefore(entries[i], entries[i-1]); i-- {
+		entries[i], entries[i-1] = entries[i-1], entries[i]
+	}
+	r.entries[entry.UserID] = entries
+	return nil
+}
+
+func (r *historyRepository) List(ctx context.Context, userID string, query domain.HistoryQuery) ([]*domain.HistoryEntry, error) {
+	for _, filter := range query.Filters {
+		if _, err := historyMetric(&domain.TextAnalysisResponse{}, filter); err != nil {
+			return nil, err
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := []*domain.HistoryEntry{}
+	stored := r.entries[userID]
+	for i := len(stored) - 1; i >= 0 && len(entries) < query.Limit; i-- {
+		entry := stored[i]
+		if query.After != nil && !historyBefore(entry, &domain.HistoryEntry{CreatedAt: query.After.CreatedAt, ID: query.After.ID}) {
+			continue
+		}
+		if !query.To.IsZero() && !entry.CreatedAt.Before(query.To) {
+			continue
+		}
+		if !query.From.IsZero() && entry.CreatedAt.Before(query.From) {
+			break
+		}
+		if !matchesFilters(entry, query.Filters) {
+			continue
+		}
+		copied := *entry
+		entries = append(entries, &copied)
+	}
+	return entries, nil
+}
+
+// historyBefore reports whether entry a is listed after entry b, being older
+// or as old with a lower ID.
+func historyBefore(a, b *domain.HistoryEntry) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+func matchesFilters(entry *domain.HistoryEntry, filters []domain.MetricFilter) bool {
+	analysis := entry.Analysis
+	if analysis == nil {
+		analysis = &domain.TextAnalysisResponse{}
+	}
+	for _, filter := range filters {
+		value, _ := historyMetric(analysis, filter)
+		var ok bool
+		switch filter.Operator {
+		case "=":
+			ok = value == filter.Value
+		case "!=":
+			ok = value != filter.Value
+		case "<":
+			ok = value < filter.Value
+		case "<=":
+			ok = value <= filter.Value
+		case ">":
+			ok = value > filter.Value
+		case ">=":
+			ok = value >= filter.Value
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// historyMetric returns the metric of an analysis a filter compares.
+func historyMetric(analysis *domain.TextAnalysisResponse, filter domain.MetricFilter) (float64, error) {
+	if _, ok := historyOperators[filter.Operator]; !ok {
+		return 0, fmt.Errorf("%w: unknown operator %q", domain.ErrInvalidInput, filter.Operator)
+	}
+	switch filter.Metric {
+	case "word_count":
+		return float64(analysis.WordCount), nil
+	case "vowel_count":
+		return float64(analysis.VowelCount), nil
+	case "consonant_count":
+		return float64(analysis.ConsonantCount), nil
+	case "other_letter_count":
+		return float64(analysis.OtherLetterCount), nil
+	}
+	return 0, fmt.Errorf("%w: unknown metric %q", domain.ErrInvalidInput, filter.Metric)
+}
+
+// historyOperators are the SQL operators of the operators of metric filters.
+var historyOperators = map[string]string{
+	"=":  "=",
+	"!=": "<>",
+	"<":  "<",
+	"<=": "<=",
+	">":  ">",
+	">=": ">=",
+}
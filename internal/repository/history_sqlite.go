@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"vm-chan/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// historySchema keeps the metrics entries are filtered by in their own
+// columns next to the JSON of the request and response.
+const historySchema = `
+CREATE TABLE IF NOT EXISTS analysis_history (
+	id                 TEXT NOT NULL,
+	user_id            TEXT NOT NULL,
+	request            TEXT NOT NULL,
+	analysis           TEXT NOT NULL,
+	word_count         INTEGER NOT NULL,
+	vowel_count        INTEGER NOT NULL,
+	consonant_count    INTEGER NOT NULL,
+	other_letter_count INTEGER NOT NULL,
+	created_at         INTEGER NOT NULL,
+	PRIMARY KEY (user_id, id)
+);
+CREATE INDEX IF NOT EXISTS analysis_history_user_created ON analysis_history (user_id, created_at DESC, id DESC);
+`
+
+type sqliteHistoryRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewSQLiteHistoryRepository returns a store of analysis history kept in a
+// SQLite database opened with OpenSQLite, creating its table if needed.
+func NewSQLiteHistoryRepository(db *sql.DB, logger *zap.Logger) (domain.HistoryRepository, error) {
+	if _, err := db.Exec(historySchema); err != nil {
+		return nil, fmt.Errorf("failed to create analysis history table: %w", err)
+	}
+	return &sqliteHistoryRepository{db: db, logger: logger}, nil
+}
+
+func (r *sqliteHistoryRepository) Add(ctx context.Context, entry *domain.HistoryEntry) error {
+	request, err := json.Marshal(entry.Request)
+	if err != nil {
+		return fmt.Errorf("failed to encode history entry: %w", err)
+	}
+	analysis, err := json.Marshal(entry.Analysis)
+	if err != nil {
+		return fmt.Errorf("failed to encode history entry: %w", err)
+	}
+	metrics := entry.Analysis
+	if metrics == nil {
+		metrics = &domain.TextAnalysisResponse{}
+	}
+	_, err = r.db.ExecContext(ctx,
+		"INSERT INTO analysis_history (id, user_id, request, analysis, word_count, vowel_count, consonant_count, "+
+			"other_letter_count, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		entry.ID, entry.UserID, string(request), string(analysis), metrics.WordCount, metrics.VowelCount,
+		metrics.ConsonantCount, metrics.OtherLetterCount, entry.CreatedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to insert history entry: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteHistoryRepository) List(ctx context.Context, userID string, query domain.HistoryQuery) ([]*domain.HistoryEntry, error) {
+	conditions := []string{"user_id = ?"}
+	args := []interface{}{userID}
+	if !query.From.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, query.From.UnixNano())
+	}
+	if !query.To.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, query.To.UnixNano())
+	}
+	if query.After != nil {
+		conditions = append(conditions, "(created_at < ? OR created_at = ? AND id < ?)")
+		after := query.After.CreatedAt.UnixNano()
+		args = append(args, after, after, query.After.ID)
+	}
+	for _, filter := range query.Filters {
+		// The metric and operator are checked against fixed lists before
+		// being written into the statement.
+		if _, err := historyMetric(&domain.TextAnalysisResponse{}, filter); err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, filter.Metric+" "+historyOperators[filter.Operator]+" ?")
+		args = append(args, filter.Value)
+	}
+	args = append(args, query.Limit)
+
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT id, user_id, request, analysis, created_at FROM analysis_history WHERE "+
+			strings.Join(conditions, " AND ")+" ORDER BY created_at DESC, id DESC LIMIT ?", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list history: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*domain.HistoryEntry{}
+	for rows.Next() {
+		var (
+			entry             domain.HistoryEntry
+			request, analysis string
+			createdAt         int64
+		)
+		if err := rows.Scan(&entry.ID, &entry.UserID, &request, &analysis, &createdAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(request), &entry.Request); err != nil {
+			return nil, fmt.Errorf("history entry %q: invalid request: %w", entry.ID, err)
+		}
+		if err := json.Unmarshal([]byte(analysis), &entry.Analysis); err != nil {
+			return nil, fmt.Errorf("history entry %q: invalid analysis: %w", entry.ID, err)
+		}
+		entry.CreatedAt = time.Unix(0, createdAt).UTC()
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list history: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+
+	// Registers the pure Go SQLite driver, which needs no cgo.
+	_ "modernc.org/sqlite"
+)
+
+// OpenSQLite opens the SQLite database at path, creating it if it does not
+// exist. Writers wait for each other instead of failing when the database is
+// locked.
+func OpenSQLite(path string) (*sql.DB, error) {
+	// The path is escaped so that characters such as ? and # are not read as
+	// the start of the query or fragment of the URI. The URI has no authority,
+	// so that a relative path stays relative instead of naming a host.
+	dsn := "file:" + (&url.URL{Path: path}).EscapedPath() +
+		"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+	return db, nil
+}
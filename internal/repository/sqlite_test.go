@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenSQLite(t *testing.T) {
+	open := func(t *testing.T, path string) {
+		db, err := OpenSQLite(path)
+		require.NoError(t, err)
+		_, err = db.Exec("CREATE TABLE t (id INTEGER)")
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+	}
+
+	t.Run("Escaped path", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "data?v=1#x")
+		require.NoError(t, os.Mkdir(dir, 0o700))
+		path := filepath.Join(dir, "documents.db")
+
+		open(t, path)
+		assert.FileExists(t, path)
+	})
+
+	t.Run("Relative path", func(t *testing.T) {
+		dir := t.TempDir()
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		require.NoError(t, os.Chdir(dir))
+		t.Cleanup(func() { _ = os.Chdir(wd) })
+		require.NoError(t, os.Mkdir("data", 0o700))
+
+		for _, path := range []string{"vm.db", "data/vm.db", "./rel.db"} {
+			open(t, path)
+			assert.FileExists(t, filepath.Join(dir, path), path)
+		}
+	})
+}
@@ -23,6 +23,7 @@ func NewUserRepository(logger *zap.Logger) domain.UserRepository {
 		"admin": {
 			ID:       "1",
 			Username: "admin",
+			TenantID: "default",
 			Password: string(hashedPassword),
 		},
 	}
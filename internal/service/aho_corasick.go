@@ -0,0 +1,89 @@
+package service
+
+// ahoCorasick finds every occurrence of a set of patterns in a single pass
+// over the text, whatever the number of patterns.
+type ahoCorasick struct {
+	nodes   []ahoCorasickNode
+	lengths []int
+}
+
+type ahoCorasickNode struct {
+	next map[rune]int
+	fail int
+	// outputs are the indexes of the patterns ending at this node, including
+	// those reached through failure links.
+	outputs []int
+}
+
+// ahoCorasickMatch is an occurrence of patterns[Pattern] in the matched runes
+// from Start to the exclusive End.
+type ahoCorasickMatch struct {
+	Pattern int
+	Start   int
+	End     int
+}
+
+func newAhoCorasick(patterns [][]rune) *ahoCorasick {
+	a := &ahoCorasick{nodes: []ahoCorasickNode{{next: make(map[rune]int)}}, lengths: make([]int, len(patterns))}
+	for i, pattern := range patterns {
+		a.lengths[i] = len(pattern)
+		if len(pattern) == 0 {
+			continue
+		}
+		node := 0
+		for _, r := range pattern {
+			child, ok := a.nodes[node].next[r]
+			if !ok {
+				child = len(a.nodes)
+				a.nodes = append(a.nodes, ahoCorasickNode{next: make(map[rune]int)})
+				a.nodes[node].next[r] = child
+			}
+			node = child
+		}
+		a.nodes[node].outputs = append(a.nodes[node].outputs, i)
+	}
+
+	// Failure links are set breadth first, so that the link of a node is
+	// complete before its children need it.
+	queue := make([]int, 0, len(a.nodes))
+	for _, child := range a.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for r, child := range a.nodes[node].next {
+			fail := a.nodes[node].fail
+			for fail > 0 && !a.hasNext(fail, r) {
+				fail = a.nodes[fail].fail
+			}
+			if next, ok := a.nodes[fail].next[r]; ok && next != child {
+				a.nodes[child].fail = next
+			}
+			a.nodes[child].outputs = append(a.nodes[child].outputs, a.nodes[a.nodes[child].fail].outputs...)
+			queue = append(queue, child)
+		}
+	}
+	return a
+}
+
+func (a *ahoCorasick) hasNext(node int, r rune) bool {
+	_, ok := a.nodes[node].next[r]
+	return ok
+}
+
+// FindAll returns the occurrences of the patterns in text, ordered by end.
+func (a *ahoCorasick) FindAll(text []rune) []ahoCorasickMatch {
+	var matches []ahoCorasickMatch
+	node := 0
+	for i, r := range text {
+		for node > 0 && !a.hasNext(node, r) {
+			node = a.nodes[node].fail
+		}
+		node = a.nodes[node].next[r]
+		for _, pattern := range a.nodes[node].outputs {
+			matches = append(matches, ahoCorasickMatch{Pattern: pattern, Start: i + 1 - a.lengths[pattern], End: i + 1})
+		}
+	}
+	return matches
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"vm-chan/internal/domain"
+)
+
+// Analyzer is a single named analysis that can be selected per request through
+// the analyses field. Results are returned under the analyzer name.
+type Analyzer interface {
+	Name() string
+	Version() string
+	Description() string
+	Analyze(ctx context.Context, doc *Document) (interface{}, error)
+}
+
+type AnalyzerRegistry struct {
+	mu        sync.RWMutex
+	analyzers map[string]Analyzer
+}
+
+func NewAnalyzerRegistry(analyzers ...Analyzer) (*AnalyzerRegistry, error) {
+	registry := &AnalyzerRegistry{
+		analyzers: make(map[string]Analyzer, len(analyzers)),
+	}
+
+	for _, analyzer := range analyzers {
+		if err := registry.Register(analyzer); err != nil {
+			return nil, err
+		}
+	}
+
+	return registry, nil
+}
+
+// AnalyzerResources are the deployment-specific data used by the built-in
+// analyzers. Nil fields select the embedded defaults.
+type AnalyzerResources struct {
+	SentimentLexicon    *SentimentLexicon
+	SpellDictionaries   map[string]*HunspellDictionary
+	CustomWords         domain.DictionaryRepository
+	ModerationWordlists map[string]*ModerationWordlist
+	TenantWordlists     map[string]*ModerationWordlist
+	POSTagger           *PerceptronTagger
+	Fingerprints        *FingerprintIndex
+	SearchIndex         *SearchIndex
+}
+
+func DefaultAnalyzers(resources AnalyzerResources) []Analyzer {
+	return []Analyzer{
+		NewCountsAnalyzer(),
+		NewReadabilityAnalyzer(),
+		NewSentencesAnalyzer(),
+		NewFrequencyAnalyzer(),
+		NewLexicalAnalyzer(),
+		NewLanguageAnalyzer(),
+		NewSentimentAnalyzer(resources.SentimentLexicon),
+		NewKeywordsAnalyzer(resources.SearchIndex),
+		NewEntitiesAnalyzer(),
+		NewSpellAnalyzer(resources.SpellDictionaries, resources.CustomWords),
+		NewModerationAnalyzer(resources.ModerationWordlists, resources.TenantWordlists),
+		NewPOSAnalyzer(resources.POSTagger),
+		NewDuplicatesAnalyzer(resources.Fingerprints),
+	}
+}
+
+func (r *AnalyzerRegistry) Register(analyzer Analyzer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := analyzer.Name()
+	if name == "" {
+		return fmt.Errorf("analyzer name must not be empty")
+	}
+	if _, exists := r.analyzers[name]; exists {
+		return fmt.Errorf("analyzer %q is already registered", name)
+	}
+
+	r.analyzers[name] = analyzer
+	return nil
+}
+
+func (r *AnalyzerRegistry) Get(name string) (Analyzer, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	analyzer, ok := r.analyzers[name]
+	return analyzer, ok
+}
+
+func (r *AnalyzerRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.analyzers))
+	for name := range r.analyzers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *AnalyzerRegistry) Describe() []domain.AnalyzerInfo {
+	names := r.Names()
+	infos := make([]domain.AnalyzerInfo, 0, len(names))
+	for _, name := range names {
+		analyzer, _ := r.Get(name)
+		infos = append(infos, domain.AnalyzerInfo{
+			Name:        analyzer.Name(),
+			Version:     analyzer.Version(),
+			Description: analyzer.Description(),
+		})
+	}
+	return infos
+}
+
+// Resolve maps the requested analyzer names to registered analyzers, dropping
+// duplicates. Unknown names produce a validation error listing what is available.
+func (r *AnalyzerRegistry) Resolve(names []string) ([]Analyzer, error) {
+	analyzers := make([]Analyzer, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	var unknown []string
+
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		analyzer, ok := r.Get(name)
+		if !ok {
+			unknown = append(unknown, fmt.Sprintf("%q", name))
+			continue
+		}
+		analyzers = append(analyzers, analyzer)
+	}
+
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%w: unknown analyzers %s, available analyzers: %s",
+			domain.ErrInvalidInput, strings.Join(unknown, ", "), strings.Join(r.Names(), ", "))
+	}
+
+	return analyzers, nil
+}
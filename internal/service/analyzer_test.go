@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDocument returns a document of text in language, read with the
+// phonology of the language or the default one like AnalyzeText does.
+func testDocument(text, language string) *Document {
+	p, ok := lookupPhonology(language)
+	if !ok {
+		p = defaultPhonology
+	}
+	doc := newDocument(text, p, NewTokenizer())
+	doc.Language = language
+	return doc
+}
+
+// analyzeDoc runs an analyzer on a document of text in language and returns
+// its result, failing the test on an error.
+func analyzeDoc[R any](t *testing.T, analyzer Analyzer, text, language string) R {
+	t.Helper()
+	return analyzeDocument[R](t, context.Background(), analyzer, testDocument(text, language))
+}
+
+// analyzeDocument is analyzeDoc for a document the test has set up.
+func analyzeDocument[R any](t *testing.T, ctx context.Context, analyzer Analyzer, doc *Document) R {
+	t.Helper()
+	result, err := analyzer.Analyze(ctx, doc)
+	require.NoError(t, err)
+	return result.(R)
+}
+
+type stubAnalyzer struct {
+	name string
+}
+
+func (a *stubAnalyzer) Name() string        { return a.name }
+func (a *stubAnalyzer) Version() string     { return "0.1.0" }
+func (a *stubAnalyzer) Description() string { return "stub " + a.name }
+
+func (a *stubAnalyzer) Analyze(ctx context.Context, doc *Document) (interface{}, error) {
+	return len(doc.Text), nil
+}
+
+func TestAnalyzerRegistry(t *testing.T) {
+	t.Run("Duplicate registration", func(t *testing.T) {
+		_, err := NewAnalyzerRegistry(&stubAnalyzer{name: "stub"}, &stubAnalyzer{name: "stub"})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Describe is sorted by name", func(t *testing.T) {
+		registry, err := NewAnalyzerRegistry(&stubAnalyzer{name: "zeta"}, &stubAnalyzer{name: "alpha"})
+		require.NoError(t, err)
+
+		infos := registry.Describe()
+
+		require.Len(t, infos, 2)
+		assert.Equal(t, "alpha", infos[0].Name)
+		assert.Equal(t, "0.1.0", infos[0].Version)
+		assert.Equal(t, "stub alpha", infos[0].Description)
+		assert.Equal(t, "zeta", infos[1].Name)
+	})
+
+	t.Run("Resolve keeps request order and drops duplicates", func(t *testing.T) {
+		registry, err := NewAnalyzerRegistry(&stubAnalyzer{name: "a"}, &stubAnalyzer{name: "b"})
+		require.NoError(t, err)
+
+		analyzers, err := registry.Resolve([]string{"b", "a", "b"})
+
+		require.NoError(t, err)
+		require.Len(t, analyzers, 2)
+		assert.Equal(t, "b", analyzers[0].Name())
+		assert.Equal(t, "a", analyzers[1].Name())
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"golang.org/x/crypto/bcrypt"
 
 	"vm-chan/internal/domain"
+	"vm-chan/internal/logging"
 
 	"github.com/golang-jwt/jwt/v5"
 	"go.uber.org/zap"
@@ -22,6 +23,7 @@ type authService struct {
 type Claims struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
+	TenantID string `json:"tenant_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -34,16 +36,16 @@ func NewAuthService(userRepo domain.UserRepository, jwtSecret string, logger *za
 }
 
 func (s *authService) Login(ctx context.Context, username, password string) (*domain.LoginResponse, error) {
-	s.logger.Info("Login attempt", zap.String("username", username))
+	s.logger.Info("Login attempt", logging.Sensitive("username", username))
 
 	user, err := s.userRepo.GetByUsername(ctx, username)
 	if err != nil {
-		s.logger.Error("User not found", zap.String("username", username), zap.Error(err))
+		s.logger.Error("User not found", logging.Sensitive("username", username), zap.Error(err))
 		return nil, errors.New("invalid credentials")
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		s.logger.Error("Invalid password", zap.String("username", username))
+		s.logger.Error("Invalid password", logging.Sensitive("username", username))
 		return nil, errors.New("invalid credentials")
 	}
 
@@ -51,6 +53,7 @@ func (s *authService) Login(ctx context.Context, username, password string) (*do
 	claims := &Claims{
 		UserID:   user.ID,
 		Username: user.Username,
+		TenantID: user.TenantID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -65,7 +68,7 @@ func (s *authService) Login(ctx context.Context, username, password string) (*do
 		return nil, errors.New("failed to generate token")
 	}
 
-	s.logger.Info("Login successful", zap.String("username", username))
+	s.logger.Info("Login successful", logging.Sensitive("username", username))
 
 	return &domain.LoginResponse{
 		Token:     tokenString,
@@ -87,6 +90,7 @@ func (s *authService) ValidateToken(ctx context.Context, tokenString string) (*d
 	user := &domain.User{
 		ID:       claims.UserID,
 		Username: claims.Username,
+		TenantID: claims.TenantID,
 	}
 
 	return user, nil
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"vm-chan/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+type batchAnalysisService struct {
+	analysis domain.TextAnalysisService
+	history  domain.HistoryService
+	maxTexts int
+	// slots bounds the number of texts analyzed at once across all batches.
+	slots  chan struct{}
+	logger *zap.Logger
+}
+
+// NewBatchAnalysisService returns the service analyzing batches of up to
+// maxTexts texts, at most workers of them at once across all batches, and
+// recording every successful analysis in the history of the user unless the
+// batch opts out.
+func NewBatchAnalysisService(analysis domain.TextAnalysisService, history domain.HistoryService, maxTexts, workers int, logger *zap.Logger) domain.BatchAnalysisService {
+	return &batchAnalysisService{
+		analysis: analysis,
+		history:  history,
+		maxTexts: max(maxTexts, 1),
+		slots:    make(chan struct{}, max(workers, 1)),
+		logger:   logger,
+	}
+}
+
+func (s *batchAnalysisService) AnalyzeBatch(ctx context.Context, req *domain.BatchAnalysisRequest) (*domain.BatchAnalysisResponse, error) {
+	if len(req.Texts) == 0 {
+		return nil, fmt.Errorf("%w: texts must not be empty", domain.ErrInvalidInput)
+	}
+	if len(req.Texts) > s.maxTexts {
+		return nil, fmt.Errorf("%w: a batch holds at most %d texts, got %d", domain.ErrInvalidInput, s.maxTexts, len(req.Texts))
+	}
+
+	response := &domain.BatchAnalysisResponse{Results: make([]domain.BatchAnalysisResult, len(req.Texts))}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < min(cap(s.slots), len(req.Texts)); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				response.Results[i] = s.analyze(ctx, req, i)
+			}
+		}()
+	}
+
+	for i := range req.Texts {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for _, result := range response.Results {
+		if result.Error != nil {
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+	}
+
+	s.logger.Info("Batch analysis completed",
+		zap.Int("texts", len(req.Texts)),
+		zap.Int("succeeded", response.Succeeded),
+		zap.Int("failed", response.Failed),
+	)
+
+	return response, nil
+}
+
+// analyze analyzes the text of a batch at index i once a slot is free.
+func (s *batchAnalysisService) analyze(ctx context.Context, batch *domain.BatchAnalysisRequest, i int) domain.BatchAnalysisResult {
+	result := domain.BatchAnalysisResult{Index: i}
+	if batch.Texts[i] == "" {
+		result.Error = &domain.ErrorResponse{
+			Error:       "Sentence cannot be empty",
+			Code:        "validation_error",
+			Description: "The text must not be empty",
+		}
+		return result
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		result.Error = &domain.ErrorResponse{
+			Error:       "Failed to analyze text",
+			Code:        "internal_error",
+			Description: "The request was canceled",
+		}
+		return result
+	}
+	defer func() { <-s.slots }()
+
+	req := &domain.TextAnalysisRequest{
+		Sentence:        batch.Texts[i],
+		Language:        batch.Language,
+		Analyses:        batch.Analyses,
+		Options:         batch.Options,
+		Normalize:       batch.Normalize,
+		SkipHistory:     batch.SkipHistory,
+		KeepFingerprint: batch.KeepFingerprint,
+	}
+	analysis, err := s.analysis.AnalyzeText(ctx, req)
+	if errors.Is(err, domain.ErrInvalidInput) {
+		result.Error = &domain.ErrorResponse{
+			Error:       "Invalid analysis request",
+			Code:        "validation_error",
+			Description: err.Error(),
+		}
+		return result
+	}
+	if err != nil {
+		s.logger.Error("Failed to analyze text", zap.Int("index", i), zap.Error(err))
+		result.Error = &domain.ErrorResponse{
+			Error:       "Failed to analyze text",
+			Code:        "internal_error",
+			Description: "An unexpected error occurred while processing your request",
+		}
+		return result
+	}
+	result.Result = analysis
+
+	// A failure to record the analysis does not withhold its result.
+	if !req.SkipHistory {
+		if err := s.history.Record(ctx, req, analysis); err != nil {
+			s.logger.Error("Failed to record analysis history", zap.Int("index", i), zap.Error(err))
+		}
+	}
+	return result
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"vm-chan/internal/domain"
+	"vm-chan/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestBatchAnalysisService(t *testing.T) {
+	registry, err := NewAnalyzerRegistry(DefaultAnalyzers(AnalyzerResources{})...)
+	require.NoError(t, err)
+	analysis := NewTextAnalysisService(registry, NewTokenizer(), nil, zap.NewNop())
+	history := NewHistoryService(repository.NewHistoryRepository(zap.NewNop()), zap.NewNop())
+	service := NewBatchAnalysisService(analysis, history, 5, 2, zap.NewNop())
+	alice := domain.WithUser(context.Background(), &domain.User{ID: "alice"})
+	bob := domain.WithUser(context.Background(), &domain.User{ID: "bob"})
+
+	recorded := func(t *testing.T, ctx context.Context) int {
+		response, err := history.List(ctx, &domain.HistoryRequest{})
+		require.NoError(t, err)
+		return len(response.Entries)
+	}
+
+	t.Run("Results in order", func(t *testing.T) {
+		response, err := service.AnalyzeBatch(alice, &domain.BatchAnalysisRequest{
+			Texts:    []string{"one", "one two", "", "one two three", "one two three four"},
+			Language: "en",
+			Analyses: []string{"counts"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 4, response.Succeeded)
+		assert.Equal(t, 1, response.Failed)
+		require.Len(t, response.Results, 5)
+		for i, words := range []int{1, 2, 0, 3, 4} {
+			result := response.Results[i]
+			assert.Equal(t, i, result.Index)
+			if words == 0 {
+				assert.Nil(t, result.Result)
+				require.NotNil(t, result.Error)
+				assert.Equal(t, "validation_error", result.Error.Code)
+				continue
+			}
+			require.Nil(t, result.Error)
+			assert.Equal(t, words, result.Result.WordCount)
+			assert.Contains(t, result.Result.Results, "counts")
+		}
+		assert.Equal(t, 4, recorded(t, alice), "successful analyses are recorded")
+	})
+
+	t.Run("Per-item errors", func(t *testing.T) {
+		response, err := service.AnalyzeBatch(bob, &domain.BatchAnalysisRequest{
+			Texts:       []string{"one", "two"},
+			Analyses:    []string{"unknown"},
+			SkipHistory: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, response.Failed)
+		for _, result := range response.Results {
+			require.NotNil(t, result.Error)
+			assert.Equal(t, "validation_error", result.Error.Code)
+		}
+		assert.Zero(t, recorded(t, bob))
+	})
+
+	t.Run("Fingerprints", func(t *testing.T) {
+		fingerprints := NewFingerprintIndex(repository.NewFingerprintRepository(zap.NewNop()))
+		analysis := NewTextAnalysisService(registry, NewTokenizer(), fingerprints, zap.NewNop())
+		service := NewBatchAnalysisService(analysis, history, 5, 2, zap.NewNop())
+
+		for _, keep := range []bool{false, true} {
+			response, err := service.AnalyzeBatch(bob, &domain.BatchAnalysisRequest{
+				Texts:           []string{duplicateOriginal},
+				Language:        "en",
+				SkipHistory:     true,
+				KeepFingerprint: keep,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, response.Results[0].Result)
+			assert.Equal(t, keep, response.Results[0].Result.DocumentID != "", "keep_fingerprint is %v", keep)
+		}
+	})
+
+	t.Run("Invalid requests", func(t *testing.T) {
+		for name, req := range map[string]*domain.BatchAnalysisRequest{
+			"no texts":       {},
+			"too many texts": {Texts: []string{"a", "b", "c", "d", "e", "f"}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := service.AnalyzeBatch(alice, req)
+				assert.ErrorIs(t, err, domain.ErrInvalidInput)
+			})
+		}
+	})
+}
+
+// concurrencyProbe is a text analysis service recording the largest number
+// of texts it analyzed at once.
+type concurrencyProbe struct {
+	domain.TextAnalysisService
+	running, peak atomic.Int32
+}
+
+func (p *concurrencyProbe) AnalyzeText(ctx context.Context, req *domain.TextAnalysisRequest) (*domain.TextAnalysisResponse, error) {
+	running := p.running.Add(1)
+	defer p.running.Add(-1)
+	for {
+		peak := p.peak.Load()
+		if running <= peak || p.peak.CompareAndSwap(peak, running) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+	return &domain.TextAnalysisResponse{Sentence: req.Sentence}, nil
+}
+
+func TestBatchAnalysisServiceWorkers(t *testing.T) {
+	probe := &concurrencyProbe{}
+	history := NewHistoryService(repository.NewHistoryRepository(zap.NewNop()), zap.NewNop())
+	service := NewBatchAnalysisService(probe, history, 100, 3, zap.NewNop())
+	alice := domain.WithUser(context.Background(), &domain.User{ID: "alice"})
+
+	texts := make([]string, 20)
+	for i := range texts {
+		texts[i] = string(rune('a' + i))
+	}
+
+	// Two batches at once share the same workers.
+	done := make(chan *domain.BatchAnalysisResponse, 2)
+	for range 2 {
+		go func() {
+			response, err := service.AnalyzeBatch(alice, &domain.BatchAnalysisRequest{Texts: texts})
+			assert.NoError(t, err)
+			done <- response
+		}()
+	}
+	for range 2 {
+		response := <-done
+		require.NotNil(t, response)
+		require.Len(t, response.Results, len(texts))
+		for i, result := range response.Results {
+			assert.Equal(t, texts[i], result.Result.Sentence)
+		}
+	}
+	assert.Equal(t, int32(3), probe.peak.Load())
+}
@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"vm-chan/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	maxComparedTexts = 10
+	maxComparedRunes = 5000
+	// maxComparedTotalRunes bounds the characters of all the texts, as every
+	// pair is compared in time proportional to the product of their lengths.
+	maxComparedTotalRunes = 10000
+	defaultShingleSize    = 3
+)
+
+const (
+	diffEqual  = "equal"
+	diffInsert = "insert"
+	diffDelete = "delete"
+)
+
+type comparisonService struct {
+	tokenizer Tokenizer
+	logger    *zap.Logger
+}
+
+func NewComparisonService(tokenizer Tokenizer, logger *zap.Logger) domain.ComparisonService {
+	return &comparisonService{
+		tokenizer: tokenizer,
+		logger:    logger,
+	}
+}
+
+// comparedText is a text prepared for comparison: its code points, its words
+// and their case-folded terms.
+type comparedText struct {
+	text   string
+	runes  []rune
+	tokens []Token
+	terms  []string
+}
+
+func (s *comparisonService) Compare(ctx context.Context, req *domain.CompareRequest) (*domain.CompareResponse, error) {
+	if len(req.Texts) < 2 || len(req.Texts) > maxComparedTexts {
+		return nil, fmt.Errorf("%w: expected between 2 and %d texts, got %d",
+			domain.ErrInvalidInput, maxComparedTexts, len(req.Texts))
+	}
+	shingleSize := req.ShingleSize
+	if shingleSize == 0 {
+		shingleSize = defaultShingleSize
+	}
+	if shingleSize < 0 {
+		return nil, fmt.Errorf("%w: shingle_size must be positive", domain.ErrInvalidInput)
+	}
+
+	texts := make([]comparedText, len(req.Texts))
+	total := 0
+	for i, text := range req.Texts {
+		runes := []rune(text)
+		if len(runes) > maxComparedRunes {
+			return nil, fmt.Errorf("%w: text %d is longer than %d characters", domain.ErrInvalidInput, i, maxComparedRunes)
+		}
+		if total += len(runes); total > maxComparedTotalRunes {
+			return nil, fmt.Errorf("%w: texts are longer than %d characters in total", domain.ErrInvalidInput, maxComparedTotalRunes)
+		}
+		tokens := s.tokenizer.Tokenize(text)
+		terms := make([]string, len(tokens))
+		for j, token := range tokens {
+			terms[j] = foldTerm(token.Text)
+		}
+		texts[i] = comparedText{text: text, runes: runes, tokens: tokens, terms: terms}
+	}
+
+	response := &domain.CompareResponse{}
+	for a := range texts {
+		for b := a + 1; b < len(texts); b++ {
+			comparison, err := compareTexts(ctx, texts[a], texts[b], a, b, shingleSize)
+			if err != nil {
+				return nil, err
+			}
+			response.Comparisons = append(response.Comparisons, comparison)
+		}
+	}
+
+	s.logger.Info("Comparison completed",
+		zap.Int("texts", len(texts)),
+		zap.Int("comparisons", len(response.Comparisons)),
+	)
+
+	return response, nil
+}
+
+func compareTexts(ctx context.Context, a, b comparedText, indexA, indexB, shingleSize int) (domain.TextComparison, error) {
+	comparison := domain.TextComparison{
+		A:       indexA,
+		B:       indexB,
+		Jaccard: round4(jaccard(shingles(a.terms, shingleSize), shingles(b.terms, shingleSize))),
+		Cosine:  round4(cosine(a.terms, b.terms)),
+	}
+	lcs, err := lcsTable(ctx, a.terms, b.terms)
+	if err != nil {
+		return comparison, err
+	}
+	comparison.LCS.Words = lcs[0][0]
+	comparison.Diff = wordDiff(a, b, lcs)
+
+	if comparison.Levenshtein.Characters, err = levenshtein(ctx, a.runes, b.runes); err != nil {
+		return comparison, err
+	}
+	if comparison.Levenshtein.Words, err = levenshtein(ctx, a.terms, b.terms); err != nil {
+		return comparison, err
+	}
+	if comparison.Damerau.Characters, err = damerau(ctx, a.runes, b.runes); err != nil {
+		return comparison, err
+	}
+	if comparison.Damerau.Words, err = damerau(ctx, a.terms, b.terms); err != nil {
+		return comparison, err
+	}
+	comparison.LCS.Characters, err = lcsLength(ctx, a.runes, b.runes)
+	return comparison, err
+}
+
+// levenshtein returns the number of insertions, deletions and substitutions
+// turning a into b. Like the other dynamic programs of this file, it checks
+// ctx after every row.
+func levenshtein[T comparable](ctx context.Context, a, b []T) (int, error) {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)], nil
+}
+
+// damerau returns the optimal string alignment distance between a and b: the
+// Levenshtein distance where swapping two adjacent elements also counts as one
+// edit, as long as no element is edited twice.
+func damerau[T comparable](ctx context.Context, a, b []T) (int, error) {
+	beforePrevious := make([]int, len(b)+1)
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				current[j] = min(current[j], beforePrevious[j-2]+1)
+			}
+		}
+		beforePrevious, previous, current = previous, current, beforePrevious
+	}
+	return previous[len(b)], nil
+}
+
+func lcsLength[T comparable](ctx context.Context, a, b []T) (int, error) {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for i := len(a) - 1; i >= 0; i-- {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				current[j] = previous[j+1] + 1
+			} else {
+				current[j] = max(previous[j], current[j+1])
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous[0], nil
+}
+
+// lcsTable returns the lengths of the longest common subsequences of every
+// pair of suffixes of a and b, so that the subsequence itself can be followed
+// from the start.
+func lcsTable(ctx context.Context, a, b []string) ([][]int, error) {
+	table := make([][]int, len(a)+1)
+	for i := range table {
+		table[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				table[i][j] = table[i+1][j+1] + 1
+			} else {
+				table[i][j] = max(table[i+1][j], table[i][j+1])
+			}
+		}
+	}
+	return table, nil
+}
+
+// wordDiff follows the longest common subsequence of the words of a and b,
+// preferring deletions over insertions, and merges consecutive words with the
+// same operation into one change.
+func wordDiff(a, b comparedText, lcs [][]int) []domain.DiffChange {
+	changes := []domain.DiffChange{}
+	var op string
+	var text *comparedText
+	from, to := 0, 0
+	flush := func() {
+		if op != "" {
+			changes = append(changes, domain.DiffChange{
+				Op:   op,
+				Text: string(text.runes[text.tokens[from].Start:text.tokens[to].End]),
+			})
+		}
+	}
+	add := func(nextOp string, next *comparedText, index int) {
+		if nextOp != op {
+			flush()
+			op, text, from = nextOp, next, index
+		}
+		to = index
+	}
+
+	i, j := 0, 0
+	for i < len(a.terms) || j < len(b.terms) {
+		switch {
+		case i < len(a.terms) && j < len(b.terms) && a.terms[i] == b.terms[j]:
+			add(diffEqual, &a, i)
+			i++
+			j++
+		case j == len(b.terms) || i < len(a.terms) && lcs[i+1][j] >= lcs[i][j+1]:
+			add(diffDelete, &a, i)
+			i++
+		default:
+			add(diffInsert, &b, j)
+			j++
+		}
+	}
+	flush()
+	return changes
+}
+
+// shingles returns the set of runs of size consecutive terms. Texts shorter
+// than size form a single shingle.
+func shingles(terms []string, size int) map[string]bool {
+	set := make(map[string]bool)
+	if len(terms) > 0 && len(terms) < size {
+		set[strings.Join(terms, " ")] = true
+	}
+	for i := 0; i+size <= len(terms); i++ {
+		set[strings.Join(terms[i:i+size], " ")] = true
+	}
+	return set
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	shared := 0
+	for shingle := range a {
+		if b[shingle] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// cosine returns the cosine similarity of the term frequency vectors of a and
+// b.
+func cosine(a, b []string) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	frequencies := func(terms []string) map[string]float64 {
+		counts := make(map[string]float64)
+		for _, term := range terms {
+			counts[term]++
+		}
+		return counts
+	}
+	countsA, countsB := frequencies(a), frequencies(b)
+
+	var dot, normA, normB float64
+	for term, count := range countsA {
+		dot += count * countsB[term]
+		normA += count * count
+	}
+	for _, count := range countsB {
+		normB += count * count
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
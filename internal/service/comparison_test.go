@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"vm-chan/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestComparisonService(t *testing.T) {
+	service := NewComparisonService(NewTokenizer(), zap.NewNop())
+
+	compare := func(t *testing.T, req *domain.CompareRequest) []domain.TextComparison {
+		response, err := service.Compare(context.Background(), req)
+		require.NoError(t, err)
+		return response.Comparisons
+	}
+
+	t.Run("Metrics", func(t *testing.T) {
+		comparisons := compare(t, &domain.CompareRequest{
+			Texts:       []string{"The quick brown fox jumps.", "The quick red fox leaps!"},
+			ShingleSize: 2,
+		})
+
+		require.Len(t, comparisons, 1)
+		comparison := comparisons[0]
+		assert.Equal(t, 0, comparison.A)
+		assert.Equal(t, 1, comparison.B)
+		assert.Equal(t, domain.LevelMetric{Characters: 8, Words: 2}, comparison.Levenshtein)
+		assert.Equal(t, domain.LevelMetric{Characters: 8, Words: 2}, comparison.Damerau)
+		assert.Equal(t, domain.LevelMetric{Characters: 18, Words: 3}, comparison.LCS)
+		assert.Equal(t, 0.1429, comparison.Jaccard)
+		assert.Equal(t, 0.6, comparison.Cosine)
+		assert.Equal(t, []domain.DiffChange{
+			{Op: "equal", Text: "The quick"},
+			{Op: "delete", Text: "brown"},
+			{Op: "insert", Text: "red"},
+			{Op: "equal", Text: "fox"},
+			{Op: "delete", Text: "jumps"},
+			{Op: "insert", Text: "leaps"},
+		}, comparison.Diff)
+	})
+
+	t.Run("Transpositions", func(t *testing.T) {
+		comparison := compare(t, &domain.CompareRequest{Texts: []string{"form a team", "from team a"}})[0]
+
+		assert.Equal(t, domain.LevelMetric{Characters: 6, Words: 3}, comparison.Levenshtein)
+		assert.Equal(t, domain.LevelMetric{Characters: 5, Words: 2}, comparison.Damerau)
+	})
+
+	t.Run("Word metrics ignore case and punctuation", func(t *testing.T) {
+		comparison := compare(t, &domain.CompareRequest{Texts: []string{"Hello, World!", "hello world"}})[0]
+
+		assert.Equal(t, 0, comparison.Levenshtein.Words)
+		assert.Equal(t, 1.0, comparison.Jaccard)
+		assert.Equal(t, 1.0, comparison.Cosine)
+		assert.Equal(t, []domain.DiffChange{{Op: "equal", Text: "Hello, World"}}, comparison.Diff)
+	})
+
+	t.Run("Every pair is compared", func(t *testing.T) {
+		comparisons := compare(t, &domain.CompareRequest{Texts: []string{"a", "b", "c"}})
+
+		require.Len(t, comparisons, 3)
+		assert.Equal(t, [2]int{0, 1}, [2]int{comparisons[0].A, comparisons[0].B})
+		assert.Equal(t, [2]int{0, 2}, [2]int{comparisons[1].A, comparisons[1].B})
+		assert.Equal(t, [2]int{1, 2}, [2]int{comparisons[2].A, comparisons[2].B})
+	})
+
+	t.Run("Cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := service.Compare(ctx, &domain.CompareRequest{
+			Texts: []string{strings.Repeat("a", maxComparedRunes), strings.Repeat("b", maxComparedRunes)},
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Invalid requests", func(t *testing.T) {
+		for name, req := range map[string]*domain.CompareRequest{
+			"single text":    {Texts: []string{"only"}},
+			"too many texts": {Texts: make([]string, maxComparedTexts+1)},
+			"negative size":  {Texts: []string{"a", "b"}, ShingleSize: -1},
+			"long text":      {Texts: []string{strings.Repeat("a", maxComparedRunes+1), "b"}},
+			"long texts": {Texts: []string{
+				strings.Repeat("a", maxComparedRunes), strings.Repeat("b", maxComparedRunes), "c",
+			}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := service.Compare(context.Background(), req)
+				assert.ErrorIs(t, err, domain.ErrInvalidInput)
+			})
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+
+	"vm-chan/internal/domain"
+)
+
+type countsAnalyzer struct{}
+
+func NewCountsAnalyzer() Analyzer {
+	return &countsAnalyzer{}
+}
+
+func (a *countsAnalyzer) Name() string {
+	return "counts"
+}
+
+func (a *countsAnalyzer) Version() string {
+	return "1.1.0"
+}
+
+func (a *countsAnalyzer) Description() string {
+	return "Word, vowel, consonant and other letter counts"
+}
+
+func (a *countsAnalyzer) Analyze(ctx context.Context, doc *Document) (interface{}, error) {
+	letters := doc.letterCounts()
+
+	return &domain.CountsResult{
+		WordCount:        len(doc.Tokens()),
+		VowelCount:       letters.vowels,
+		ConsonantCount:   letters.consonants,
+		OtherLetterCount: letters.other,
+	}, nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"vm-chan/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	maxCustomWordRunes = 64
+	maxCustomWords     = 10000
+)
+
+var errNoUser = errors.New("no authenticated user in context")
+
+type dictionaryService struct {
+	repo      domain.DictionaryRepository
+	tokenizer Tokenizer
+	logger    *zap.Logger
+}
+
+// NewDictionaryService returns a service managing the custom dictionary of
+// the authenticated user, whose words the spell analyzer accepts.
+func NewDictionaryService(repo domain.DictionaryRepository, tokenizer Tokenizer, logger *zap.Logger) domain.DictionaryService {
+	return &dictionaryService{
+		repo:      repo,
+		tokenizer: tokenizer,
+		logger:    logger,
+	}
+}
+
+func (s *dictionaryService) Words(ctx context.Context) (*domain.DictionaryResponse, error) {
+	user, ok := domain.UserFromContext(ctx)
+	if !ok {
+		return nil, errNoUser
+	}
+	return s.response(ctx, user.ID)
+}
+
+func (s *dictionaryService) AddWords(ctx context.Context, req *domain.DictionaryRequest) (*domain.DictionaryResponse, error) {
+	user, ok := domain.UserFromContext(ctx)
+	if !ok {
+		return nil, errNoUser
+	}
+
+	for _, word := range req.Words {
+		tokens := s.tokenizer.Tokenize(word)
+		if len(tokens) != 1 || tokens[0].Start != 0 || tokens[0].End != utf8.RuneCountInString(word) {
+			return nil, fmt.Errorf("%w: %q is not a single word", domain.ErrInvalidInput, word)
+		}
+		if tokens[0].End > maxCustomWordRunes {
+			return nil, fmt.Errorf("%w: words are limited to %d characters", domain.ErrInvalidInput, maxCustomWordRunes)
+		}
+	}
+
+	existing, err := s.repo.Words(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing)+len(req.Words) > maxCustomWords {
+		return nil, fmt.Errorf("%w: custom dictionaries are limited to %d words", domain.ErrInvalidInput, maxCustomWords)
+	}
+
+	if err := s.repo.AddWords(ctx, user.ID, req.Words); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Custom dictionary words added", zap.String("user_id", user.ID), zap.Int("words", len(req.Words)))
+
+	return s.response(ctx, user.ID)
+}
+
+func (s *dictionaryService) RemoveWord(ctx context.Context, word string) (*domain.DictionaryResponse, error) {
+	user, ok := domain.UserFromContext(ctx)
+	if !ok {
+		return nil, errNoUser
+	}
+
+	if err := s.repo.RemoveWord(ctx, user.ID, word); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Custom dictionary word removed", zap.String("user_id", user.ID))
+
+	return s.response(ctx, user.ID)
+}
+
+func (s *dictionaryService) response(ctx context.Context, userID string) (*domain.DictionaryResponse, error) {
+	words, err := s.repo.Words(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.DictionaryResponse{Words: words}, nil
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"bufio"
+	"embed"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+//go:embed data/segmentation/*.txt
+var segmentationFiles embed.FS
+
+const unknownCharacterCost = 1.5
+
+// segmentationDictionary holds the words of languages written without spaces.
+type segmentationDictionary struct {
+	words     map[string]bool
+	maxLength int
+}
+
+var (
+	segmentationOnce sync.Once
+	segmentationDict *segmentationDictionary
+)
+
+func defaultSegmentationDictionary() *segmentationDictionary {
+	segmentationOnce.Do(func() {
+		segmentationDict = loadSegmentationDictionary()
+	})
+	return segmentationDict
+}
+
+func loadSegmentationDictionary() *segmentationDictionary {
+	dictionary := &segmentationDictionary{words: make(map[string]bool)}
+
+	entries, err := segmentationFiles.ReadDir("data/segmentation")
+	if err != nil {
+		return dictionary
+	}
+
+	for _, entry := range entries {
+		file, err := segmentationFiles.Open("data/segmentation/" + entry.Name())
+		if err != nil {
+			continue
+		}
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			dictionary.add(scanner.Text())
+		}
+		_ = file.Close()
+	}
+
+	return dictionary
+}
+
+func (d *segmentationDictionary) add(word string) {
+	word = strings.TrimSpace(word)
+	if word == "" || strings.HasPrefix(word, "#") {
+		return
+	}
+
+	d.words[word] = true
+	if length := len([]rune(word)); length > d.maxLength {
+		d.maxLength = length
+	}
+}
+
+// segment splits runes into the sequence of dictionary words with the lowest
+// cost, where every word costs 1 and every character not covered by a word
+// costs more, so that longer known words are preferred. Consecutive unknown
+// characters are kept together except for ideographs, which are words of their
+// own. It returns [start, end) spans relative to runes.
+func (d *segmentationDictionary) segment(runes []rune) [][2]int {
+	n := len(runes)
+	cost := make([]float64, n+1)
+	from := make([]int, n+1)
+	known := make([]bool, n+1)
+	for i := 1; i <= n; i++ {
+		cost[i] = -1
+	}
+
+	for i := 0; i < n; i++ {
+		if cost[i] < 0 || isCombiningMark(runes[i]) && i > 0 {
+			continue
+		}
+
+		unknownEnd := i + 1
+		for unknownEnd < n && isCombiningMark(runes[unknownEnd]) {
+			unknownEnd++
+		}
+		d.relax(cost, from, known, i, unknownEnd, cost[i]+unknownCharacterCost, false)
+
+		for length := 1; length <= d.maxLength && i+length <= n; length++ {
+			end := i + length
+			if end < n && isCombiningMark(runes[end]) {
+				continue
+			}
+			if d.words[string(runes[i:end])] {
+				d.relax(cost, from, known, i, end, cost[i]+1, true)
+			}
+		}
+	}
+
+	var spans [][2]int
+	for end := n; end > 0; end = from[end] {
+		spans = append(spans, [2]int{from[end], end})
+	}
+	for i, j := 0, len(spans)-1; i < j; i, j = i+1, j-1 {
+		spans[i], spans[j] = spans[j], spans[i]
+	}
+
+	return mergeUnknownSpans(runes, spans, known)
+}
+
+func (d *segmentationDictionary) relax(cost []float64, from []int, known []bool, start, end int, value float64, isWord bool) {
+	if cost[end] < 0 || value < cost[end] {
+		cost[end] = value
+		from[end] = start
+		known[end] = isWord
+	}
+}
+
+func mergeUnknownSpans(runes []rune, spans [][2]int, known []bool) [][2]int {
+	merged := make([][2]int, 0, len(spans))
+	for _, span := range spans {
+		last := len(merged) - 1
+		if last >= 0 && !known[span[1]] && !known[merged[last][1]] &&
+			!unicode.Is(unicode.Han, runes[span[0]]) && !unicode.Is(unicode.Han, runes[merged[last][0]]) {
+			merged[last][1] = span[1]
+			continue
+		}
+		merged = append(merged, span)
+	}
+	return merged
+}
+
+func isCombiningMark(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Mc, unicode.Me)
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"sort"
+
+	"vm-chan/internal/domain"
+)
+
+// Document is the text being analyzed together with the lazily computed
+// intermediate results shared between analyzers, so that each of them is
+// derived only once per request.
+type Document struct {
+	Text     string
+	Language string
+	Options  domain.AnalysisOptions
+
+	phonology    *phonology
+	tokenizer    Tokenizer
+	runeList     []rune
+	tokens       []Token
+	termList     []string
+	stemList     []string
+	sentenceList []sentenceSpan
+	letters      *letterCounts
+	detection    *domain.LanguageResult
+	fingerprints *textFingerprint
+}
+
+func newDocument(text string, phonology *phonology, tokenizer Tokenizer) *Document {
+	return &Document{
+		Text:      text,
+		Language:  phonology.language,
+		phonology: phonology,
+		tokenizer: tokenizer,
+	}
+}
+
+// Tokens returns the words of the document in text order.
+func (d *Document) Tokens() []Token {
+	if d.tokens == nil {
+		d.tokens = d.tokenizer.Tokenize(d.Text)
+		if d.tokens == nil {
+			d.tokens = []Token{}
+		}
+	}
+	return d.tokens
+}
+
+func (d *Document) runes() []rune {
+	if d.runeList == nil {
+		d.runeList = []rune(d.Text)
+	}
+	return d.runeList
+}
+
+// tokensIn returns the tokens that start within [start, end).
+func (d *Document) tokensIn(start, end int) []Token {
+	from, to := d.tokenRange(start, end)
+	return d.Tokens()[from:to]
+}
+
+// tokenRange returns the index range of the tokens that start within
+// [start, end).
+func (d *Document) tokenRange(start, end int) (from, to int) {
+	tokens := d.Tokens()
+	from = sort.Search(len(tokens), func(i int) bool { return tokens[i].Start >= start })
+	to = sort.Search(len(tokens), func(i int) bool { return tokens[i].Start >= end })
+	return from, to
+}
+
+// terms returns the case-folded form of every token, aligned with Tokens.
+func (d *Document) terms() []string {
+	if d.termList == nil {
+		tokens := d.Tokens()
+		d.termList = make([]string, len(tokens))
+		for i, token := range tokens {
+			d.termList[i] = foldTerm(token.Text)
+		}
+	}
+	return d.termList
+}
+
+// wordForms returns the form under which every token is counted as a word,
+// aligned with Tokens: its stem when the stem option is set, otherwise its
+// case-folded term.
+func (d *Document) wordForms() ([]string, error) {
+	if !d.Options.Stem {
+		return d.terms(), nil
+	}
+	if d.stemList == nil {
+		stem, err := morphologyFor(d.Language, morphologyStem)
+		if err != nil {
+			return nil, err
+		}
+		terms := d.terms()
+		d.stemList = make([]string, len(terms))
+		for i, term := range terms {
+			d.stemList[i] = stem(term)
+		}
+	}
+	return d.stemList, nil
+}
+
+func (d *Document) sentences() []sentenceSpan {
+	if d.sentenceList == nil {
+		d.sentenceList = splitSentences(d.Text)
+	}
+	return d.sentenceList
+}
+
+// fingerprint returns the SimHash and MinHash signatures of the document, or
+// nil when it has no words.
+func (d *Document) fingerprint() *textFingerprint {
+	if d.fingerprints == nil {
+		d.fingerprints = fingerprintTerms(d.terms())
+	}
+	return d.fingerprints
+}
+
+func (d *Document) letterCounts() letterCounts {
+	if d.letters == nil {
+		counts := d.phonology.countLetters(d.Text)
+		d.letters = &counts
+	}
+	return *d.letters
+}
+
+// languageDetection returns the languages the text appears to be written in,
+// regardless of the language the document is analyzed as.
+func (d *Document) languageDetection() *domain.LanguageResult {
+	if d.detection == nil {
+		d.detection = detectLanguage(d.Text)
+	}
+	return d.detection
+}
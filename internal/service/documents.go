@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"vm-chan/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	maxDocumentRunes      = 100000
+	maxDocumentTitleRunes = 200
+	defaultDocumentLimit  = 20
+	maxDocumentLimit      = 100
+)
+
+type documentService struct {
+	repo         domain.DocumentRepository
+	analysis     domain.TextAnalysisService
+	search       *SearchIndex
+	fingerprints *FingerprintIndex
+	logger       *zap.Logger
+}
+
+// NewDocumentService returns a service storing the documents of the
+// authenticated user together with their analysis, which the text analysis
+// service runs on every write, fingerprinting the text under the ID of the
+// document. When search is not nil, it is kept up to date with the text of
+// the stored documents, and when fingerprints is not nil, the fingerprints of
+// deleted documents are removed from it.
+func NewDocumentService(repo domain.DocumentRepository, analysis domain.TextAnalysisService, search *SearchIndex, fingerprints *FingerprintIndex, logger *zap.Logger) domain.DocumentService {
+	return &documentService{
+		repo:         repo,
+		analysis:     analysis,
+		search:       search,
+		fingerprints: fingerprints,
+		logger:       logger,
+	}
+}
+
+func (s *documentService) Create(ctx context.Context, req *domain.DocumentRequest) (*domain.Document, error) {
+	user, ok := domain.UserFromContext(ctx)
+	if !ok {
+		return nil, errNoUser
+	}
+	id, err := newDocumentID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	document := &domain.Document{ID: id, UserID: user.ID, CreatedAt: now}
+	if err := s.analyze(ctx, document, req, now); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, document); err != nil {
+		return nil, err
+	}
+	s.index(document)
+
+	s.logger.Info("Document created", zap.String("user_id", user.ID), zap.String("document_id", id))
+	return document, nil
+}
+
+func (s *documentService) Get(ctx context.Context, id string) (*domain.Document, error) {
+	user, ok := domain.UserFromContext(ctx)
+	if !ok {
+		return nil, errNoUser
+	}
+	return s.repo.Get(ctx, user.ID, id)
+}
+
+func (s *documentService) List(ctx context.Context, limit, offset int) (*domain.DocumentListResponse, error) {
+	user, ok := domain.UserFromContext(ctx)
+	if !ok {
+		return nil, errNoUser
+	}
+	if limit == 0 {
+		limit = defaultDocumentLimit
+	}
+	if limit < 0 || limit > maxDocumentLimit || offset < 0 {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d and offset positive", domain.ErrInvalidInput, maxDocumentLimit)
+	}
+
+	documents, total, err := s.repo.List(ctx, user.ID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	response := &domain.DocumentListResponse{
+		Documents: make([]domain.DocumentSummary, 0, len(documents)),
+		Total:     total,
+	}
+	for _, document := range documents {
+		summary := domain.DocumentSummary{
+			ID:        document.ID,
+			Title:     document.Title,
+			Language:  document.Language,
+			CreatedAt: document.CreatedAt,
+			UpdatedAt: document.UpdatedAt,
+		}
+		if document.Analysis != nil {
+			summary.Language = document.Analysis.Language
+			summary.WordCount = document.Analysis.WordCount
+		}
+		response.Documents = append(response.Documents, summary)
+	}
+	return response, nil
+}
+
+func (s *documentService) Update(ctx context.Context, id string, req *domain.DocumentRequest) (*domain.Document, error) {
+	user, ok := domain.UserFromContext(ctx)
+	if !ok {
+		return nil, errNoUser
+	}
+	document, err := s.repo.Get(ctx, user.ID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.analyze(ctx, document, req, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, document); err != nil {
+		return nil, err
+	}
+	s.index(document)
+
+	s.logger.Info("Document updated", zap.String("user_id", user.ID), zap.String("document_id", id))
+	return document, nil
+}
+
+func (s *documentService) Delete(ctx context.Context, id string) error {
+	user, ok := domain.UserFromContext(ctx)
+	if !ok {
+		return errNoUser
+	}
+	if err := s.repo.Delete(ctx, user.ID, id); err != nil {
+		return err
+	}
+	// The document is gone whether or not it can be removed from the search
+	// index, which drops it when it is next found, and its fingerprint.
+	if s.search != nil {
+		if err := s.search.Remove(user.ID, id); err != nil {
+			s.logger.Error("Failed to remove document from search index", zap.String("document_id", id), zap.Error(err))
+		}
+	}
+	if s.fingerprints != nil {
+		if err := s.fingerprints.Remove(ctx, fingerprintTenant(user), id); err != nil {
+			s.logger.Error("Failed to remove document fingerprint", zap.String("document_id", id), zap.Error(err))
+		}
+	}
+
+	s.logger.Info("Document deleted", zap.String("user_id", user.ID), zap.String("document_id", id))
+	return nil
+}
+
+// analyze sets the fields of a document from a request and analyzes its
+// text.
+func (s *documentService) analyze(ctx context.Context, document *domain.Document, req *domain.DocumentRequest, now time.Time) error {
+	if utf8.RuneCountInString(req.Text) > maxDocumentRunes {
+		return fmt.Errorf("%w: text is longer than %d characters", domain.ErrInvalidInput, maxDocumentRunes)
+	}
+	if utf8.RuneCountInString(req.Title) > maxDocumentTitleRunes {
+		return fmt.Errorf("%w: title is longer than %d characters", domain.ErrInvalidInput, maxDocumentTitleRunes)
+	}
+
+	analysis, err := s.analysis.AnalyzeText(ctx, &domain.TextAnalysisRequest{
+		Sentence:   req.Text,
+		Language:   req.Language,
+		Analyses:   req.Analyses,
+		Options:    req.Options,
+		DocumentID: document.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	document.Title = req.Title
+	document.Text = req.Text
+	document.Language = req.Language
+	document.Analyses = req.Analyses
+	document.Options = req.Options
+	document.Analysis = analysis
+	document.UpdatedAt = now
+	return nil
+}
+
+// index adds the text of a stored document to the search index. The
+// document is stored whether or not it can be indexed; the index catches up
+// when it is synchronized with the repository at startup.
+func (s *documentService) index(document *domain.Document) {
+	if s.search == nil {
+		return
+	}
+	if err := s.search.Add(document); err != nil {
+		s.logger.Error("Failed to index document", zap.String("document_id", document.ID), zap.Error(err))
+	}
+}
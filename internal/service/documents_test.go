@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"vm-chan/internal/domain"
+	"vm-chan/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDocumentService(t *testing.T) {
+	db, err := repository.OpenSQLite(filepath.Join(t.TempDir(), "documents.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	sqlite, err := repository.NewSQLiteDocumentRepository(db, zap.NewNop())
+	require.NoError(t, err)
+
+	for name, repo := range map[string]domain.DocumentRepository{
+		"Memory": repository.NewDocumentRepository(zap.NewNop()),
+		"SQLite": sqlite,
+	} {
+		t.Run(name, func(t *testing.T) {
+			testDocumentService(t, repo)
+		})
+	}
+}
+
+func testDocumentService(t *testing.T, repo domain.DocumentRepository) {
+	registry, err := NewAnalyzerRegistry(DefaultAnalyzers(AnalyzerResources{})...)
+	require.NoError(t, err)
+	fingerprints := NewFingerprintIndex(repository.NewFingerprintRepository(zap.NewNop()))
+	analysis := NewTextAnalysisService(registry, NewTokenizer(), fingerprints, zap.NewNop())
+	service := NewDocumentService(repo, analysis, nil, fingerprints, zap.NewNop())
+	similar := func(text string) []domain.DuplicateMatch {
+		matches, err := fingerprints.Similar(context.Background(), "user:alice",
+			testDocument(text, "en").fingerprint(), defaultDuplicateThreshold)
+		require.NoError(t, err)
+		return matches
+	}
+
+	alice := domain.WithUser(context.Background(), &domain.User{ID: "alice"})
+	bob := domain.WithUser(context.Background(), &domain.User{ID: "bob"})
+
+	created, err := service.Create(alice, &domain.DocumentRequest{
+		Title:    "Greeting",
+		Text:     "Hello brave new world",
+		Language: "en",
+		Analyses: []string{"counts"},
+		Options:  domain.AnalysisOptions{TopN: 3},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, created.ID)
+	assert.Equal(t, created.ID, created.Analysis.DocumentID, "the fingerprint is recorded under the document ID")
+	assert.Equal(t, 4, created.Analysis.WordCount)
+	assert.Contains(t, created.Analysis.Results, "counts")
+
+	fetched, err := service.Get(alice, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created.Text, fetched.Text)
+	assert.Equal(t, created.Title, fetched.Title)
+	assert.Equal(t, []string{"counts"}, fetched.Analyses)
+	assert.Equal(t, 3, fetched.Options.TopN)
+	assert.Equal(t, 4, fetched.Analysis.WordCount)
+	assert.Contains(t, fetched.Analysis.Results, "counts")
+	assert.True(t, created.CreatedAt.Equal(fetched.CreatedAt))
+
+	_, err = service.Get(bob, created.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound, "documents of other users are not visible")
+
+	t.Run("List", func(t *testing.T) {
+		second, err := service.Create(alice, &domain.DocumentRequest{Text: "Bonjour tout le monde", Language: "fr"})
+		require.NoError(t, err)
+
+		list, err := service.List(alice, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 2, list.Total)
+		require.Len(t, list.Documents, 2)
+		assert.Equal(t, second.ID, list.Documents[0].ID)
+		assert.Equal(t, "fr", list.Documents[0].Language)
+		assert.Equal(t, 4, list.Documents[0].WordCount)
+
+		page, err := service.List(alice, 1, 1)
+		require.NoError(t, err)
+		require.Len(t, page.Documents, 1)
+		assert.Equal(t, created.ID, page.Documents[0].ID)
+
+		list, err = service.List(bob, 0, 0)
+		require.NoError(t, err)
+		assert.Empty(t, list.Documents)
+
+		_, err = service.List(alice, 1000, 0)
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+
+	t.Run("Walk", func(t *testing.T) {
+		other, err := service.Create(bob, &domain.DocumentRequest{Text: "Hola mundo", Language: "es"})
+		require.NoError(t, err)
+
+		var ids []string
+		require.NoError(t, repo.Walk(context.Background(), func(document *domain.Document) error {
+			ids = append(ids, document.ID)
+			return nil
+		}))
+		assert.Len(t, ids, 3)
+		assert.Contains(t, ids, created.ID)
+		assert.Contains(t, ids, other.ID)
+
+		stop := errors.New("stop")
+		visited := 0
+		err = repo.Walk(context.Background(), func(*domain.Document) error {
+			visited++
+			return stop
+		})
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, 1, visited)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		updated, err := service.Update(alice, created.ID, &domain.DocumentRequest{Text: "Goodbye world", Language: "en"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, updated.Analysis.WordCount)
+		assert.Equal(t, created.ID, updated.Analysis.DocumentID)
+		assert.Empty(t, similar("Hello brave new world"), "the fingerprint of the previous text is replaced")
+		matches := similar("Goodbye world")
+		require.Len(t, matches, 1)
+		assert.Equal(t, created.ID, matches[0].DocumentID)
+		assert.True(t, created.CreatedAt.Equal(updated.CreatedAt))
+		assert.False(t, updated.UpdatedAt.Before(created.UpdatedAt))
+
+		fetched, err := service.Get(alice, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Goodbye world", fetched.Text)
+		assert.Empty(t, fetched.Title)
+
+		_, err = service.Update(bob, created.ID, &domain.DocumentRequest{Text: "Hijacked"})
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		_, err = service.Update(alice, created.ID, &domain.DocumentRequest{Text: "text", Analyses: []string{"unknown"}})
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		assert.ErrorIs(t, service.Delete(bob, created.ID), domain.ErrNotFound)
+		require.NoError(t, service.Delete(alice, created.ID))
+		assert.Empty(t, similar("Goodbye world"), "the fingerprint of a deleted document is removed")
+		_, err := service.Get(alice, created.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.ErrorIs(t, service.Delete(alice, created.ID), domain.ErrNotFound)
+	})
+}
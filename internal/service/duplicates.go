@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"vm-chan/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultDuplicateThreshold = 0.5
+	defaultClusterThreshold   = 0.8
+	documentIDBytes           = 8
+)
+
+// fingerprintTenant returns the key under which the texts of a user are
+// indexed: the tenant of the user, or the user alone when it has none.
+func fingerprintTenant(user *domain.User) string {
+	if user.TenantID != "" {
+		return user.TenantID
+	}
+	return "user:" + user.ID
+}
+
+func newDocumentID() (string, error) {
+	id := make([]byte, documentIDBytes)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// recordFingerprint stores the fingerprint of an analyzed document under the
+// tenant of the user found in the context, returning the ID of the document:
+// id, replacing its previous fingerprint, or a new ID when id is empty.
+// Documents without words or user are not recorded, and a document with an
+// ID that no longer has words loses its fingerprint.
+func recordFingerprint(ctx context.Context, index *FingerprintIndex, doc *Document, id string) (string, error) {
+	user, ok := domain.UserFromContext(ctx)
+	if !ok {
+		return "", nil
+	}
+	fingerprint := doc.fingerprint()
+	if fingerprint == nil {
+		if id == "" {
+			return "", nil
+		}
+		return id, index.Remove(ctx, fingerprintTenant(user), id)
+	}
+
+	if id == "" {
+		var err error
+		if id, err = newDocumentID(); err != nil {
+			return "", err
+		}
+	}
+	err := index.Add(ctx, &domain.Fingerprint{
+		DocumentID:  id,
+		TenantID:    fingerprintTenant(user),
+		UserID:      user.ID,
+		SimHash:     fingerprint.simHash,
+		MinHash:     fingerprint.minHash,
+		SubmittedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+type duplicatesAnalyzer struct {
+	index *FingerprintIndex
+}
+
+// NewDuplicatesAnalyzer returns an analyzer looking up the near-duplicates of
+// a text among the texts previously analyzed for the tenant of the user found
+// in the request context. With a nil index, requesting it is an error.
+func NewDuplicatesAnalyzer(index *FingerprintIndex) Analyzer {
+	return &duplicatesAnalyzer{index: index}
+}
+
+func (a *duplicatesAnalyzer) Name() string {
+	return "duplicates"
+}
+
+func (a *duplicatesAnalyzer) Version() string {
+	return "1.0.0"
+}
+
+func (a *duplicatesAnalyzer) Description() string {
+	return "Previously analyzed texts of the tenant that are near-duplicates of the text, by MinHash similarity " +
+		"of word shingles and SimHash distance"
+}
+
+func (a *duplicatesAnalyzer) Analyze(ctx context.Context, doc *Document) (interface{}, error) {
+	if a.index == nil {
+		return nil, fmt.Errorf("%w: duplicate detection is not enabled", domain.ErrInvalidInput)
+	}
+	threshold, err := duplicateThreshold(doc.Options.SimilarityThreshold, defaultDuplicateThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.DuplicatesResult{Matches: []domain.DuplicateMatch{}}
+	user, ok := domain.UserFromContext(ctx)
+	fingerprint := doc.fingerprint()
+	if !ok || fingerprint == nil {
+		return result, nil
+	}
+
+	result.Matches, err = a.index.Similar(ctx, fingerprintTenant(user), fingerprint, threshold)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func duplicateThreshold(threshold, fallback float64) (float64, error) {
+	if threshold < 0 || threshold > 1 {
+		return 0, fmt.Errorf("%w: similarity threshold must be between 0 and 1", domain.ErrInvalidInput)
+	}
+	if threshold == 0 {
+		return fallback, nil
+	}
+	return threshold, nil
+}
+
+type duplicatesService struct {
+	index  *FingerprintIndex
+	logger *zap.Logger
+}
+
+// NewDuplicatesService returns a service grouping the near-duplicate texts
+// analyzed for the tenant of the authenticated user.
+func NewDuplicatesService(index *FingerprintIndex, logger *zap.Logger) domain.DuplicatesService {
+	return &duplicatesService{
+		index:  index,
+		logger: logger,
+	}
+}
+
+func (s *duplicatesService) Clusters(ctx context.Context, threshold float64) (*domain.DuplicatesResponse, error) {
+	user, ok := domain.UserFromContext(ctx)
+	if !ok {
+		return nil, errNoUser
+	}
+	threshold, err := duplicateThreshold(threshold, defaultClusterThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters, err := s.index.Clusters(ctx, fingerprintTenant(user), threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Duplicate clusters listed",
+		zap.String("tenant", fingerprintTenant(user)),
+		zap.Float64("threshold", threshold),
+		zap.Int("clusters", len(clusters)),
+	)
+
+	return &domain.DuplicatesResponse{Threshold: threshold, Clusters: clusters}, nil
+}
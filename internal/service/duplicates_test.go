@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"vm-chan/internal/domain"
+	"vm-chan/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	duplicateOriginal = "Our quarterly report shows that revenue grew by twelve percent while operating costs " +
+		"remained flat, driven mostly by strong demand for the new subscription plans in Europe and Asia."
+	duplicateEdited = "Our quarterly report shows that revenue grew by twelve percent while operating costs " +
+		"remained flat, driven mostly by strong demand for the new subscription plans in Europe and Africa."
+	duplicateUnrelated = "The hiking trail follows the river through the pine forest before climbing steeply " +
+		"to the old stone hut, where walkers usually stop for lunch and enjoy the view of the valley."
+)
+
+func TestFingerprintTerms(t *testing.T) {
+	fingerprint := func(text string) *textFingerprint {
+		return testDocument(text, "en").fingerprint()
+	}
+	original, edited, unrelated := fingerprint(duplicateOriginal), fingerprint(duplicateEdited), fingerprint(duplicateUnrelated)
+
+	assert.Equal(t, original, fingerprint(duplicateOriginal))
+	assert.Equal(t, 1.0, minHashSimilarity(original.minHash, fingerprint("OUR QUARTERLY report shows that revenue "+
+		"grew by twelve percent, while operating costs remained flat; driven mostly by strong demand for the new "+
+		"subscription plans in Europe and Asia!").minHash))
+	assert.Greater(t, minHashSimilarity(original.minHash, edited.minHash), 0.8)
+	assert.Less(t, minHashSimilarity(original.minHash, unrelated.minHash), 0.1)
+	assert.LessOrEqual(t, simHashDistance(original.simHash, edited.simHash), 8)
+	assert.Greater(t, simHashDistance(original.simHash, unrelated.simHash), 16)
+	assert.Nil(t, fingerprint("!!!"))
+}
+
+func TestDuplicates(t *testing.T) {
+	index := NewFingerprintIndex(repository.NewFingerprintRepository(zap.NewNop()))
+	registry, err := NewAnalyzerRegistry(NewDuplicatesAnalyzer(index))
+	require.NoError(t, err)
+	analysis := NewTextAnalysisService(registry, NewTokenizer(), index, zap.NewNop())
+	duplicates := NewDuplicatesService(index, zap.NewNop())
+
+	acme := domain.WithUser(context.Background(), &domain.User{ID: "1", TenantID: "acme"})
+	acmeColleague := domain.WithUser(context.Background(), &domain.User{ID: "2", TenantID: "acme"})
+	other := domain.WithUser(context.Background(), &domain.User{ID: "3", TenantID: "globex"})
+
+	analyze := func(ctx context.Context, text string) *domain.TextAnalysisResponse {
+		response, err := analysis.AnalyzeText(ctx, &domain.TextAnalysisRequest{
+			Sentence:        text,
+			Language:        "en",
+			Analyses:        []string{"duplicates"},
+			KeepFingerprint: true,
+		})
+		require.NoError(t, err)
+		return response
+	}
+	matches := func(response *domain.TextAnalysisResponse) []domain.DuplicateMatch {
+		return response.Results["duplicates"].(*domain.DuplicatesResult).Matches
+	}
+
+	first := analyze(acme, duplicateOriginal)
+	require.NotEmpty(t, first.DocumentID)
+	assert.Empty(t, matches(first))
+
+	analyze(acme, duplicateUnrelated)
+	assert.Empty(t, matches(analyze(other, duplicateEdited)), "texts of other tenants are not matched")
+
+	unkept, err := analysis.AnalyzeText(acme, &domain.TextAnalysisRequest{Sentence: duplicateOriginal, Language: "en"})
+	require.NoError(t, err)
+	assert.Empty(t, unkept.DocumentID, "texts are only fingerprinted on request")
+
+	second := analyze(acmeColleague, duplicateEdited)
+	require.Len(t, matches(second), 1)
+	assert.Equal(t, first.DocumentID, matches(second)[0].DocumentID)
+	assert.Greater(t, matches(second)[0].Similarity, 0.8)
+	assert.False(t, matches(second)[0].SubmittedAt.IsZero())
+
+	t.Run("Clusters", func(t *testing.T) {
+		response, err := duplicates.Clusters(acme, 0)
+		require.NoError(t, err)
+
+		assert.Equal(t, 0.8, response.Threshold)
+		require.Len(t, response.Clusters, 1)
+		cluster := response.Clusters[0]
+		require.Len(t, cluster.Documents, 2)
+		assert.Equal(t, first.DocumentID, cluster.Documents[0].DocumentID)
+		assert.Equal(t, second.DocumentID, cluster.Documents[1].DocumentID)
+		assert.Equal(t, "2", cluster.Documents[1].UserID)
+
+		response, err = duplicates.Clusters(other, 0)
+		require.NoError(t, err)
+		assert.Empty(t, response.Clusters)
+
+		_, err = duplicates.Clusters(acme, 1.5)
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+
+	t.Run("File store", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "fingerprints.jsonl")
+		repo, err := repository.NewFileFingerprintRepository(path, zap.NewNop())
+		require.NoError(t, err)
+		index := NewFingerprintIndex(repo)
+		id, err := recordFingerprint(acme, index, testDocument(duplicateOriginal, "en"), "")
+		require.NoError(t, err)
+		_, err = recordFingerprint(acme, index, testDocument(duplicateUnrelated, "en"), "stored")
+		require.NoError(t, err)
+		_, err = recordFingerprint(acme, index, testDocument(duplicateEdited, "en"), "stored")
+		require.NoError(t, err)
+
+		similar := func(text string) []domain.DuplicateMatch {
+			reopened, err := repository.NewFileFingerprintRepository(path, zap.NewNop())
+			require.NoError(t, err)
+			found, err := NewFingerprintIndex(reopened).Similar(acme, "acme",
+				testDocument(text, "en").fingerprint(), defaultDuplicateThreshold)
+			require.NoError(t, err)
+			return found
+		}
+		found := similar(duplicateOriginal)
+		require.Len(t, found, 2)
+		assert.ElementsMatch(t, []string{id, "stored"}, []string{found[0].DocumentID, found[1].DocumentID})
+		assert.Empty(t, similar(duplicateUnrelated), "fingerprints are replaced by later ones of their document")
+
+		require.NoError(t, index.Remove(acme, "acme", "stored"))
+		found = similar(duplicateOriginal)
+		require.Len(t, found, 1)
+		assert.Equal(t, id, found[0].DocumentID)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		_, err := NewDuplicatesAnalyzer(nil).Analyze(acme, testDocument(duplicateOriginal, "en"))
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+}
+
+func TestLSHIndexSimHashBands(t *testing.T) {
+	signature := func(offset uint32) []uint32 {
+		minHash := make([]uint32, minHashPermutations)
+		for i := range minHash {
+			minHash[i] = offset + uint32(i)
+		}
+		return minHash
+	}
+	index := newLSHIndex()
+	simHash := uint64(0x0123456789abcdef)
+	index.add(&domain.Fingerprint{DocumentID: "a", SimHash: simHash, MinHash: signature(0)})
+
+	// Three differing bits, one in each of three bands, leave one band equal.
+	near := simHash ^ (1 | 1<<20 | 1<<40)
+	assert.Equal(t, []int{0}, index.candidates(near, signature(1000)))
+	far := simHash ^ (1 | 1<<20 | 1<<40 | 1<<60)
+	assert.Empty(t, index.candidates(far, signature(1000)))
+	assert.Equal(t, []int{0}, index.candidates(far, signature(0)), "MinHash bands still match")
+}
+
+// failingFingerprintRepository fails to store any fingerprint.
+type failingFingerprintRepository struct {
+	domain.FingerprintRepository
+}
+
+func (failingFingerprintRepository) Add(ctx context.Context, fingerprint *domain.Fingerprint) error {
+	return errors.New("disk full")
+}
+
+func TestTextAnalysisFingerprintFailure(t *testing.T) {
+	index := NewFingerprintIndex(failingFingerprintRepository{repository.NewFingerprintRepository(zap.NewNop())})
+	registry, err := NewAnalyzerRegistry(DefaultAnalyzers(AnalyzerResources{})...)
+	require.NoError(t, err)
+	analysis := NewTextAnalysisService(registry, NewTokenizer(), index, zap.NewNop())
+
+	ctx := domain.WithUser(context.Background(), &domain.User{ID: "1", TenantID: "acme"})
+	response, err := analysis.AnalyzeText(ctx, &domain.TextAnalysisRequest{Sentence: duplicateOriginal, Language: "en"})
+	require.NoError(t, err)
+	assert.Empty(t, response.DocumentID)
+	assert.Positive(t, response.WordCount)
+}
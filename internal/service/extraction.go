@@ -0,0 +1,499 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"vm-chan/internal/domain"
+)
+
+const (
+	entityEmail      = "email"
+	entityURL        = "url"
+	entityIPAddress  = "ip_address"
+	entityDateTime   = "datetime"
+	entityDate       = "date"
+	entityTime       = "time"
+	entityMoney      = "money"
+	entityPercentage = "percentage"
+	entityPhone      = "phone"
+	entityMention    = "mention"
+	entityHashtag    = "hashtag"
+)
+
+const (
+	currencyCodes  = `USD|EUR|GBP|JPY|CNY|CHF|CAD|AUD|INR|RUB|KRW|SEK|NOK|DKK|PLN|CZK|HUF|TRY|UAH|BRL|MXN`
+	currencySigns  = `[$€£¥₹₽₩₺₴]`
+	amountPattern  = `\d{1,3}(?:[,.\x{00A0}\x{202F}]\d{3})+(?:[.,]\d{1,2})?|\d+(?:[.,]\d{1,2})?`
+	monthNames     = `january|february|march|april|may|june|july|august|september|october|november|december`
+	monthAbbrevs   = `jan|feb|mar|apr|jun|jul|aug|sept|sep|oct|nov|dec`
+	monthPattern   = `(` + monthNames + `|` + monthAbbrevs + `)\.?`
+	minPhoneDigits = 7
+	maxPhoneDigits = 15
+)
+
+var currencySymbols = map[string]string{
+	"$": "USD", "€": "EUR", "£": "GBP", "¥": "JPY", "₹": "INR", "₽": "RUB", "₩": "KRW", "₺": "TRY", "₴": "UAH",
+}
+
+// trunkCallingCodes maps languages to the country calling code assumed for
+// national phone numbers written with a leading trunk prefix 0. English is
+// left out: its national numbers are read as North American numbers, which
+// have no trunk prefix 0.
+var trunkCallingCodes = map[string]string{
+	"de": "49", "fi": "358", "fr": "33", "nl": "31", "sv": "46", "tr": "90", "uk": "380",
+}
+
+// entityPattern finds one kind of entity. When the pattern has a subexpression
+// named "entity", only that part of the match is the entity, which lets a
+// pattern require what precedes it. normalize receives the submatches and
+// rejects false positives by returning false.
+type entityPattern struct {
+	kind      string
+	pattern   *regexp.Regexp
+	normalize func(match []string, language string) (string, bool)
+}
+
+// entityPatterns are in order of precedence: an entity overlapping one found
+// by an earlier pattern is dropped.
+var entityPatterns = []entityPattern{
+	{
+		kind:      entityURL,
+		pattern:   regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"]*[^\s<>".,;:!?'")\]}]`),
+		normalize: normalizeURL,
+	},
+	{
+		kind:      entityEmail,
+		pattern:   regexp.MustCompile(`[\p{L}\p{N}._%+\-]+@[\p{L}\p{N}\-]+(?:\.[\p{L}\p{N}\-]+)*\.\p{L}{2,}`),
+		normalize: normalizeEmail,
+	},
+	{
+		kind: entityDateTime,
+		pattern: regexp.MustCompile(
+			`\b(\d{4})-(\d{2})-(\d{2})[T ](\d{2}):(\d{2})(?::(\d{2})(?:\.\d+)?)?(Z|[+-]\d{2}:?\d{2})?\b`),
+		normalize: normalizeISODateTime,
+	},
+	{
+		kind:    entityDate,
+		pattern: regexp.MustCompile(`\b(\d{4})([-/])(\d{1,2})([-/])(\d{1,2})\b`),
+		normalize: func(match []string, language string) (string, bool) {
+			if match[2] != match[4] {
+				return "", false
+			}
+			return isoDate(match[1], match[3], match[5])
+		},
+	},
+	{
+		kind:      entityDate,
+		pattern:   regexp.MustCompile(`\b(\d{1,2})([/.])(\d{1,2})([/.])(\d{4})\b`),
+		normalize: normalizeNumericDate,
+	},
+	{
+		kind:    entityDate,
+		pattern: regexp.MustCompile(`(?i)\b` + monthPattern + `\s+(\d{1,2})(?:st|nd|rd|th)?,?\s+(\d{4})\b`),
+		normalize: func(match []string, language string) (string, bool) {
+			return isoDate(match[3], monthNumber(match[1]), match[2])
+		},
+	},
+	{
+		kind: entityDate,
+		pattern: regexp.MustCompile(
+			`(?i)\b(\d{1,2})(?:st|nd|rd|th)?\s+(?:of\s+)?` + monthPattern + `,?\s+(\d{4})\b`),
+		normalize: func(match []string, language string) (string, bool) {
+			return isoDate(match[3], monthNumber(match[2]), match[1])
+		},
+	},
+	{
+		kind:      entityIPAddress,
+		pattern:   regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`),
+		normalize: normalizeIP,
+	},
+	{
+		kind:      entityIPAddress,
+		pattern:   regexp.MustCompile(`(?i)(?:^|[^\w:])(?P<entity>(?:[0-9a-f]{0,4}:){2,7}[0-9a-f]{0,4})`),
+		normalize: normalizeIP,
+	},
+	{
+		kind:      entityTime,
+		pattern:   regexp.MustCompile(`(?i)\b(\d{1,2}):(\d{2})(?::(\d{2}))?(?:\s?([ap])(?:\.m\.|m\b))?`),
+		normalize: normalizeTime,
+	},
+	{
+		kind:    entityTime,
+		pattern: regexp.MustCompile(`(?i)\b(\d{1,2})()()\s?([ap])(?:\.m\.|m\b)`),
+		normalize: func(match []string, language string) (string, bool) {
+			return normalizeTime([]string{match[0], match[1], "00", "", match[4]}, language)
+		},
+	},
+	{
+		kind:    entityMoney,
+		pattern: regexp.MustCompile(`(` + currencySigns + `|\b(?:` + currencyCodes + `))\s?(` + amountPattern + `)`),
+		normalize: func(match []string, language string) (string, bool) {
+			return normalizeMoney(match[1], match[2])
+		},
+	},
+	{
+		kind: entityMoney,
+		pattern: regexp.MustCompile(
+			`\b(` + amountPattern + `)\s?(` + currencySigns + `|(?:` + currencyCodes + `)\b)`),
+		normalize: func(match []string, language string) (string, bool) {
+			return normalizeMoney(match[2], match[1])
+		},
+	},
+	{
+		kind:    entityPercentage,
+		pattern: regexp.MustCompile(`(?i)\b(\d+(?:[.,]\d+)?)\s?(?:%|percent\b|per cent\b)`),
+		normalize: func(match []string, language string) (string, bool) {
+			return strings.Replace(match[1], ",", ".", 1) + "%", true
+		},
+	},
+	{
+		kind:      entityPhone,
+		pattern:   regexp.MustCompile(`(?:\+|\()?\b\d[\d \-.()]{5,}\d\b`),
+		normalize: normalizePhone,
+	},
+	{
+		kind:    entityMention,
+		pattern: regexp.MustCompile(`(?:^|[^\p{L}\p{N}_])(?P<entity>@[\p{L}\p{N}_]+)`),
+		normalize: func(match []string, language string) (string, bool) {
+			return foldTerm(match[len(match)-1]), true
+		},
+	},
+	{
+		kind:    entityHashtag,
+		pattern: regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&])(?P<entity>#[\p{L}\p{N}_]*\p{L}[\p{L}\p{N}_]*)`),
+		normalize: func(match []string, language string) (string, bool) {
+			return foldTerm(match[len(match)-1]), true
+		},
+	},
+}
+
+type entitiesAnalyzer struct{}
+
+func NewEntitiesAnalyzer() Analyzer {
+	return &entitiesAnalyzer{}
+}
+
+func (a *entitiesAnalyzer) Name() string {
+	return "entities"
+}
+
+func (a *entitiesAnalyzer) Version() string {
+	return "1.0.0"
+}
+
+func (a *entitiesAnalyzer) Description() string {
+	return "Emails, URLs, IP addresses, phone numbers, dates and times, money amounts, percentages, " +
+		"mentions and hashtags with their normalized value and offsets"
+}
+
+func (a *entitiesAnalyzer) Analyze(ctx context.Context, doc *Document) (interface{}, error) {
+	return &domain.EntitiesResult{Entities: extractEntities(doc.Text, doc.Language)}, nil
+}
+
+// entityMatch is an entity found by a pattern, located by byte offsets until
+// its code point offsets are set.
+type entityMatch struct {
+	entity     domain.Entity
+	start, end int
+	precedence int
+}
+
+// extractEntities returns the entities of text in text order. language
+// decides how ambiguous dates and national phone numbers are read.
+func extractEntities(text, language string) []domain.Entity {
+	matches := resolveEntityMatches(text, matchEntityPatterns(text, language, entityPatterns))
+
+	entities := make([]domain.Entity, 0, len(matches))
+	for _, match := range matches {
+		entities = append(entities, match.entity)
+	}
+	return entities
+}
+
+// matchEntityPatterns returns every match of patterns in text, taking the
+// precedence of a match from the position of its pattern.
+func matchEntityPatterns(text, language string, patterns []entityPattern) []entityMatch {
+	var matches []entityMatch
+	for precedence, p := range patterns {
+		group := max(p.pattern.SubexpIndex("entity"), 0)
+		for _, indexes := range p.pattern.FindAllStringSubmatchIndex(text, -1) {
+			submatches := make([]string, len(indexes)/2)
+			for i := range submatches {
+				if indexes[2*i] >= 0 {
+					submatches[i] = text[indexes[2*i]:indexes[2*i+1]]
+				}
+			}
+
+			normalized, ok := p.normalize(submatches, language)
+			if !ok {
+				continue
+			}
+			start, end := indexes[2*group], indexes[2*group+1]
+			matches = append(matches, entityMatch{
+				entity: domain.Entity{
+					Type:       p.kind,
+					Text:       text[start:end],
+					Normalized: normalized,
+				},
+				start:      start,
+				end:        end,
+				precedence: precedence,
+			})
+		}
+	}
+	return matches
+}
+
+// resolveEntityMatches drops every match overlapping one of higher precedence
+// and returns the others in text order with their code point offsets set.
+func resolveEntityMatches(text string, matches []entityMatch) []entityMatch {
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].precedence < matches[j].precedence
+	})
+	var kept []entityMatch
+	for _, match := range matches {
+		overlaps := false
+		for _, other := range kept {
+			if match.start < other.end && other.start < match.end {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			kept = append(kept, match)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool {
+		return kept[i].start < kept[j].start
+	})
+
+	for i := range kept {
+		kept[i].entity.Start = utf8.RuneCountInString(text[:kept[i].start])
+		kept[i].entity.End = kept[i].entity.Start + utf8.RuneCountInString(kept[i].entity.Text)
+	}
+	return kept
+}
+
+func normalizeURL(match []string, language string) (string, bool) {
+	raw := match[0]
+	if !strings.Contains(strings.ToLower(raw), "://") {
+		raw = "http://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "", false
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	return u.String(), true
+}
+
+func normalizeEmail(match []string, language string) (string, bool) {
+	at := strings.LastIndex(match[0], "@")
+	local, domainPart := match[0][:at], match[0][at+1:]
+	if strings.HasPrefix(local, ".") || strings.HasSuffix(local, ".") || strings.Contains(local, "..") {
+		return "", false
+	}
+	return local + "@" + foldTerm(domainPart), true
+}
+
+func normalizeISODateTime(match []string, language string) (string, bool) {
+	date, ok := isoDate(match[1], match[2], match[3])
+	if !ok {
+		return "", false
+	}
+	seconds := match[6]
+	if seconds == "" {
+		seconds = "00"
+	}
+	clock, ok := isoTime(match[4], match[5], seconds)
+	if !ok {
+		return "", false
+	}
+
+	zone := match[7]
+	if len(zone) == 5 {
+		zone = zone[:3] + ":" + zone[3:]
+	}
+	return date + "T" + clock + zone, true
+}
+
+// normalizeNumericDate reads dates written with slashes month first in
+// English and day first otherwise, and dates written with dots day first.
+func normalizeNumericDate(match []string, language string) (string, bool) {
+	if match[2] != match[4] {
+		return "", false
+	}
+	first, second := match[1], match[3]
+	if match[2] == "/" && language == "en" {
+		first, second = second, first
+	}
+	return isoDate(match[5], second, first)
+}
+
+func normalizeIP(match []string, language string) (string, bool) {
+	raw := match[len(match)-1]
+	ip := net.ParseIP(raw)
+	if ip == nil || strings.Trim(raw, ":") == "" {
+		return "", false
+	}
+	if strings.Contains(raw, ":") && !strings.Contains(raw, "::") && strings.Count(raw, ":") != 7 {
+		return "", false
+	}
+	return ip.String(), true
+}
+
+func normalizeTime(match []string, language string) (string, bool) {
+	hour, err := strconv.Atoi(match[1])
+	if err != nil {
+		return "", false
+	}
+	if meridiem := strings.ToLower(match[4]); meridiem != "" {
+		if hour < 1 || hour > 12 {
+			return "", false
+		}
+		hour %= 12
+		if meridiem == "p" {
+			hour += 12
+		}
+	}
+
+	seconds := match[3]
+	if seconds == "" {
+		seconds = "00"
+	}
+	return isoTime(strconv.Itoa(hour), match[2], seconds)
+}
+
+func normalizeMoney(currency, amount string) (string, bool) {
+	code, ok := currencySymbols[currency]
+	if !ok {
+		code = currency
+	}
+	value, ok := parseAmount(amount)
+	return code + " " + value, ok
+}
+
+// normalizePhone returns a phone number in E.164 form. Numbers written
+// without a country code are read as national numbers of the language:
+// ten-digit North American numbers in English, or numbers with a trunk prefix
+// 0 where the language maps to a country. Numbers that cannot be placed, and
+// national digit runs with single-digit groups such as lists of decimals, are
+// rejected.
+func normalizePhone(match []string, language string) (string, bool) {
+	raw := match[0]
+	if strings.HasPrefix(raw, "+") {
+		raw = strings.Replace(raw, "(0)", "", 1)
+	}
+	if strings.Count(raw, "(") != strings.Count(raw, ")") || strings.Count(raw, "(") > 1 ||
+		strings.Contains(raw, "  ") || strings.Contains(raw, "--") || strings.Contains(raw, "..") {
+		return "", false
+	}
+	if !strings.HasPrefix(raw, "+") {
+		groups := strings.FieldsFunc(raw, func(r rune) bool { return r < '0' || r > '9' })
+		for _, group := range groups[1:] {
+			if len(group) < 2 {
+				return "", false
+			}
+		}
+	}
+
+	number := digitsOf(raw)
+	if len(number) < minPhoneDigits || len(number) > maxPhoneDigits {
+		return "", false
+	}
+
+	switch {
+	case strings.HasPrefix(raw, "+"):
+		return "+" + number, true
+	case strings.HasPrefix(number, "00"):
+		return "+" + number[2:], len(number) > minPhoneDigits+2
+	case len(number) == len(raw):
+		return "", false
+	case language == "en" && len(number) == 11 && number[0] == '1' && number[1] >= '2':
+		return "+" + number, true
+	case language == "en" && len(number) == 10 && number[0] >= '2':
+		return "+1" + number, true
+	case number[0] == '0':
+		if code, ok := trunkCallingCodes[language]; ok {
+			return "+" + code + number[1:], true
+		}
+	}
+	return "", false
+}
+
+// parseAmount returns a number written with thousands separators and a
+// decimal comma or point as plain digits with a decimal point. A final
+// separator followed by three digits groups thousands.
+func parseAmount(amount string) (string, bool) {
+	decimal := -1
+	if i := strings.LastIndexAny(amount, ".,"); i >= 0 && len(amount)-i-1 != 3 {
+		decimal = i
+	}
+
+	var value strings.Builder
+	for i, r := range amount {
+		switch {
+		case i == decimal:
+			value.WriteByte('.')
+		case r >= '0' && r <= '9':
+			value.WriteRune(r)
+		}
+	}
+	return value.String(), value.Len() > 0
+}
+
+func digitsOf(text string) string {
+	var digits strings.Builder
+	for _, r := range text {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	return digits.String()
+}
+
+func monthNumber(name string) string {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	for i, month := range strings.Split(monthNames, "|") {
+		if strings.HasPrefix(month, name) {
+			return strconv.Itoa(i + 1)
+		}
+	}
+	return ""
+}
+
+// isoDate returns the date in ISO-8601 form when it exists.
+func isoDate(year, month, day string) (string, bool) {
+	y, errY := strconv.Atoi(year)
+	m, errM := strconv.Atoi(month)
+	d, errD := strconv.Atoi(day)
+	if errY != nil || errM != nil || errD != nil {
+		return "", false
+	}
+
+	date := time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+	if date.Year() != y || int(date.Month()) != m || date.Day() != d {
+		return "", false
+	}
+	return date.Format("2006-01-02"), true
+}
+
+func isoTime(hour, minute, second string) (string, bool) {
+	h, errH := strconv.Atoi(hour)
+	m, errM := strconv.Atoi(minute)
+	s, errS := strconv.Atoi(second)
+	if errH != nil || errM != nil || errS != nil || h > 23 || m > 59 || s > 59 {
+		return "", false
+	}
+	return fmt.Sprintf("%02d:%02d:%02d", h, m, s), true
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"testing"
+
+	"vm-chan/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntitiesAnalyzer(t *testing.T) {
+	analyzer := NewEntitiesAnalyzer()
+
+	analyze := func(t *testing.T, text, language string) []domain.Entity {
+		return analyzeDoc[*domain.EntitiesResult](t, analyzer, text, language).Entities
+	}
+
+	t.Run("Entities with offsets", func(t *testing.T) {
+		entities := analyze(t, "Écrivez à Ana@Example.COM ou appelez +33 1 42 68 53 00 ! #Promo", "fr")
+
+		assert.Equal(t, []domain.Entity{
+			{Type: "email", Text: "Ana@Example.COM", Normalized: "Ana@example.com", Start: 10, End: 25},
+			{Type: "phone", Text: "+33 1 42 68 53 00", Normalized: "+33142685300", Start: 37, End: 54},
+			{Type: "hashtag", Text: "#Promo", Normalized: "#promo", Start: 57, End: 63},
+		}, entities)
+	})
+
+	t.Run("Normalization", func(t *testing.T) {
+		tests := []struct {
+			text       string
+			language   string
+			kind       string
+			normalized string
+		}{
+			{"see WWW.Example.org/docs.", "en", "url", "http://www.example.org/docs"},
+			{"host 2001:db8:0:0:0:0:0:1 is up", "en", "ip_address", "2001:db8::1"},
+			{"call (555) 123-4567", "en", "phone", "+15551234567"},
+			{"ruf 030 1234567 an", "de", "phone", "+49301234567"},
+			{"dial 0049 30 1234567", "en", "phone", "+49301234567"},
+			{"at 2024-03-15T10:30:00+0100", "en", "datetime", "2024-03-15T10:30:00+01:00"},
+			{"on 03/04/2024", "en", "date", "2024-03-04"},
+			{"le 03/04/2024", "fr", "date", "2024-04-03"},
+			{"on the 5th of January, 2024", "en", "date", "2024-01-05"},
+			{"Sept. 9 2025", "en", "date", "2025-09-09"},
+			{"at 3:30 p.m.", "en", "time", "15:30:00"},
+			{"by 12am", "en", "time", "00:00:00"},
+			{"costs $1,234.50", "en", "money", "USD 1234.50"},
+			{"kostet 1.234,5 €", "de", "money", "EUR 1234.5"},
+			{"up 12,5 %", "de", "percentage", "12.5%"},
+			{"ping @Alice_B", "en", "mention", "@alice_b"},
+		}
+
+		for _, tt := range tests {
+			entities := analyze(t, tt.text, tt.language)
+			if assert.Len(t, entities, 1, tt.text) {
+				assert.Equal(t, tt.kind, entities[0].Type, tt.text)
+				assert.Equal(t, tt.normalized, entities[0].Normalized, tt.text)
+			}
+		}
+	})
+
+	t.Run("False positives", func(t *testing.T) {
+		texts := []string{
+			"Hello 123 world!",
+			"the years 1990-2000",
+			"values 0.5 0.25 0.125",
+			"on 2024-02-30",
+			"user@localhost and &#39; and #1",
+			"version 1.2.3",
+			"note:: done",
+		}
+
+		for _, text := range texts {
+			assert.Empty(t, analyze(t, text, "de"), text)
+		}
+		assert.Empty(t, analyze(t, "call 020 7946 0958", "en"), "English numbers have no trunk prefix")
+	})
+
+	t.Run("Overlaps keep the stronger entity", func(t *testing.T) {
+		entities := analyze(t, "https://example.com/#anchor and mail@example.com", "en")
+
+		require.Len(t, entities, 2)
+		assert.Equal(t, "url", entities[0].Type)
+		assert.Equal(t, "email", entities[1].Type)
+	})
+}
@@ -0,0 +1,477 @@
+package service
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"sort"
+	"strings"
+	"sync"
+
+	"vm-chan/internal/domain"
+)
+
+const (
+	shingleSize         = 3
+	minHashPermutations = 128
+	// lshBands splits MinHash signatures into bands of lshRows values. Two
+	// texts become candidates when any band is equal, which is likely from a
+	// Jaccard similarity of about (1/bands)^(1/rows), here 0.42.
+	lshBands = 32
+	lshRows  = minHashPermutations / lshBands
+	// maxSimHashDistance is the number of differing SimHash bits up to which
+	// texts are candidates regardless of their MinHash bands.
+	maxSimHashDistance = 3
+)
+
+// minHashSeeds derive the hash functions standing for the permutations of
+// MinHash signatures.
+var minHashSeeds = func() [minHashPermutations]uint64 {
+	var seeds [minHashPermutations]uint64
+	state := uint64(0x853c49e6748fea9b)
+	for i := range seeds {
+		state += 0x9e3779b97f4a7c15
+		seeds[i] = mix64(state)
+	}
+	return seeds
+}()
+
+// textFingerprint holds the SimHash and MinHash signatures of a text, computed
+// over its shingles: the runs of shingleSize consecutive case-folded words,
+// or its words when it has fewer.
+type textFingerprint struct {
+	simHash uint64
+	minHash []uint32
+}
+
+func fingerprintTerms(terms []string) *textFingerprint {
+	if len(terms) == 0 {
+		return nil
+	}
+
+	shingles := make(map[uint64]int)
+	if len(terms) < shingleSize {
+		for _, term := range terms {
+			shingles[hashString(term)]++
+		}
+	} else {
+		for i := 0; i+shingleSize <= len(terms); i++ {
+			shingles[hashString(strings.Join(terms[i:i+shingleSize], " "))]++
+		}
+	}
+
+	var weights [64]int
+	minHash := make([]uint32, minHashPermutations)
+	for i := range minHash {
+		minHash[i] = math.MaxUint32
+	}
+	for shingle, count := range shingles {
+		for bit := range weights {
+			if shingle&(1<<bit) != 0 {
+				weights[bit] += count
+			} else {
+				weights[bit] -= count
+			}
+		}
+		for i, seed := range minHashSeeds {
+			if value := uint32(mix64(shingle ^ seed)); value < minHash[i] {
+				minHash[i] = value
+			}
+		}
+	}
+
+	fingerprint := &textFingerprint{minHash: minHash}
+	for bit, weight := range weights {
+		if weight > 0 {
+			fingerprint.simHash |= 1 << bit
+		}
+	}
+	return fingerprint
+}
+
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	return mix64(h.Sum64())
+}
+
+// mix64 is the finalizer of SplitMix64, spreading every input bit over the
+// whole output.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	return x ^ x>>31
+}
+
+// minHashSimilarity estimates the Jaccard similarity of the shingle sets of
+// two texts as the share of equal signature values.
+func minHashSimilarity(a, b []uint32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	equal := 0
+	for i := range a {
+		if a[i] == b[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(a))
+}
+
+func simHashDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// FingerprintIndex finds near-duplicates among the fingerprints of a
+// repository, keeping a locality-sensitive hashing index per tenant that is
+// loaded from the repository the first time the tenant is queried.
+type FingerprintIndex struct {
+	repo    domain.FingerprintRepository
+	mu      sync.Mutex
+	tenants map[string]*lshIndex
+}
+
+func NewFingerprintIndex(repo domain.FingerprintRepository) *FingerprintIndex {
+	return &FingerprintIndex{
+		repo:    repo,
+		tenants: make(map[string]*lshIndex),
+	}
+}
+
+// lshIndex buckets the fingerprints of a tenant by the hash of each band of
+// their MinHash signature and by each band of simHashBandBits of their
+// SimHash. Fingerprints are numbered in insertion order.
+type lshIndex struct {
+	// loading serializes the loading of the index from the repository, which
+	// mu does not cover.
+	loading      sync.Mutex
+	mu           sync.RWMutex
+	loaded       bool
+	fingerprints map[int]*domain.Fingerprint
+	documents    map[string]int
+	next         int
+	buckets      map[uint64][]int
+	simBuckets   map[uint64][]int
+}
+
+// simHashBandBits splits SimHashes into bands such that two within
+// maxSimHashDistance bits of each other have at least one equal band.
+const simHashBandBits = 64 / (maxSimHashDistance + 1)
+
+func newLSHIndex() *lshIndex {
+	return &lshIndex{
+		fingerprints: make(map[int]*domain.Fingerprint),
+		documents:    make(map[string]int),
+		buckets:      make(map[uint64][]int),
+		simBuckets:   make(map[uint64][]int),
+	}
+}
+
+// add indexes a fingerprint, replacing the previous fingerprint of its
+// document.
+func (l *lshIndex) add(fingerprint *domain.Fingerprint) {
+	l.remove(fingerprint.DocumentID)
+	index := l.next
+	l.next++
+	l.fingerprints[index] = fingerprint
+	l.documents[fingerprint.DocumentID] = index
+	for _, key := range bandKeys(fingerprint.MinHash) {
+		l.buckets[key] = append(l.buckets[key], index)
+	}
+	for _, key := range simHashBandKeys(fingerprint.SimHash) {
+		l.simBuckets[key] = append(l.simBuckets[key], index)
+	}
+}
+
+func (l *lshIndex) remove(documentID string) {
+	index, ok := l.documents[documentID]
+	if !ok {
+		return
+	}
+	fingerprint := l.fingerprints[index]
+	for _, key := range bandKeys(fingerprint.MinHash) {
+		l.buckets[key] = removeIndex(l.buckets[key], index)
+		if len(l.buckets[key]) == 0 {
+			delete(l.buckets, key)
+		}
+	}
+	for _, key := range simHashBandKeys(fingerprint.SimHash) {
+		l.simBuckets[key] = removeIndex(l.simBuckets[key], index)
+		if len(l.simBuckets[key]) == 0 {
+			delete(l.simBuckets, key)
+		}
+	}
+	delete(l.fingerprints, index)
+	delete(l.documents, documentID)
+}
+
+func removeIndex(indexes []int, index int) []int {
+	for i, value := range indexes {
+		if value == index {
+			return append(indexes[:i], indexes[i+1:]...)
+		}
+	}
+	return indexes
+}
+
+// bandCandidates returns the indexes of the fingerprints sharing a band with
+// a signature.
+func (l *lshIndex) bandCandidates(minHash []uint32) map[int]bool {
+	candidates := make(map[int]bool)
+	for _, key := range bandKeys(minHash) {
+		for _, index := range l.buckets[key] {
+			candidates[index] = true
+		}
+	}
+	return candidates
+}
+
+// candidates returns the indexes of the fingerprints sharing a band with a
+// signature or close to its SimHash, in insertion order.
+func (l *lshIndex) candidates(simHash uint64, minHash []uint32) []int {
+	seen := l.bandCandidates(minHash)
+	for _, key := range simHashBandKeys(simHash) {
+		for _, index := range l.simBuckets[key] {
+			if simHashDistance(simHash, l.fingerprints[index].SimHash) <= maxSimHashDistance {
+				seen[index] = true
+			}
+		}
+	}
+
+	candidates := make([]int, 0, len(seen))
+	for index := range seen {
+		candidates = append(candidates, index)
+	}
+	sort.Ints(candidates)
+	return candidates
+}
+
+// ordered returns the indexes of the fingerprints in insertion order.
+func (l *lshIndex) ordered() []int {
+	indexes := make([]int, 0, len(l.fingerprints))
+	for index := range l.fingerprints {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+func bandKeys(minHash []uint32) []uint64 {
+	if len(minHash) != minHashPermutations {
+		return nil
+	}
+	keys := make([]uint64, lshBands)
+	for band := range keys {
+		key := uint64(band)
+		for _, value := range minHash[band*lshRows : (band+1)*lshRows] {
+			key = mix64(key ^ uint64(value))
+		}
+		keys[band] = key
+	}
+	return keys
+}
+
+// simHashBandKeys returns the bands of a SimHash, each tagged with its
+// position.
+func simHashBandKeys(simHash uint64) []uint64 {
+	keys := make([]uint64, 0, 64/simHashBandBits)
+	for band := 0; band < 64/simHashBandBits; band++ {
+		value := simHash >> (band * simHashBandBits) & (1<<simHashBandBits - 1)
+		keys = append(keys, uint64(band)<<simHashBandBits|value)
+	}
+	return keys
+}
+
+// tenant returns the index of a tenant, loading it from the repository the
+// first time. Loading only holds the lock of the tenant.
+func (f *FingerprintIndex) tenant(ctx context.Context, tenantID string) (*lshIndex, error) {
+	f.mu.Lock()
+	index, ok := f.tenants[tenantID]
+	if !ok {
+		index = newLSHIndex()
+		f.tenants[tenantID] = index
+	}
+	f.mu.Unlock()
+
+	index.mu.RLock()
+	loaded := index.loaded
+	index.mu.RUnlock()
+	if loaded {
+		return index, nil
+	}
+
+	index.loading.Lock()
+	defer index.loading.Unlock()
+	index.mu.RLock()
+	loaded = index.loaded
+	index.mu.RUnlock()
+	if loaded {
+		return index, nil
+	}
+
+	fingerprints, err := f.repo.List(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	index.mu.Lock()
+	defer index.mu.Unlock()
+	for _, fingerprint := range fingerprints {
+		index.add(fingerprint)
+	}
+	index.loaded = true
+	return index, nil
+}
+
+// Add stores a fingerprint and indexes it under its tenant, replacing the
+// previous fingerprint of its document.
+func (f *FingerprintIndex) Add(ctx context.Context, fingerprint *domain.Fingerprint) error {
+	index, err := f.tenant(ctx, fingerprint.TenantID)
+	if err != nil {
+		return err
+	}
+	if err := f.repo.Add(ctx, fingerprint); err != nil {
+		return err
+	}
+
+	index.mu.Lock()
+	defer index.mu.Unlock()
+	index.add(fingerprint)
+	return nil
+}
+
+// Remove drops the fingerprint of a document of a tenant, if any.
+func (f *FingerprintIndex) Remove(ctx context.Context, tenantID, documentID string) error {
+	index, err := f.tenant(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if err := f.repo.Remove(ctx, tenantID, documentID); err != nil {
+		return err
+	}
+
+	index.mu.Lock()
+	defer index.mu.Unlock()
+	index.remove(documentID)
+	return nil
+}
+
+// Similar returns the documents of a tenant whose estimated similarity to a
+// text is at least threshold, most similar first.
+func (f *FingerprintIndex) Similar(ctx context.Context, tenantID string, fingerprint *textFingerprint, threshold float64) ([]domain.DuplicateMatch, error) {
+	index, err := f.tenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	matches := []domain.DuplicateMatch{}
+	for _, candidate := range index.candidates(fingerprint.simHash, fingerprint.minHash) {
+		stored := index.fingerprints[candidate]
+		similarity := minHashSimilarity(fingerprint.minHash, stored.MinHash)
+		if similarity < threshold {
+			continue
+		}
+		matches = append(matches, domain.DuplicateMatch{
+			DocumentID:      stored.DocumentID,
+			Similarity:      round4(similarity),
+			SimHashDistance: simHashDistance(fingerprint.simHash, stored.SimHash),
+			SubmittedAt:     stored.SubmittedAt,
+		})
+	}
+	sort.SliceStable(matches, func(a, b int) bool {
+		return matches[a].Similarity > matches[b].Similarity
+	})
+	return matches, nil
+}
+
+// Clusters groups the documents of a tenant linked by an estimated similarity
+// of at least threshold, directly or through other documents. Documents
+// without near-duplicates are left out. Only the documents sharing a MinHash
+// band are compared, which keeps listing clusters close to linear.
+func (f *FingerprintIndex) Clusters(ctx context.Context, tenantID string, threshold float64) ([]domain.DuplicateCluster, error) {
+	index, err := f.tenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	order := index.ordered()
+	parents := make(map[int]int, len(order))
+	for _, i := range order {
+		parents[i] = i
+	}
+	var root func(int) int
+	root = func(i int) int {
+		if parents[i] != i {
+			parents[i] = root(parents[i])
+		}
+		return parents[i]
+	}
+
+	// lowest holds, for every root, the lowest similarity of the links
+	// merged into its cluster.
+	lowest := make(map[int]float64)
+	for _, a := range order {
+		fingerprint := index.fingerprints[a]
+		var linked []int
+		for b := range index.bandCandidates(fingerprint.MinHash) {
+			if b > a {
+				linked = append(linked, b)
+			}
+		}
+		sort.Ints(linked)
+		for _, b := range linked {
+			similarity := minHashSimilarity(fingerprint.MinHash, index.fingerprints[b].MinHash)
+			if similarity < threshold {
+				continue
+			}
+			rootA, rootB := root(a), root(b)
+			if rootA == rootB {
+				continue
+			}
+			link := similarity
+			for _, r := range []int{rootA, rootB} {
+				if value, ok := lowest[r]; ok {
+					link = math.Min(link, value)
+				}
+			}
+			delete(lowest, rootA)
+			delete(lowest, rootB)
+			parents[rootB] = rootA
+			lowest[rootA] = link
+		}
+	}
+
+	members := make(map[int][]int)
+	for _, i := range order {
+		r := root(i)
+		members[r] = append(members[r], i)
+	}
+	clusters := []domain.DuplicateCluster{}
+	for r, indexes := range members {
+		if len(indexes) < 2 {
+			continue
+		}
+		cluster := domain.DuplicateCluster{Similarity: round4(lowest[r])}
+		for _, i := range indexes {
+			cluster.Documents = append(cluster.Documents, domain.DuplicateDocument{
+				DocumentID:  index.fingerprints[i].DocumentID,
+				UserID:      index.fingerprints[i].UserID,
+				SubmittedAt: index.fingerprints[i].SubmittedAt,
+			})
+		}
+		clusters = append(clusters, cluster)
+	}
+	sort.Slice(clusters, func(a, b int) bool {
+		if len(clusters[a].Documents) != len(clusters[b].Documents) {
+			return len(clusters[a].Documents) > len(clusters[b].Documents)
+		}
+		return clusters[a].Documents[0].SubmittedAt.Before(clusters[b].Documents[0].SubmittedAt)
+	})
+	return clusters, nil
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"vm-chan/internal/domain"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	defaultTopN = 10
+	maxTopN     = 1000
+)
+
+type frequencyAnalyzer struct{}
+
+func NewFrequencyAnalyzer() Analyzer {
+	return &frequencyAnalyzer{}
+}
+
+func (a *frequencyAnalyzer) Name() string {
+	return "frequency"
+}
+
+func (a *frequencyAnalyzer) Version() string {
+	return "1.1.0"
+}
+
+func (a *frequencyAnalyzer) Description() string {
+	return "Letter histogram and top-N word, character n-gram and word n-gram frequencies"
+}
+
+func (a *frequencyAnalyzer) Analyze(ctx context.Context, doc *Document) (interface{}, error) {
+	topN, err := topNOption(doc.Options.TopN)
+	if err != nil {
+		return nil, err
+	}
+	forms, err := doc.wordForms()
+	if err != nil {
+		return nil, err
+	}
+
+	var stopwords map[string]bool
+	if doc.Options.FilterStopwords {
+		stopwords = stopwordsFor(doc.Language)
+	}
+
+	letters := newCounter()
+	words := newCounter()
+	charBigrams := newCounter()
+	charTrigrams := newCounter()
+	wordBigrams := newCounter()
+	wordTrigrams := newCounter()
+
+	terms := doc.terms()
+	for _, sentence := range doc.sentences() {
+		var previous []string
+		from, to := doc.tokenRange(sentence.start, sentence.end)
+		for i, term := range terms[from:to] {
+			runes := []rune(term)
+			for j, r := range runes {
+				if unicode.IsLetter(r) {
+					letters.add(string(r))
+				}
+				if j+2 <= len(runes) {
+					charBigrams.add(string(runes[j : j+2]))
+				}
+				if j+3 <= len(runes) {
+					charTrigrams.add(string(runes[j : j+3]))
+				}
+			}
+
+			if !stopwords[term] {
+				words.add(forms[from+i])
+			}
+
+			previous = append(previous, term)
+			if n := len(previous); n >= 2 {
+				wordBigrams.add(strings.Join(previous[n-2:], " "))
+				if n >= 3 {
+					wordTrigrams.add(strings.Join(previous[n-3:], " "))
+				}
+			}
+		}
+	}
+
+	return &domain.FrequencyResult{
+		Letters:           letters.entries(0),
+		Words:             words.entries(topN),
+		CharacterBigrams:  charBigrams.entries(topN),
+		CharacterTrigrams: charTrigrams.entries(topN),
+		WordBigrams:       wordBigrams.entries(topN),
+		WordTrigrams:      wordTrigrams.entries(topN),
+	}, nil
+}
+
+func topNOption(topN int) (int, error) {
+	switch {
+	case topN == 0:
+		return defaultTopN, nil
+	case topN < 0 || topN > maxTopN:
+		return 0, fmt.Errorf("%w: top_n must be between 1 and %d", domain.ErrInvalidInput, maxTopN)
+	}
+	return topN, nil
+}
+
+// foldTerm puts a word into the form used to compare words: canonically
+// composed and with full Unicode case folding applied.
+func foldTerm(word string) string {
+	return norm.NFC.String(cases.Fold().String(word))
+}
+
+type counter struct {
+	counts map[string]int
+	total  int
+}
+
+func newCounter() *counter {
+	return &counter{counts: make(map[string]int)}
+}
+
+func (c *counter) add(term string) {
+	c.counts[term]++
+	c.total++
+}
+
+// entries returns the counted terms by descending count, ties broken
+// alphabetically, limited to the first n when n is positive.
+func (c *counter) entries(n int) []domain.FrequencyEntry {
+	entries := make([]domain.FrequencyEntry, 0, len(c.counts))
+	for term, count := range c.counts {
+		entries = append(entries, domain.FrequencyEntry{
+			Term:      term,
+			Count:     count,
+			Frequency: round4(float64(count) / float64(c.total)),
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Term < entries[j].Term
+	})
+
+	if n > 0 && len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries
+}
+
+func round4(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
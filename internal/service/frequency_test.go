@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"vm-chan/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrequencyAnalyzer(t *testing.T) {
+	analyzer := NewFrequencyAnalyzer()
+
+	analyze := func(t *testing.T, text string, options domain.AnalysisOptions) *domain.FrequencyResult {
+		doc := testDocument(text, "en")
+		doc.Options = options
+		return analyzeDocument[*domain.FrequencyResult](t, context.Background(), analyzer, doc)
+	}
+
+	text := "The cat and the hat. The END."
+
+	t.Run("Case-folded counts", func(t *testing.T) {
+		result := analyze(t, text, domain.AnalysisOptions{TopN: 2})
+
+		assert.Equal(t, []domain.FrequencyEntry{
+			{Term: "the", Count: 3, Frequency: 0.4286},
+			{Term: "and", Count: 1, Frequency: 0.1429},
+		}, result.Words)
+		assert.Equal(t, domain.FrequencyEntry{Term: "t", Count: 5, Frequency: 0.2381}, result.Letters[0])
+		assert.Equal(t, []string{"he", "th"}, terms(result.CharacterBigrams))
+		assert.Equal(t, []string{"the", "and"}, terms(result.CharacterTrigrams))
+	})
+
+	t.Run("Word n-grams stay within sentences", func(t *testing.T) {
+		result := analyze(t, text, domain.AnalysisOptions{})
+
+		assert.Equal(t, []string{"and the", "cat and", "the cat", "the end", "the hat"}, terms(result.WordBigrams))
+		assert.Equal(t, []string{"and the hat", "cat and the", "the cat and"}, terms(result.WordTrigrams))
+	})
+
+	t.Run("Stopword filtering", func(t *testing.T) {
+		result := analyze(t, text, domain.AnalysisOptions{FilterStopwords: true})
+
+		assert.Equal(t, []string{"cat", "end", "hat"}, terms(result.Words))
+	})
+
+	t.Run("Stemmed words", func(t *testing.T) {
+		result := analyze(t, "Run, runs and running. The runner ran.", domain.AnalysisOptions{Stem: true})
+
+		assert.Equal(t, domain.FrequencyEntry{Term: "run", Count: 3, Frequency: 0.4286}, result.Words[0])
+		assert.Contains(t, terms(result.WordBigrams), "and running")
+	})
+
+	t.Run("Stemming an unsupported language", func(t *testing.T) {
+		doc := testDocument(text, "fi")
+		doc.Options.Stem = true
+
+		_, err := analyzer.Analyze(context.Background(), doc)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+
+	t.Run("Invalid top_n", func(t *testing.T) {
+		doc := testDocument(text, "en")
+		doc.Options.TopN = -1
+
+		_, err := analyzer.Analyze(context.Background(), doc)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+}
+
+func terms(entries []domain.FrequencyEntry) []string {
+	result := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, entry.Term)
+	}
+	return result
+}
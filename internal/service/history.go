@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"vm-chan/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultHistoryLimit = 20
+	maxHistoryLimit     = 100
+	maxHistoryFilters   = 10
+)
+
+// metricFilterPattern matches metric thresholds such as word_count>100.
+var metricFilterPattern = regexp.MustCompile(`^\s*([a-z_]+)\s*(!=|<=|>=|=|<|>)\s*(\S+)\s*$`)
+
+type historyService struct {
+	repo   domain.HistoryRepository
+	logger *zap.Logger
+}
+
+// NewHistoryService returns the service recording the analyses of the
+// authenticated user and listing them, most recent first.
+func NewHistoryService(repo domain.HistoryRepository, logger *zap.Logger) domain.HistoryService {
+	return &historyService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (s *historyService) Record(ctx context.Context, req *domain.TextAnalysisRequest, response *domain.TextAnalysisResponse) error {
+	user, ok := domain.UserFromContext(ctx)
+	if !ok {
+		return errNoUser
+	}
+	id, err := newDocumentID()
+	if err != nil {
+		return err
+	}
+
+	entry := &domain.HistoryEntry{
+		ID:        id,
+		UserID:    user.ID,
+		Request:   *req,
+		Analysis:  response,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.repo.Add(ctx, entry); err != nil {
+		return err
+	}
+
+	s.logger.Debug("Analysis recorded", zap.String("user_id", user.ID), zap.String("entry_id", id))
+	return nil
+}
+
+func (s *historyService) List(ctx context.Context, req *domain.HistoryRequest) (*domain.HistoryResponse, error) {
+	user, ok := domain.UserFromContext(ctx)
+	if !ok {
+		return nil, errNoUser
+	}
+	query, err := parseHistoryRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// One more entry than requested tells whether there is a next page.
+	limit := query.Limit
+	query.Limit++
+	entries, err := s.repo.List(ctx, user.ID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &domain.HistoryResponse{Entries: entries}
+	if len(entries) > limit {
+		response.Entries = entries[:limit]
+		last := entries[limit-1]
+		response.NextCursor = encodeHistoryCursor(domain.HistoryCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	return response, nil
+}
+
+func parseHistoryRequest(req *domain.HistoryRequest) (domain.HistoryQuery, error) {
+	query := domain.HistoryQuery{Limit: req.Limit}
+	if query.Limit == 0 {
+		query.Limit = defaultHistoryLimit
+	}
+	if query.Limit < 0 || query.Limit > maxHistoryLimit {
+		return query, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidInput, maxHistoryLimit)
+	}
+
+	var err error
+	if query.From, err = parseHistoryTime(req.From, false); err != nil {
+		return query, fmt.Errorf("%w: from: %v", domain.ErrInvalidInput, err)
+	}
+	if query.To, err = parseHistoryTime(req.To, true); err != nil {
+		return query, fmt.Errorf("%w: to: %v", domain.ErrInvalidInput, err)
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		return query, fmt.Errorf("%w: from must be before to", domain.ErrInvalidInput)
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeHistoryCursor(req.Cursor)
+		if err != nil {
+			return query, err
+		}
+		query.After = &cursor
+	}
+
+	if len(req.Filters) > maxHistoryFilters {
+		return query, fmt.Errorf("%w: at most %d filters are allowed", domain.ErrInvalidInput, maxHistoryFilters)
+	}
+	for _, filter := range req.Filters {
+		parsed, err := parseMetricFilter(filter)
+		if err != nil {
+			return query, err
+		}
+		query.Filters = append(query.Filters, parsed)
+	}
+	return query, nil
+}
+
+// parseHistoryTime parses an RFC 3339 time or a date, which stands for the
+// start of its day, or of the next when it ends a range, so that the range
+// covers the whole day.
+func parseHistoryTime(value string, end bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither an RFC 3339 time nor a date", value)
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+func parseMetricFilter(filter string) (domain.MetricFilter, error) {
+	match := metricFilterPattern.FindStringSubmatch(filter)
+	if match == nil {
+		return domain.MetricFilter{}, fmt.Errorf("%w: filter %q must be a metric, an operator and a number, such as word_count>100",
+			domain.ErrInvalidInput, filter)
+	}
+	if !slices.Contains(domain.HistoryMetrics, match[1]) {
+		return domain.MetricFilter{}, fmt.Errorf("%w: unknown metric %q, supported metrics: %s",
+			domain.ErrInvalidInput, match[1], strings.Join(domain.HistoryMetrics, ", "))
+	}
+	value, err := strconv.ParseFloat(match[3], 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return domain.MetricFilter{}, fmt.Errorf("%w: filter %q: %q is not a number", domain.ErrInvalidInput, filter, match[3])
+	}
+	return domain.MetricFilter{Metric: match[1], Operator: match[2], Value: value}, nil
+}
+
+// encodeHistoryCursor returns the opaque cursor of a position in history:
+// the creation time in nanoseconds and the ID of its entry.
+func encodeHistoryCursor(cursor domain.HistoryCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(cursor.CreatedAt.UnixNano(), 10) + ":" + cursor.ID))
+}
+
+func decodeHistoryCursor(value string) (domain.HistoryCursor, error) {
+	invalid := fmt.Errorf("%w: invalid cursor", domain.ErrInvalidInput)
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return domain.HistoryCursor{}, invalid
+	}
+	nanos, id, ok := strings.Cut(string(data), ":")
+	if !ok || id == "" {
+		return domain.HistoryCursor{}, invalid
+	}
+	createdAt, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return domain.HistoryCursor{}, invalid
+	}
+	return domain.HistoryCursor{CreatedAt: time.Unix(0, createdAt).UTC(), ID: id}, nil
+}
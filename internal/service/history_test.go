@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"vm-chan/internal/domain"
+	"vm-chan/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHistoryService(t *testing.T) {
+	db, err := repository.OpenSQLite(filepath.Join(t.TempDir(), "history.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	sqlite, err := repository.NewSQLiteHistoryRepository(db, zap.NewNop())
+	require.NoError(t, err)
+
+	for name, repo := range map[string]domain.HistoryRepository{
+		"Memory": repository.NewHistoryRepository(zap.NewNop()),
+		"SQLite": sqlite,
+	} {
+		t.Run(name, func(t *testing.T) {
+			testHistoryService(t, repo)
+		})
+	}
+}
+
+func testHistoryService(t *testing.T, repo domain.HistoryRepository) {
+	service := NewHistoryService(repo, zap.NewNop())
+	alice := domain.WithUser(context.Background(), &domain.User{ID: "alice"})
+	bob := domain.WithUser(context.Background(), &domain.User{ID: "bob"})
+
+	list := func(t *testing.T, ctx context.Context, req *domain.HistoryRequest) *domain.HistoryResponse {
+		response, err := service.List(ctx, req)
+		require.NoError(t, err)
+		return response
+	}
+	wordCounts := func(response *domain.HistoryResponse) []int {
+		counts := []int{}
+		for _, entry := range response.Entries {
+			counts = append(counts, entry.Analysis.WordCount)
+		}
+		return counts
+	}
+
+	t.Run("Record", func(t *testing.T) {
+		req := &domain.TextAnalysisRequest{Sentence: "Hello world", Language: "en", Analyses: []string{"counts"}}
+		require.NoError(t, service.Record(alice, req, &domain.TextAnalysisResponse{Sentence: "Hello world", WordCount: 2}))
+
+		response := list(t, alice, &domain.HistoryRequest{})
+		require.Len(t, response.Entries, 1)
+		entry := response.Entries[0]
+		assert.NotEmpty(t, entry.ID)
+		assert.Equal(t, "Hello world", entry.Request.Sentence)
+		assert.Equal(t, []string{"counts"}, entry.Request.Analyses)
+		assert.Equal(t, 2, entry.Analysis.WordCount)
+		assert.WithinDuration(t, time.Now(), entry.CreatedAt, time.Minute)
+		assert.Empty(t, response.NextCursor)
+
+		assert.Empty(t, list(t, bob, &domain.HistoryRequest{}).Entries, "entries of other users are not listed")
+	})
+
+	// Ten entries of carol, one a day from May 1st to 9th and two on May
+	// 5th, with one to ten words in the order they were run.
+	carol := domain.WithUser(context.Background(), &domain.User{ID: "carol"})
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, day := range []int{0, 1, 2, 3, 4, 4, 5, 6, 7, 8} {
+		require.NoError(t, repo.Add(context.Background(), &domain.HistoryEntry{
+			ID:        string(rune('a' + i)),
+			UserID:    "carol",
+			Analysis:  &domain.TextAnalysisResponse{WordCount: i + 1, VowelCount: 10},
+			CreatedAt: start.AddDate(0, 0, day),
+		}))
+	}
+
+	t.Run("Pagination", func(t *testing.T) {
+		var counts []int
+		cursor := ""
+		for pages := 0; ; pages++ {
+			require.Less(t, pages, 10)
+			response := list(t, carol, &domain.HistoryRequest{Limit: 3, Cursor: cursor})
+			assert.LessOrEqual(t, len(response.Entries), 3)
+			counts = append(counts, wordCounts(response)...)
+			if response.NextCursor == "" {
+				break
+			}
+			cursor = response.NextCursor
+		}
+		assert.Equal(t, []int{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}, counts)
+	})
+
+	t.Run("Date range", func(t *testing.T) {
+		assert.Equal(t, []int{6, 5, 4}, wordCounts(list(t, carol, &domain.HistoryRequest{From: "2024-05-04", To: "2024-05-05"})))
+		assert.Equal(t, []int{10, 9}, wordCounts(list(t, carol, &domain.HistoryRequest{From: "2024-05-08T00:00:00Z"})))
+		assert.Equal(t, []int{1}, wordCounts(list(t, carol, &domain.HistoryRequest{To: "2024-05-01T13:00:00+00:00"})))
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		assert.Equal(t, []int{10, 9, 8}, wordCounts(list(t, carol, &domain.HistoryRequest{Filters: []string{"word_count>7"}})))
+		assert.Equal(t, []int{3, 2}, wordCounts(list(t, carol, &domain.HistoryRequest{
+			Filters: []string{"word_count >= 2", "word_count<=3", "vowel_count=10"},
+		})))
+		assert.Empty(t, list(t, carol, &domain.HistoryRequest{Filters: []string{"vowel_count!=10"}}).Entries)
+
+		response := list(t, carol, &domain.HistoryRequest{Filters: []string{"word_count<5"}, Limit: 2})
+		assert.Equal(t, []int{4, 3}, wordCounts(response))
+		response = list(t, carol, &domain.HistoryRequest{Filters: []string{"word_count<5"}, Limit: 2, Cursor: response.NextCursor})
+		assert.Equal(t, []int{2, 1}, wordCounts(response))
+	})
+
+	t.Run("Invalid requests", func(t *testing.T) {
+		for name, req := range map[string]*domain.HistoryRequest{
+			"limit above maximum": {Limit: 1000},
+			"malformed date":      {From: "May 1st"},
+			"empty range":         {From: "2024-05-02", To: "2024-05-01"},
+			"malformed cursor":    {Cursor: "not a cursor"},
+			"unknown metric":      {Filters: []string{"sentiment>0"}},
+			"malformed filter":    {Filters: []string{"word_count~3"}},
+			"non-numeric value":   {Filters: []string{"word_count>many"}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := service.List(carol, req)
+				assert.ErrorIs(t, err, domain.ErrInvalidInput)
+			})
+		}
+	})
+}
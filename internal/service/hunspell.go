@@ -0,0 +1,580 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/ianaindex"
+)
+
+const (
+	// maxSuggestedRunes is the length of the longest word given suggestions.
+	maxSuggestedRunes = 40
+	// maxDoubleEditRunes is the length of the longest word whose candidates
+	// two edits away are looked up, as their number grows with the square
+	// of the length.
+	maxDoubleEditRunes = 12
+)
+
+// HunspellDictionary holds the word forms generated by a Hunspell dictionary:
+// every word of the .dic file with the prefixes and suffixes of the .aff file
+// its flags allow. Compounding and affixes applied on top of other affixes are
+// not supported.
+type HunspellDictionary struct {
+	forms     map[string]bool
+	forbidden map[string]bool
+	// suggestible maps the lowercase spelling of the forms that may be
+	// suggested to their dictionary spelling.
+	suggestible  map[string]string
+	replacements [][2]string
+	try          []rune
+}
+
+type hunspellAffix struct {
+	flag      string
+	prefix    bool
+	cross     bool
+	strip     string
+	add       string
+	condition []hunspellCondition
+}
+
+// hunspellCondition matches one character of an affix condition: any
+// character, or a character in or out of a set.
+type hunspellCondition struct {
+	any    bool
+	negate bool
+	runes  string
+}
+
+type hunspellEntry struct {
+	word  string
+	flags []string
+}
+
+type hunspellParser struct {
+	flagMode     string
+	aliases      [][]string
+	affixes      map[string][]*hunspellAffix
+	crossFlags   map[string]bool
+	forbiddenTag string
+	noSuggestTag string
+	needAffixTag string
+	dictionary   *HunspellDictionary
+}
+
+// ParseHunspell reads a dictionary from the contents of its .aff and .dic
+// files, decoding them with the character set named by SET.
+func ParseHunspell(aff, dic io.Reader) (*HunspellDictionary, error) {
+	affData, err := io.ReadAll(aff)
+	if err != nil {
+		return nil, err
+	}
+	dicData, err := io.ReadAll(dic)
+	if err != nil {
+		return nil, err
+	}
+
+	if charset := hunspellCharset(affData); charset != "" && charset != "UTF-8" {
+		encoding, err := ianaindex.IANA.Encoding(strings.Replace(charset, "ISO8859", "ISO-8859", 1))
+		if err != nil || encoding == nil {
+			return nil, fmt.Errorf("unsupported character set %q", charset)
+		}
+		if affData, err = encoding.NewDecoder().Bytes(affData); err != nil {
+			return nil, fmt.Errorf("aff: %w", err)
+		}
+		if dicData, err = encoding.NewDecoder().Bytes(dicData); err != nil {
+			return nil, fmt.Errorf("dic: %w", err)
+		}
+	}
+
+	p := &hunspellParser{
+		affixes:    make(map[string][]*hunspellAffix),
+		crossFlags: make(map[string]bool),
+		dictionary: &HunspellDictionary{
+			forms:       make(map[string]bool),
+			forbidden:   make(map[string]bool),
+			suggestible: make(map[string]string),
+		},
+	}
+	if err := p.parseAff(affData); err != nil {
+		return nil, fmt.Errorf("aff: %w", err)
+	}
+	if err := p.parseDic(dicData); err != nil {
+		return nil, fmt.Errorf("dic: %w", err)
+	}
+	return p.dictionary, nil
+}
+
+func hunspellCharset(aff []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(aff))
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) == 2 && fields[0] == "SET" {
+			return strings.ToUpper(fields[1])
+		}
+	}
+	return ""
+}
+
+func (p *hunspellParser) parseAff(data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		var err error
+		switch fields[0] {
+		case "FLAG":
+			p.flagMode = fields[1]
+		case "TRY":
+			p.dictionary.try = []rune(fields[1])
+		case "FORBIDDENWORD":
+			p.forbiddenTag = fields[1]
+		case "NOSUGGEST":
+			p.noSuggestTag = fields[1]
+		case "NEEDAFFIX", "PSEUDOROOT":
+			p.needAffixTag = fields[1]
+		case "AF":
+			if _, countErr := strconv.Atoi(fields[1]); countErr != nil {
+				p.aliases = append(p.aliases, p.parseFlags(fields[1]))
+			}
+		case "REP":
+			if len(fields) >= 3 {
+				p.dictionary.replacements = append(p.dictionary.replacements, [2]string{
+					strings.ReplaceAll(fields[1], "_", " "),
+					strings.ReplaceAll(fields[2], "_", " "),
+				})
+			}
+		case "PFX", "SFX":
+			err = p.parseAffix(fields)
+		}
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// parseAffix reads an affix header, "SFX flag cross count", or one of its
+// rules, "SFX flag strip add[/flags] condition".
+func (p *hunspellParser) parseAffix(fields []string) error {
+	if len(fields) == 4 && (fields[2] == "Y" || fields[2] == "N") {
+		if _, err := strconv.Atoi(fields[3]); err == nil {
+			p.affixes[fields[1]] = []*hunspellAffix{}
+			p.crossFlags[fields[1]] = fields[2] == "Y"
+			return nil
+		}
+	}
+	if len(fields) < 4 {
+		return fmt.Errorf("incomplete %s rule", fields[0])
+	}
+
+	affix := &hunspellAffix{
+		flag:   fields[1],
+		prefix: fields[0] == "PFX",
+		cross:  p.crossFlags[fields[1]],
+		strip:  fields[2],
+		add:    fields[3],
+	}
+	if _, ok := p.affixes[fields[1]]; !ok {
+		return fmt.Errorf("%s rule before the header of flag %s", fields[0], fields[1])
+	}
+	if affix.strip == "0" {
+		affix.strip = ""
+	}
+	if i := strings.Index(affix.add, "/"); i >= 0 {
+		affix.add = affix.add[:i]
+	}
+	if affix.add == "0" {
+		affix.add = ""
+	}
+
+	condition := "."
+	if len(fields) > 4 {
+		condition = fields[4]
+	}
+	var err error
+	if affix.condition, err = parseHunspellCondition(condition); err != nil {
+		return err
+	}
+
+	p.affixes[affix.flag] = append(p.affixes[affix.flag], affix)
+	return nil
+}
+
+func parseHunspellCondition(condition string) ([]hunspellCondition, error) {
+	var parsed []hunspellCondition
+	runes := []rune(condition)
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '.':
+			parsed = append(parsed, hunspellCondition{any: true})
+		case '[':
+			end := i + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated condition %q", condition)
+			}
+			set := runes[i+1 : end]
+			negate := len(set) > 0 && set[0] == '^'
+			if negate {
+				set = set[1:]
+			}
+			parsed = append(parsed, hunspellCondition{negate: negate, runes: string(set)})
+			i = end
+		default:
+			parsed = append(parsed, hunspellCondition{runes: string(runes[i])})
+		}
+	}
+	return parsed, nil
+}
+
+func (c hunspellCondition) matches(r rune) bool {
+	return c.any || strings.ContainsRune(c.runes, r) != c.negate
+}
+
+// parseFlags splits flags according to the FLAG mode: one character per flag
+// by default, two with long, or comma separated numbers with num.
+func (p *hunspellParser) parseFlags(flags string) []string {
+	var parsed []string
+	switch p.flagMode {
+	case "long":
+		runes := []rune(flags)
+		for i := 0; i+1 < len(runes); i += 2 {
+			parsed = append(parsed, string(runes[i:i+2]))
+		}
+	case "num":
+		parsed = strings.Split(flags, ",")
+	default:
+		for _, r := range flags {
+			parsed = append(parsed, string(r))
+		}
+	}
+	return parsed
+}
+
+func (p *hunspellParser) parseDic(data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[0]); err == nil && line == 1 {
+			continue
+		}
+
+		entry := hunspellEntry{word: fields[0]}
+		if i := unescapedSlash(fields[0]); i >= 0 {
+			entry.word = fields[0][:i]
+			flags := fields[0][i+1:]
+			if alias, err := strconv.Atoi(flags); err == nil && len(p.aliases) > 0 {
+				if alias < 1 || alias > len(p.aliases) {
+					return fmt.Errorf("line %d: unknown flag alias %d", line, alias)
+				}
+				entry.flags = p.aliases[alias-1]
+			} else {
+				entry.flags = p.parseFlags(flags)
+			}
+		}
+		entry.word = strings.ReplaceAll(entry.word, `\/`, "/")
+		p.addEntry(entry)
+	}
+	return scanner.Err()
+}
+
+func unescapedSlash(s string) int {
+	for i := 1; i < len(s); i++ {
+		if s[i] == '/' && s[i-1] != '\\' {
+			return i
+		}
+	}
+	return -1
+}
+
+// addEntry generates the forms of a dictionary word: the word itself, the
+// word with each of its prefixes and suffixes, and with a prefix and a suffix
+// together when both allow cross products.
+func (p *hunspellParser) addEntry(entry hunspellEntry) {
+	forbidden, suggest, needAffix := false, true, false
+	var prefixes, suffixes []*hunspellAffix
+	for _, flag := range entry.flags {
+		switch flag {
+		case p.forbiddenTag:
+			forbidden = true
+		case p.noSuggestTag:
+			suggest = false
+		case p.needAffixTag:
+			needAffix = true
+		}
+		for _, affix := range p.affixes[flag] {
+			if affix.prefix {
+				prefixes = append(prefixes, affix)
+			} else {
+				suffixes = append(suffixes, affix)
+			}
+		}
+	}
+
+	add := func(form string) {
+		if forbidden {
+			p.dictionary.forbidden[form] = true
+			return
+		}
+		p.dictionary.forms[form] = true
+		if suggest {
+			p.dictionary.suggestible[strings.ToLower(form)] = form
+		}
+	}
+
+	if !needAffix || forbidden {
+		add(entry.word)
+	}
+	for _, suffix := range suffixes {
+		suffixed, ok := suffix.apply(entry.word)
+		if !ok {
+			continue
+		}
+		add(suffixed)
+		for _, prefix := range prefixes {
+			if !prefix.cross || !suffix.cross {
+				continue
+			}
+			if _, ok := prefix.apply(entry.word); ok {
+				add(prefix.add + strings.TrimPrefix(suffixed, prefix.strip))
+			}
+		}
+	}
+	for _, prefix := range prefixes {
+		if prefixed, ok := prefix.apply(entry.word); ok {
+			add(prefixed)
+		}
+	}
+}
+
+// apply returns the word with the affix when the word meets its condition.
+func (a *hunspellAffix) apply(word string) (string, bool) {
+	runes := []rune(word)
+	if len(runes) < len(a.condition) {
+		return "", false
+	}
+
+	if a.prefix {
+		for i, c := range a.condition {
+			if !c.matches(runes[i]) {
+				return "", false
+			}
+		}
+		if !strings.HasPrefix(word, a.strip) || len(a.strip) >= len(word) {
+			return "", false
+		}
+		return a.add + word[len(a.strip):], true
+	}
+
+	offset := len(runes) - len(a.condition)
+	for i, c := range a.condition {
+		if !c.matches(runes[offset+i]) {
+			return "", false
+		}
+	}
+	if !strings.HasSuffix(word, a.strip) || len(a.strip) >= len(word) {
+		return "", false
+	}
+	return word[:len(word)-len(a.strip)] + a.add, true
+}
+
+// Check reports whether word is spelled correctly. Like Hunspell, a lowercase
+// dictionary word is also accepted capitalized or in capitals.
+func (d *HunspellDictionary) Check(word string) bool {
+	word = strings.ReplaceAll(word, "’", "'")
+	for _, variant := range caseVariants(word) {
+		if d.forbidden[variant] {
+			return false
+		}
+		if d.forms[variant] {
+			return true
+		}
+	}
+	return false
+}
+
+// caseVariants returns word followed by the spellings under which a
+// dictionary may list it.
+func caseVariants(word string) []string {
+	variants := []string{word}
+	lower := strings.ToLower(word)
+	switch {
+	case lower == word:
+	case word == capitalize(lower):
+		variants = append(variants, lower)
+	case word == strings.ToUpper(word):
+		variants = append(variants, lower, capitalize(lower))
+	}
+	return variants
+}
+
+func capitalize(word string) string {
+	r, size := utf8.DecodeRuneInString(word)
+	if r == utf8.RuneError {
+		return word
+	}
+	return string(unicode.ToTitle(r)) + word[size:]
+}
+
+// Suggest returns up to limit correctly spelled words close to word: first
+// the words one edit away or produced by the REP table of the dictionary,
+// then the words two edits away, which are only looked up for words of up to
+// maxDoubleEditRunes. Words longer than maxSuggestedRunes get no suggestions.
+// Candidates at the same distance are ordered by rank, lowest first, then
+// alphabetically. Suggestions follow the capitalization of word.
+func (d *HunspellDictionary) Suggest(ctx context.Context, word string, limit int, rank func(string) float64) ([]string, error) {
+	word = strings.ReplaceAll(word, "’", "'")
+	lower := strings.ToLower(word)
+	length := utf8.RuneCountInString(lower)
+	if length > maxSuggestedRunes {
+		return []string{}, nil
+	}
+
+	alphabet := suggestionAlphabet(d.try, lower)
+
+	candidates := make(map[string]bool)
+	collect := func(edit string) {
+		if form, ok := d.suggestible[edit]; ok && edit != lower {
+			candidates[form] = true
+		}
+	}
+
+	var first []string
+	forEachEdit(lower, alphabet, func(edit string) {
+		first = append(first, edit)
+		collect(edit)
+	})
+	for _, rep := range d.replacements {
+		for i := strings.Index(lower, rep[0]); i >= 0; {
+			collect(lower[:i] + rep[1] + lower[i+len(rep[0]):])
+			next := strings.Index(lower[i+1:], rep[0])
+			if next < 0 {
+				break
+			}
+			i += next + 1
+		}
+	}
+	for i := range lower {
+		head, headOK := d.suggestible[lower[:i]]
+		tail, tailOK := d.suggestible[lower[i:]]
+		if i > 0 && headOK && tailOK && !isAllCaps(head) && !isAllCaps(tail) {
+			candidates[head+" "+tail] = true
+		}
+	}
+	if len(candidates) == 0 && length <= maxDoubleEditRunes {
+		for _, edit := range first {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			forEachEdit(edit, alphabet, collect)
+		}
+	}
+
+	type candidate struct {
+		form     string
+		distance int
+		rank     float64
+	}
+	ranked := make([]candidate, 0, len(candidates))
+	for form := range candidates {
+		distance, err := damerau(ctx, []rune(lower), []rune(strings.ToLower(form)))
+		if err != nil {
+			return nil, err
+		}
+		c := candidate{form: form, distance: distance}
+		if rank != nil {
+			c.rank = rank(strings.ToLower(form))
+		}
+		ranked = append(ranked, c)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].distance != ranked[j].distance {
+			return ranked[i].distance < ranked[j].distance
+		}
+		if ranked[i].rank != ranked[j].rank {
+			return ranked[i].rank < ranked[j].rank
+		}
+		return ranked[i].form < ranked[j].form
+	})
+
+	suggestions := []string{}
+	for _, c := range ranked {
+		if len(suggestions) == limit {
+			break
+		}
+		suggestions = append(suggestions, matchCase(c.form, word))
+	}
+	return suggestions, nil
+}
+
+// suggestionAlphabet returns the lowercase characters inserted and replaced
+// when generating suggestions: the TRY characters of the dictionary, or the
+// characters of word when it has none.
+func suggestionAlphabet(try []rune, word string) []rune {
+	if len(try) == 0 {
+		try = []rune(word)
+	}
+	seen := make(map[rune]bool)
+	var alphabet []rune
+	for _, r := range try {
+		r = unicode.ToLower(r)
+		if !seen[r] {
+			seen[r] = true
+			alphabet = append(alphabet, r)
+		}
+	}
+	return alphabet
+}
+
+// forEachEdit calls fn with every string one deletion, transposition,
+// replacement or insertion of a character of alphabet away from word. The
+// same string may be passed more than once.
+func forEachEdit(word string, alphabet []rune, fn func(string)) {
+	runes := []rune(word)
+	for i := 0; i <= len(runes); i++ {
+		head, tail := string(runes[:i]), runes[i:]
+		if len(tail) > 0 {
+			fn(head + string(tail[1:]))
+		}
+		if len(tail) > 1 {
+			fn(head + string(tail[1]) + string(tail[0]) + string(tail[2:]))
+		}
+		for _, r := range alphabet {
+			if len(tail) > 0 && r != tail[0] {
+				fn(head + string(r) + string(tail[1:]))
+			}
+			fn(head + string(r) + string(tail))
+		}
+	}
+}
+
+// matchCase capitalizes or uppercases a lowercase suggestion like word.
+func matchCase(suggestion, word string) string {
+	if suggestion != strings.ToLower(suggestion) {
+		return suggestion
+	}
+	switch {
+	case utf8.RuneCountInString(word) > 1 && word == strings.ToUpper(word):
+		return strings.ToUpper(suggestion)
+	case word == capitalize(strings.ToLower(word)) && word != strings.ToLower(word):
+		return capitalize(suggestion)
+	}
+	return suggestion
+}
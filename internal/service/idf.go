@@ -0,0 +1,103 @@
+package service
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:embed data/idf/*.txt
+var idfFiles embed.FS
+
+// IDFTable holds the inverse document frequency of case-folded terms, used to
+// weight terms by how specific they are to a document.
+type IDFTable struct {
+	weights map[string]float64
+	unknown float64
+}
+
+var (
+	idfTablesOnce sync.Once
+	idfTables     map[string]*IDFTable
+)
+
+// defaultIDFTable returns the embedded table of a language, or nil when the
+// language has none.
+func defaultIDFTable(language string) *IDFTable {
+	idfTablesOnce.Do(loadIDFTables)
+	return idfTables[language]
+}
+
+func loadIDFTables() {
+	idfTables = make(map[string]*IDFTable)
+
+	entries, err := idfFiles.ReadDir("data/idf")
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		file, err := idfFiles.Open("data/idf/" + entry.Name())
+		if err != nil {
+			continue
+		}
+
+		table, err := parseIDFTable(file)
+		_ = file.Close()
+		if err != nil {
+			continue
+		}
+
+		idfTables[strings.TrimSuffix(entry.Name(), ".txt")] = table
+	}
+}
+
+// parseIDFTable reads lines holding a term and its idf separated by
+// whitespace. Terms missing from the table are weighted one above the largest
+// listed idf, as they are rarer than any of them.
+func parseIDFTable(r io.Reader) (*IDFTable, error) {
+	table := &IDFTable{weights: make(map[string]float64)}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected a term and an idf", line)
+		}
+		weight, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid idf %q", line, fields[1])
+		}
+
+		table.weights[foldTerm(fields[0])] = weight
+		table.unknown = math.Max(table.unknown, weight+1)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// smoothedIDF returns the idf ln((1+N)/(1+df)) + 1 of a term found in
+// frequency of total documents.
+func smoothedIDF(total, frequency int) float64 {
+	return math.Log(float64(1+total)/float64(1+frequency)) + 1
+}
+
+func (t *IDFTable) weight(term string) float64 {
+	if weight, ok := t.weights[term]; ok {
+		return weight
+	}
+	return t.unknown
+}
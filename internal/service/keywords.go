@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"vm-chan/internal/domain"
+)
+
+const (
+	textRankDamping    = 0.85
+	textRankIterations = 50
+	textRankTolerance  = 1e-6
+)
+
+type keywordsAnalyzer struct {
+	corpus *SearchIndex
+}
+
+// NewKeywordsAnalyzer returns an analyzer extracting keywords. TF-IDF weights
+// words by their inverse document frequency over the documents stored by the
+// user found in the request context, when corpus is not nil and holds enough
+// of them, and by the embedded table of the language otherwise.
+func NewKeywordsAnalyzer(corpus *SearchIndex) Analyzer {
+	return &keywordsAnalyzer{corpus: corpus}
+}
+
+func (a *keywordsAnalyzer) Name() string {
+	return "keywords"
+}
+
+func (a *keywordsAnalyzer) Version() string {
+	return "1.0.0"
+}
+
+func (a *keywordsAnalyzer) Description() string {
+	return "Keyword and keyphrase extraction with RAKE and TextRank, and TF-IDF when an IDF table " +
+		"is available for the stored documents of the user or the language, with the offsets of every occurrence"
+}
+
+func (a *keywordsAnalyzer) Analyze(ctx context.Context, doc *Document) (interface{}, error) {
+	topN, err := topNOption(doc.Options.TopN)
+	if err != nil {
+		return nil, err
+	}
+
+	phrases := candidatePhrases(doc)
+	result := &domain.KeywordsResult{
+		RAKE:     rake(doc, phrases).top(topN),
+		TextRank: textRank(doc, phrases).top(topN),
+	}
+	if table := a.idfTable(ctx, doc); table != nil {
+		result.TFIDF = tfidf(doc, phrases, table).top(topN)
+	}
+
+	return result, nil
+}
+
+// idfTable returns the table computed from the stored documents of the user,
+// or the embedded table of the document language, or nil when neither
+// exists.
+func (a *keywordsAnalyzer) idfTable(ctx context.Context, doc *Document) *IDFTable {
+	if user, ok := domain.UserFromContext(ctx); ok && a.corpus != nil {
+		if table := a.corpus.idfTable(user.ID, doc.terms()); table != nil {
+			return table
+		}
+	}
+	return defaultIDFTable(doc.Language)
+}
+
+// candidatePhrases splits the document into runs of consecutive content words,
+// as token index ranges. Stopwords, numbers and any punctuation between two
+// words end a run.
+func candidatePhrases(doc *Document) [][2]int {
+	tokens := doc.Tokens()
+	terms := doc.terms()
+	runes := doc.runes()
+	stopwords := stopwordsFor(doc.Language)
+
+	var phrases [][2]int
+	start := -1
+	for i, token := range tokens {
+		isContent := token.Kind == TokenWord && !stopwords[terms[i]]
+		if start >= 0 && (!isContent || !onlySpaces(runes[tokens[i-1].End:token.Start])) {
+			phrases = append(phrases, [2]int{start, i})
+			start = -1
+		}
+		if isContent && start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		phrases = append(phrases, [2]int{start, len(tokens)})
+	}
+
+	return phrases
+}
+
+func onlySpaces(runes []rune) bool {
+	for _, r := range runes {
+		if !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// keywordScores accumulates keywords by their case-folded text.
+type keywordScores struct {
+	keywords map[string]*domain.Keyword
+}
+
+func newKeywordScores() *keywordScores {
+	return &keywordScores{keywords: make(map[string]*domain.Keyword)}
+}
+
+// add records an occurrence of the tokens [from, to) with the score of the
+// phrase, which is the same for every occurrence.
+func (k *keywordScores) add(doc *Document, from, to int, score float64) {
+	phrase := strings.Join(doc.terms()[from:to], " ")
+	keyword, ok := k.keywords[phrase]
+	if !ok {
+		keyword = &domain.Keyword{Phrase: phrase, Score: score}
+		k.keywords[phrase] = keyword
+	}
+
+	tokens := doc.Tokens()
+	keyword.Occurrences = append(keyword.Occurrences, domain.TextSpan{
+		Start: tokens[from].Start,
+		End:   tokens[to-1].End,
+	})
+}
+
+func (k *keywordScores) top(n int) []domain.Keyword {
+	keywords := make([]domain.Keyword, 0, len(k.keywords))
+	for _, keyword := range k.keywords {
+		keyword.Score = round4(keyword.Score)
+		keywords = append(keywords, *keyword)
+	}
+
+	sort.Slice(keywords, func(i, j int) bool {
+		if keywords[i].Score != keywords[j].Score {
+			return keywords[i].Score > keywords[j].Score
+		}
+		return keywords[i].Phrase < keywords[j].Phrase
+	})
+
+	if len(keywords) > n {
+		keywords = keywords[:n]
+	}
+	return keywords
+}
+
+// rake scores phrases with Rapid Automatic Keyword Extraction: every word is
+// scored by its degree, the total length of the phrases it occurs in, divided
+// by its frequency, and a phrase by the sum of its word scores.
+func rake(doc *Document, phrases [][2]int) *keywordScores {
+	terms := doc.terms()
+	frequency := make(map[string]int)
+	degree := make(map[string]int)
+	for _, phrase := range phrases {
+		for _, term := range terms[phrase[0]:phrase[1]] {
+			frequency[term]++
+			degree[term] += phrase[1] - phrase[0]
+		}
+	}
+
+	scores := newKeywordScores()
+	for _, phrase := range phrases {
+		score := 0.0
+		for _, term := range terms[phrase[0]:phrase[1]] {
+			score += float64(degree[term]) / float64(frequency[term])
+		}
+		scores.add(doc, phrase[0], phrase[1], score)
+	}
+	return scores
+}
+
+// textRank ranks content words with PageRank over a graph linking words that
+// follow each other in a sentence, keeps the top third and joins adjacent kept
+// words into keyphrases scored by the sum of their ranks.
+func textRank(doc *Document, phrases [][2]int) *keywordScores {
+	terms := doc.terms()
+	neighbors := make(map[string]map[string]bool)
+	link := func(a, b string) {
+		if neighbors[a] == nil {
+			neighbors[a] = make(map[string]bool)
+		}
+		if a != b {
+			neighbors[a][b] = true
+		}
+	}
+
+	sentences := doc.sentences()
+	sentence := 0
+	previous := ""
+	for _, phrase := range phrases {
+		start := doc.Tokens()[phrase[0]].Start
+		for sentence < len(sentences) && start >= sentences[sentence].end {
+			sentence++
+			previous = ""
+		}
+		for _, term := range terms[phrase[0]:phrase[1]] {
+			link(term, term)
+			if previous != "" {
+				link(previous, term)
+				link(term, previous)
+			}
+			previous = term
+		}
+	}
+
+	ranks := pageRank(neighbors)
+
+	ranked := make([]string, 0, len(ranks))
+	for term := range ranks {
+		ranked = append(ranked, term)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranks[ranked[i]] != ranks[ranked[j]] {
+			return ranks[ranked[i]] > ranks[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+	kept := make(map[string]bool)
+	for _, term := range ranked[:(len(ranked)+2)/3] {
+		kept[term] = true
+	}
+
+	scores := newKeywordScores()
+	for _, phrase := range phrases {
+		start := -1
+		score := 0.0
+		for i := phrase[0]; i <= phrase[1]; i++ {
+			if i < phrase[1] && kept[terms[i]] {
+				if start < 0 {
+					start = i
+					score = 0
+				}
+				score += ranks[terms[i]]
+				continue
+			}
+			if start >= 0 {
+				scores.add(doc, start, i, score)
+				start = -1
+			}
+		}
+	}
+	return scores
+}
+
+func pageRank(neighbors map[string]map[string]bool) map[string]float64 {
+	ranks := make(map[string]float64, len(neighbors))
+	for term := range neighbors {
+		ranks[term] = 1
+	}
+
+	for iteration := 0; iteration < textRankIterations; iteration++ {
+		next := make(map[string]float64, len(ranks))
+		change := 0.0
+		for term := range neighbors {
+			sum := 0.0
+			for neighbor := range neighbors[term] {
+				sum += ranks[neighbor] / float64(len(neighbors[neighbor]))
+			}
+			next[term] = 1 - textRankDamping + textRankDamping*sum
+			change = math.Max(change, math.Abs(next[term]-ranks[term]))
+		}
+		ranks = next
+		if change < textRankTolerance {
+			break
+		}
+	}
+
+	return ranks
+}
+
+// tfidf scores every content word by its share of the words of the document
+// multiplied by its inverse document frequency.
+func tfidf(doc *Document, phrases [][2]int, table *IDFTable) *keywordScores {
+	terms := doc.terms()
+	words := 0
+	for _, token := range doc.Tokens() {
+		if token.Kind == TokenWord {
+			words++
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, phrase := range phrases {
+		for _, term := range terms[phrase[0]:phrase[1]] {
+			counts[term]++
+		}
+	}
+
+	scores := newKeywordScores()
+	for _, phrase := range phrases {
+		for i := phrase[0]; i < phrase[1]; i++ {
+			tf := float64(counts[terms[i]]) / float64(words)
+			scores.add(doc, i, i+1, tf*table.weight(terms[i]))
+		}
+	}
+	return scores
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"vm-chan/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func analyzeKeywords(t *testing.T, text, language string) *domain.KeywordsResult {
+	t.Helper()
+
+	return analyzeDoc[*domain.KeywordsResult](t, NewKeywordsAnalyzer(nil), text, language)
+}
+
+func phrases(keywords []domain.Keyword) []string {
+	phrases := make([]string, 0, len(keywords))
+	for _, keyword := range keywords {
+		phrases = append(phrases, keyword.Phrase)
+	}
+	return phrases
+}
+
+func TestKeywordsAnalyzer_RAKE(t *testing.T) {
+	text := "The payment gateway rejected my credit card. I contacted customer support, " +
+		"but the payment gateway still fails."
+	result := analyzeKeywords(t, text, "en")
+
+	require.NotEmpty(t, result.RAKE)
+	assert.Equal(t, []string{
+		"payment gateway still fails", "payment gateway rejected", "contacted customer support", "credit card",
+	}, phrases(result.RAKE))
+
+	runes := []rune(text)
+	for _, keyword := range result.RAKE {
+		require.NotEmpty(t, keyword.Occurrences)
+		for _, occurrence := range keyword.Occurrences {
+			assert.Equal(t, keyword.Phrase, strings.ToLower(string(runes[occurrence.Start:occurrence.End])))
+		}
+	}
+}
+
+func TestKeywordsAnalyzer_TextRank(t *testing.T) {
+	text := "Machine learning models need good training data. Poor training data quality hurts " +
+		"machine learning models. Engineers review data quality weekly."
+	result := analyzeKeywords(t, text, "en")
+
+	require.NotEmpty(t, result.TextRank)
+	assert.Equal(t, "training data quality", result.TextRank[0].Phrase)
+	assert.Equal(t, []domain.TextSpan{{Start: 54, End: 75}}, result.TextRank[0].Occurrences)
+	assert.Contains(t, phrases(result.TextRank), "training data")
+	for i := 1; i < len(result.TextRank); i++ {
+		assert.GreaterOrEqual(t, result.TextRank[i-1].Score, result.TextRank[i].Score)
+	}
+}
+
+func TestKeywordsAnalyzer_TFIDF(t *testing.T) {
+	result := analyzeKeywords(t, "The invoice shows the wrong amount. Please fix the invoice.", "en")
+
+	require.NotEmpty(t, result.TFIDF)
+	assert.Equal(t, "invoice", result.TFIDF[0].Phrase)
+	assert.Equal(t, []domain.TextSpan{{Start: 4, End: 11}, {Start: 51, End: 58}}, result.TFIDF[0].Occurrences)
+}
+
+func TestKeywordsAnalyzer_WithoutIDFTable(t *testing.T) {
+	result := analyzeKeywords(t, "Der Kunde meldet eine falsche Rechnung.", "de")
+
+	assert.NotEmpty(t, result.RAKE)
+	assert.Nil(t, result.TFIDF)
+}
+
+func TestKeywordsAnalyzer_StoredDocuments(t *testing.T) {
+	corpus := NewSearchIndex(NewTokenizer())
+	analyzer := NewKeywordsAnalyzer(corpus)
+	alice := domain.WithUser(context.Background(), &domain.User{ID: "alice"})
+	analyze := func(ctx context.Context, text, language string) *domain.KeywordsResult {
+		return analyzeDocument[*domain.KeywordsResult](t, ctx, analyzer, testDocument(text, language))
+	}
+	text := "Der Kunde will eine Erstattung wegen eines Anmeldeproblems."
+
+	assert.Nil(t, analyze(alice, text, "de").TFIDF, "too few stored documents")
+
+	for i := 0; i < minCorpusDocuments; i++ {
+		require.NoError(t, corpus.Add(&domain.Document{UserID: "alice", ID: fmt.Sprint(i), Text: "Der Kunde will eine Erstattung."}))
+	}
+	require.NoError(t, corpus.Add(&domain.Document{UserID: "alice", ID: "login", Text: "Ein Anmeldeproblem."}))
+
+	result := analyze(alice, text, "de")
+	require.NotEmpty(t, result.TFIDF)
+	assert.Equal(t, "anmeldeproblems", result.TFIDF[0].Phrase, "words unseen in the stored documents weigh most")
+	scores := make(map[string]float64)
+	for _, keyword := range result.TFIDF {
+		scores[keyword.Phrase] = keyword.Score
+	}
+	assert.Less(t, scores["erstattung"], scores["anmeldeproblems"])
+
+	assert.Nil(t, analyze(context.Background(), text, "de").TFIDF, "documents of a user are used for that user only")
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+)
+
+type languageAnalyzer struct{}
+
+func NewLanguageAnalyzer() Analyzer {
+	return &languageAnalyzer{}
+}
+
+func (a *languageAnalyzer) Name() string {
+	return "language"
+}
+
+func (a *languageAnalyzer) Version() string {
+	return "1.0.0"
+}
+
+func (a *languageAnalyzer) Description() string {
+	return "Offline language identification from character n-gram profiles of " +
+		"42 languages, with candidate confidences and the share of each script"
+}
+
+func (a *languageAnalyzer) Analyze(ctx context.Context, doc *Document) (interface{}, error) {
+	return doc.languageDetection(), nil
+}
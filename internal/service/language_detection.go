@@ -0,0 +1,350 @@
+package service
+
+import (
+	"embed"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"vm-chan/internal/domain"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+//go:embed data/languages/*.txt
+var languageSampleFiles embed.FS
+
+const (
+	maxNgramLength        = 3
+	ngramSmoothing        = 0.5
+	maxLanguageCandidates = 5
+	// minDetectionConfidence is the confidence a detected language needs before
+	// its rules replace the multilingual defaults.
+	minDetectionConfidence = 0.5
+	otherScript            = "Other"
+)
+
+type detectionScript struct {
+	name  string
+	table *unicode.RangeTable
+}
+
+var detectionScripts = []detectionScript{
+	{"Latin", unicode.Latin},
+	{"Cyrillic", unicode.Cyrillic},
+	{"Greek", unicode.Greek},
+	{"Arabic", unicode.Arabic},
+	{"Hebrew", unicode.Hebrew},
+	{"Han", unicode.Han},
+	{"Hiragana", unicode.Hiragana},
+	{"Katakana", unicode.Katakana},
+	{"Hangul", unicode.Hangul},
+	{"Thai", unicode.Thai},
+	{"Devanagari", unicode.Devanagari},
+	{"Bengali", unicode.Bengali},
+	{"Tamil", unicode.Tamil},
+	{"Armenian", unicode.Armenian},
+	{"Georgian", unicode.Georgian},
+}
+
+// Scripts that identify a language on their own. Han is shared by Chinese and
+// Japanese and is attributed to Japanese when the text also contains kana.
+var scriptLanguages = map[string]string{
+	"Greek":      "el",
+	"Hebrew":     "he",
+	"Hiragana":   "ja",
+	"Katakana":   "ja",
+	"Hangul":     "ko",
+	"Thai":       "th",
+	"Devanagari": "hi",
+	"Bengali":    "bn",
+	"Tamil":      "ta",
+	"Armenian":   "hy",
+	"Georgian":   "ka",
+}
+
+var languageNames = map[string]string{
+	"ar": "Arabic", "bg": "Bulgarian", "bn": "Bengali", "ca": "Catalan", "cs": "Czech",
+	"da": "Danish", "de": "German", "el": "Greek", "en": "English", "es": "Spanish",
+	"et": "Estonian", "fa": "Persian", "fi": "Finnish", "fr": "French", "he": "Hebrew",
+	"hi": "Hindi", "hr": "Croatian", "hu": "Hungarian", "hy": "Armenian", "id": "Indonesian",
+	"it": "Italian", "ja": "Japanese", "ka": "Georgian", "ko": "Korean", "lt": "Lithuanian",
+	"lv": "Latvian", "nl": "Dutch", "no": "Norwegian", "pl": "Polish", "pt": "Portuguese",
+	"ro": "Romanian", "ru": "Russian", "sk": "Slovak", "sl": "Slovenian", "sr": "Serbian",
+	"sv": "Swedish", "ta": "Tamil", "th": "Thai", "tr": "Turkish", "uk": "Ukrainian",
+	"vi": "Vietnamese", "zh": "Chinese",
+}
+
+// ngramProfile holds the character n-gram counts of one language, built from
+// its embedded sample text.
+type ngramProfile struct {
+	language string
+	counts   map[string]int
+	total    int
+}
+
+// scriptProfiles are the profiles of the languages written in one script, with
+// the number of distinct n-grams across all of them used for smoothing.
+type scriptProfiles struct {
+	profiles   []*ngramProfile
+	vocabulary int
+}
+
+var (
+	languageProfilesOnce sync.Once
+	languageProfiles     map[string]*scriptProfiles
+)
+
+func profilesFor(script string) *scriptProfiles {
+	languageProfilesOnce.Do(loadLanguageProfiles)
+	return languageProfiles[script]
+}
+
+func loadLanguageProfiles() {
+	languageProfiles = make(map[string]*scriptProfiles)
+
+	entries, err := languageSampleFiles.ReadDir("data/languages")
+	if err != nil {
+		return
+	}
+
+	vocabularies := make(map[string]map[string]bool)
+	for _, entry := range entries {
+		data, err := languageSampleFiles.ReadFile("data/languages/" + entry.Name())
+		if err != nil {
+			continue
+		}
+
+		sample := scanScripts(string(data))
+		script := sample.dominantScript()
+		if script == "" {
+			continue
+		}
+
+		profile := &ngramProfile{
+			language: strings.TrimSuffix(entry.Name(), ".txt"),
+			counts:   characterNgrams(sample.words[script]),
+		}
+		if vocabularies[script] == nil {
+			vocabularies[script] = make(map[string]bool)
+			languageProfiles[script] = &scriptProfiles{}
+		}
+		for gram, count := range profile.counts {
+			profile.total += count
+			vocabularies[script][gram] = true
+		}
+		languageProfiles[script].profiles = append(languageProfiles[script].profiles, profile)
+	}
+
+	for script, profiles := range languageProfiles {
+		profiles.vocabulary = len(vocabularies[script])
+	}
+}
+
+// scriptSample is text split by script: the number of letters and the
+// case-folded words written in each script.
+type scriptSample struct {
+	letters map[string]int
+	words   map[string][]string
+	total   int
+}
+
+func scanScripts(text string) *scriptSample {
+	sample := &scriptSample{
+		letters: make(map[string]int),
+		words:   make(map[string][]string),
+	}
+
+	var word []rune
+	wordScript := ""
+	flush := func() {
+		if len(word) > 0 {
+			sample.words[wordScript] = append(sample.words[wordScript], string(word))
+			word = word[:0]
+		}
+	}
+
+	for _, r := range norm.NFC.String(strings.ToLower(text)) {
+		if isCombiningMark(r) && len(word) > 0 {
+			word = append(word, r)
+			continue
+		}
+		if !unicode.IsLetter(r) {
+			flush()
+			continue
+		}
+
+		script := scriptOf(r)
+		if script != wordScript {
+			flush()
+			wordScript = script
+		}
+		word = append(word, r)
+		sample.letters[script]++
+		sample.total++
+	}
+	flush()
+
+	return sample
+}
+
+func scriptOf(r rune) string {
+	for _, script := range detectionScripts {
+		if unicode.Is(script.table, r) {
+			return script.name
+		}
+	}
+	return otherScript
+}
+
+func (s *scriptSample) dominantScript() string {
+	best := ""
+	for script, letters := range s.letters {
+		if letters > s.letters[best] || letters == s.letters[best] && script < best {
+			best = script
+		}
+	}
+	return best
+}
+
+// characterNgrams counts the character n-grams of one to three characters of
+// every word, with a space marking the start and end of the word.
+func characterNgrams(words []string) map[string]int {
+	counts := make(map[string]int)
+	for _, word := range words {
+		runes := []rune(" " + word + " ")
+		for n := 1; n <= maxNgramLength; n++ {
+			for i := 0; i+n <= len(runes); i++ {
+				gram := string(runes[i : i+n])
+				if gram != " " {
+					counts[gram]++
+				}
+			}
+		}
+	}
+	return counts
+}
+
+// detectLanguage identifies the language of text. Every script contributes in
+// proportion to its share of the letters: scripts used by a single language
+// vote for it directly, and scripts shared by several languages are scored
+// against the n-gram profiles of those languages.
+func detectLanguage(text string) *domain.LanguageResult {
+	sample := scanScripts(text)
+	result := &domain.LanguageResult{
+		Candidates: []domain.LanguageCandidate{},
+		Scripts:    []domain.ScriptShare{},
+	}
+	if sample.total == 0 {
+		return result
+	}
+
+	weights := make(map[string]float64)
+	for script, letters := range sample.letters {
+		share := float64(letters) / float64(sample.total)
+		result.Scripts = append(result.Scripts, domain.ScriptShare{
+			Script:     script,
+			Letters:    letters,
+			Proportion: round4(share),
+		})
+
+		if language, ok := scriptLanguages[script]; ok {
+			weights[language] += share
+			continue
+		}
+		if script == "Han" {
+			if sample.letters["Hiragana"]+sample.letters["Katakana"] > 0 {
+				weights["ja"] += share
+			} else {
+				weights["zh"] += share
+			}
+			continue
+		}
+		if profiles := profilesFor(script); profiles != nil {
+			for language, probability := range profiles.classify(sample.words[script]) {
+				weights[language] += share * probability
+			}
+		}
+	}
+
+	sort.Slice(result.Scripts, func(i, j int) bool {
+		if result.Scripts[i].Letters != result.Scripts[j].Letters {
+			return result.Scripts[i].Letters > result.Scripts[j].Letters
+		}
+		return result.Scripts[i].Script < result.Scripts[j].Script
+	})
+
+	for language, weight := range weights {
+		result.Candidates = append(result.Candidates, domain.LanguageCandidate{
+			Language:   language,
+			Name:       languageNames[language],
+			Confidence: weight,
+		})
+	}
+	sort.Slice(result.Candidates, func(i, j int) bool {
+		if result.Candidates[i].Confidence != result.Candidates[j].Confidence {
+			return result.Candidates[i].Confidence > result.Candidates[j].Confidence
+		}
+		return result.Candidates[i].Language < result.Candidates[j].Language
+	})
+	if len(result.Candidates) > maxLanguageCandidates {
+		result.Candidates = result.Candidates[:maxLanguageCandidates]
+	}
+	for i := range result.Candidates {
+		result.Candidates[i].Confidence = round4(result.Candidates[i].Confidence)
+	}
+
+	if len(result.Candidates) > 0 {
+		result.Language = result.Candidates[0].Language
+		result.Confidence = result.Candidates[0].Confidence
+	}
+
+	return result
+}
+
+// classify returns the probability of each language given the words, using a
+// naive Bayes model over the n-gram profiles. The log-likelihoods are divided
+// by the square root of the number of n-grams before normalizing, which keeps
+// short, ambiguous inputs from receiving near-certain scores.
+func (s *scriptProfiles) classify(words []string) map[string]float64 {
+	grams := characterNgrams(words)
+	observed := 0
+	for _, count := range grams {
+		observed += count
+	}
+
+	probabilities := make(map[string]float64, len(s.profiles))
+	if observed == 0 {
+		return probabilities
+	}
+
+	temperature := math.Sqrt(float64(observed))
+	scores := make([]float64, len(s.profiles))
+	best := math.Inf(-1)
+	for i, profile := range s.profiles {
+		denominator := float64(profile.total) + ngramSmoothing*float64(s.vocabulary)
+		for gram, count := range grams {
+			scores[i] += float64(count) * math.Log((float64(profile.counts[gram])+ngramSmoothing)/denominator)
+		}
+		scores[i] /= temperature
+		best = math.Max(best, scores[i])
+	}
+
+	sum := 0.0
+	for i := range scores {
+		scores[i] = math.Exp(scores[i] - best)
+		sum += scores[i]
+	}
+	for i, profile := range s.profiles {
+		probabilities[profile.language] = scores[i] / sum
+	}
+
+	return probabilities
+}
+
+func isKnownLanguage(language string) bool {
+	_, ok := languageNames[language]
+	return ok
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"testing"
+
+	"vm-chan/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"The children were playing in the garden while their parents prepared dinner.", "en"},
+		{"Les enfants jouaient dans le jardin pendant que leurs parents préparaient le dîner.", "fr"},
+		{"Die Kinder spielten im Garten, während ihre Eltern das Abendessen vorbereiteten.", "de"},
+		{"Los niños jugaban en el jardín mientras sus padres preparaban la cena.", "es"},
+		{"I bambini giocavano in giardino mentre i loro genitori preparavano la cena.", "it"},
+		{"As crianças brincavam no jardim enquanto os pais preparavam o jantar.", "pt"},
+		{"De kinderen speelden in de tuin terwijl hun ouders het avondeten klaarmaakten.", "nl"},
+		{"Barnen lekte i trädgården medan deras föräldrar lagade middag.", "sv"},
+		{"Lapset leikkivät puutarhassa, kun heidän vanhempansa valmistivat illallista.", "fi"},
+		{"Dzieci bawiły się w ogrodzie, a rodzice przygotowywali kolację.", "pl"},
+		{"Gyerekek játszottak a kertben, miközben a szüleik a vacsorát készítették.", "hu"},
+		{"Çocuklar bahçede oynarken anne ve babaları akşam yemeğini hazırlıyordu.", "tr"},
+		{"Trẻ em đang chơi trong vườn trong khi bố mẹ chuẩn bị bữa tối.", "vi"},
+		{"Мы были очень рады, что вы смогли приехать к нам на выходные.", "ru"},
+		{"Діти гралися в саду, поки їхні батьки готували вечерю.", "uk"},
+		{"كان الأطفال يلعبون في الحديقة بينما كان والداهم يعدون العشاء.", "ar"},
+		{"Τα παιδιά έπαιζαν στον κήπο.", "el"},
+		{"子供たちは庭で遊んでいました。", "ja"},
+		{"孩子们在花园里玩。", "zh"},
+		{"아이들이 정원에서 놀고 있었다.", "ko"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			result := detectLanguage(tt.text)
+
+			assert.Equal(t, tt.expected, result.Language)
+			assert.GreaterOrEqual(t, result.Confidence, minDetectionConfidence)
+			assert.LessOrEqual(t, len(result.Candidates), maxLanguageCandidates)
+		})
+	}
+}
+
+func TestDetectLanguage_Scripts(t *testing.T) {
+	result := detectLanguage("Hello 世界 мир")
+
+	assert.Equal(t, []domain.ScriptShare{
+		{Script: "Latin", Letters: 5, Proportion: 0.5},
+		{Script: "Cyrillic", Letters: 3, Proportion: 0.3},
+		{Script: "Han", Letters: 2, Proportion: 0.2},
+	}, result.Scripts)
+}
+
+func TestDetectLanguage_NoLetters(t *testing.T) {
+	result := detectLanguage("123 !?")
+
+	require.NotNil(t, result)
+	assert.Empty(t, result.Language)
+	assert.Zero(t, result.Confidence)
+	assert.Empty(t, result.Candidates)
+	assert.Empty(t, result.Scripts)
+}
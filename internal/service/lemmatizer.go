@@ -0,0 +1,89 @@
+package service
+
+import (
+	"bufio"
+	_ "embed"
+	"strings"
+	"sync"
+)
+
+//go:embed data/lemmas/en.txt
+var englishLemmaFile string
+
+var (
+	englishLemmasOnce sync.Once
+	englishLemmas     map[string]string
+)
+
+// lemmatizeEnglish returns the dictionary form of a case-folded English word.
+// Irregular forms are looked up in the embedded dictionary; other words have
+// their inflectional suffix removed and the spelling of the base restored.
+func lemmatizeEnglish(term string) string {
+	englishLemmasOnce.Do(loadEnglishLemmas)
+
+	term = strings.ReplaceAll(term, "’", "'")
+	if lemma, ok := englishLemmas[term]; ok {
+		return lemma
+	}
+	if i := strings.Index(term, "'"); i > 0 {
+		return lemmatizeEnglish(term[:i])
+	}
+
+	w := newSnowballWord(term, englishVowels)
+	switch n := len(w.runes); {
+	case n <= 3:
+	case w.hasSuffix("ies") || w.hasSuffix("ied"):
+		w.replace(w.longest(suffixList("ies ied")), "y")
+	case w.longest(suffixList("sses shes ches xes zzes")) != "":
+		w.remove("es")
+	case w.hasSuffix("s") && !w.precededBy("s", "siu'"):
+		w.remove("s")
+	case w.hasSuffix("eed") && !w.hasSuffix("ceed"):
+		w.remove("d")
+	case w.hasSuffix("ing") && n > 4:
+		englishRestoreBase(w, "ing")
+	case w.hasSuffix("ed") && n > 3:
+		englishRestoreBase(w, "ed")
+	}
+
+	return w.String()
+}
+
+// englishRestoreBase removes a verb ending when what precedes it contains a
+// vowel, then undoubles a final consonant or restores a silent e.
+func englishRestoreBase(w *snowballWord, suffix string) {
+	stem := &snowballWord{runes: w.runes[:w.start(suffix)], vowels: w.vowels}
+	hasVowel := false
+	for i := range stem.runes {
+		hasVowel = hasVowel || stem.isVowel(i)
+	}
+	if !hasVowel {
+		return
+	}
+	w.remove(suffix)
+
+	switch {
+	case w.longest(suffixList("bb dd ff gg mm nn pp rr tt")) != "":
+		w.runes = w.runes[:len(w.runes)-1]
+	case w.longest(suffixList("at bl iz c v dg")) != "",
+		w.hasSuffix("us") && w.isVowel(len(w.runes)-3),
+		englishShortWord(w, w.region(0)):
+		w.runes = append(w.runes, 'e')
+	}
+}
+
+func loadEnglishLemmas() {
+	englishLemmas = make(map[string]string)
+
+	scanner := bufio.NewScanner(strings.NewReader(englishLemmaFile))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		switch {
+		case len(fields) == 0 || strings.HasPrefix(fields[0], "#"):
+		case len(fields) == 1:
+			englishLemmas[fields[0]] = fields[0]
+		default:
+			englishLemmas[fields[0]] = fields[1]
+		}
+	}
+}
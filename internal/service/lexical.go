@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"math"
+	"unicode/utf8"
+
+	"vm-chan/internal/domain"
+)
+
+const (
+	mtldThreshold       = 0.72
+	hddSampleSize       = 42
+	readingWordsPerMin  = 238
+	speakingWordsPerMin = 150
+)
+
+type lexicalAnalyzer struct{}
+
+func NewLexicalAnalyzer() Analyzer {
+	return &lexicalAnalyzer{}
+}
+
+func (a *lexicalAnalyzer) Name() string {
+	return "lexical"
+}
+
+func (a *lexicalAnalyzer) Version() string {
+	return "1.1.0"
+}
+
+func (a *lexicalAnalyzer) Description() string {
+	return "Lexical richness: unique words, type-token ratio, MTLD, HD-D, hapax and dis legomena, " +
+		"word length, character and word entropy, reading and speaking time"
+}
+
+func (a *lexicalAnalyzer) Analyze(ctx context.Context, doc *Document) (interface{}, error) {
+	tokens := doc.Tokens()
+	allTerms := doc.terms()
+	forms, err := doc.wordForms()
+	if err != nil {
+		return nil, err
+	}
+
+	words := make([]string, 0, len(tokens))
+	characters := make(map[rune]int)
+	totalCharacters := 0
+	totalLength := 0
+	result := &domain.LexicalResult{}
+
+	for i, token := range tokens {
+		if token.Kind != TokenWord {
+			continue
+		}
+		words = append(words, forms[i])
+
+		length := utf8.RuneCountInString(token.Text)
+		totalLength += length
+		if length > result.MaxWordLength {
+			result.MaxWordLength = length
+		}
+
+		for _, r := range allTerms[i] {
+			characters[r]++
+			totalCharacters++
+		}
+	}
+
+	result.WordCount = len(words)
+	if len(words) == 0 {
+		return result, nil
+	}
+
+	frequencies := make(map[string]int, len(words))
+	for _, word := range words {
+		frequencies[word]++
+	}
+	for _, count := range frequencies {
+		switch count {
+		case 1:
+			result.HapaxLegomena++
+		case 2:
+			result.DisLegomena++
+		}
+	}
+
+	wordCount := float64(len(words))
+	result.UniqueWordCount = len(frequencies)
+	result.TypeTokenRatio = round4(float64(len(frequencies)) / wordCount)
+	result.MTLD = round2(mtld(words))
+	if hdd, ok := hdD(frequencies, len(words)); ok {
+		result.HDD = &hdd
+	}
+	result.AverageWordLength = round2(float64(totalLength) / wordCount)
+
+	wordCounts := make([]int, 0, len(frequencies))
+	for _, count := range frequencies {
+		wordCounts = append(wordCounts, count)
+	}
+	characterCounts := make([]int, 0, len(characters))
+	for _, count := range characters {
+		characterCounts = append(characterCounts, count)
+	}
+	result.WordEntropy = round4(shannonEntropy(wordCounts, len(words)))
+	result.CharacterEntropy = round4(shannonEntropy(characterCounts, totalCharacters))
+
+	result.ReadingTimeSeconds = round2(wordCount / readingWordsPerMin * 60)
+	result.SpeakingTimeSeconds = round2(wordCount / speakingWordsPerMin * 60)
+
+	return result, nil
+}
+
+// mtld computes the Measure of Textual Lexical Diversity (McCarthy and Jarvis,
+// 2010) as the mean of a forward and a backward pass.
+func mtld(words []string) float64 {
+	reversed := make([]string, len(words))
+	for i, word := range words {
+		reversed[len(words)-1-i] = word
+	}
+
+	return (mtldPass(words) + mtldPass(reversed)) / 2
+}
+
+// mtldPass counts how many times the running type-token ratio falls to the
+// threshold, adding a partial factor for the remainder, and divides the text
+// length by that number of factors.
+func mtldPass(words []string) float64 {
+	factors := 0.0
+	types := make(map[string]bool)
+	count := 0
+	ttr := 1.0
+
+	for _, word := range words {
+		count++
+		types[word] = true
+		ttr = float64(len(types)) / float64(count)
+
+		if ttr <= mtldThreshold {
+			factors++
+			types = make(map[string]bool)
+			count = 0
+			ttr = 1
+		}
+	}
+
+	if count > 0 {
+		factors += (1 - ttr) / (1 - mtldThreshold)
+	}
+	if factors == 0 {
+		return float64(len(words))
+	}
+
+	return float64(len(words)) / factors
+}
+
+// hdD computes HD-D (McCarthy and Jarvis, 2007): for every word type the
+// probability of drawing it at least once in a random sample of 42 tokens,
+// taken from the hypergeometric distribution, summed over all types. Texts
+// shorter than the sample have no HD-D.
+func hdD(frequencies map[string]int, tokens int) (float64, bool) {
+	if tokens < hddSampleSize {
+		return 0, false
+	}
+
+	sum := 0.0
+	for _, count := range frequencies {
+		absent := math.Exp(logChoose(tokens-count, hddSampleSize) - logChoose(tokens, hddSampleSize))
+		sum += (1 - absent) / hddSampleSize
+	}
+
+	return round4(sum), true
+}
+
+func logChoose(n, k int) float64 {
+	if k < 0 || k > n {
+		return math.Inf(-1)
+	}
+
+	a, _ := math.Lgamma(float64(n + 1))
+	b, _ := math.Lgamma(float64(k + 1))
+	c, _ := math.Lgamma(float64(n - k + 1))
+	return a - b - c
+}
+
+// shannonEntropy returns the entropy in bits of the distribution given by
+// counts summing to total.
+func shannonEntropy(counts []int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
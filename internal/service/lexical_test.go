@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"vm-chan/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLexicalAnalyzer(t *testing.T) {
+	analyzer := NewLexicalAnalyzer()
+
+	t.Run("Short text", func(t *testing.T) {
+		result := analyzeDoc[*domain.LexicalResult](t, analyzer, "The cat and the hat, and THE dog. 42", "en")
+
+		assert.Equal(t, 8, result.WordCount)
+		assert.Equal(t, 5, result.UniqueWordCount)
+		assert.Equal(t, 0.625, result.TypeTokenRatio)
+		assert.Equal(t, 8.0, result.MTLD)
+		assert.Nil(t, result.HDD)
+		assert.Equal(t, 3, result.HapaxLegomena)
+		assert.Equal(t, 1, result.DisLegomena)
+		assert.Equal(t, 3.0, result.AverageWordLength)
+		assert.Equal(t, 3, result.MaxWordLength)
+		assert.Equal(t, 2.1556, result.WordEntropy)
+		assert.Greater(t, result.CharacterEntropy, 0.0)
+		assert.Equal(t, 2.02, result.ReadingTimeSeconds)
+		assert.Equal(t, 3.2, result.SpeakingTimeSeconds)
+	})
+
+	t.Run("HD-D of a text without repetition", func(t *testing.T) {
+		words := make([]string, hddSampleSize)
+		for i := range words {
+			words[i] = fmt.Sprintf("w%d", i)
+		}
+
+		result := analyzeDoc[*domain.LexicalResult](t, analyzer, strings.Join(words, " "), "en")
+
+		require.NotNil(t, result.HDD)
+		assert.InDelta(t, 1.0, *result.HDD, 0.0001)
+	})
+
+	t.Run("HD-D falls with repetition", func(t *testing.T) {
+		result := analyzeDoc[*domain.LexicalResult](t, analyzer, strings.Repeat("one two three four five six ", 10), "en")
+
+		require.NotNil(t, result.HDD)
+		assert.InDelta(t, 6.0/hddSampleSize, *result.HDD, 0.0001)
+	})
+
+	t.Run("Unique stems", func(t *testing.T) {
+		doc := testDocument("Connect, connected, connection and connecting", "en")
+		doc.Options.Stem = true
+
+		lexical := analyzeDocument[*domain.LexicalResult](t, context.Background(), analyzer, doc)
+		assert.Equal(t, 5, lexical.WordCount)
+		assert.Equal(t, 2, lexical.UniqueWordCount)
+		assert.Equal(t, 1, lexical.HapaxLegomena)
+	})
+
+	t.Run("Empty text", func(t *testing.T) {
+		result := analyzeDoc[*domain.LexicalResult](t, analyzer, "!!!", "en")
+
+		assert.Equal(t, &domain.LexicalResult{}, result)
+	})
+}
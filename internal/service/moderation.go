@@ -0,0 +1,403 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"vm-chan/internal/domain"
+)
+
+//go:embed data/moderation/*.txt
+var moderationFiles embed.FS
+
+const (
+	severityNone     = "none"
+	severityMild     = "mild"
+	severityModerate = "moderate"
+	severitySevere   = "severe"
+)
+
+var severityRanks = map[string]int{
+	severityNone:     0,
+	severityMild:     1,
+	severityModerate: 2,
+	severitySevere:   3,
+}
+
+// leetLetters are the digits and symbols read as the letters they stand for
+// in obfuscated words such as "sh1t" or "a$$".
+var leetLetters = map[rune]rune{
+	'0': 'o',
+	'1': 'i',
+	'3': 'e',
+	'4': 'a',
+	'5': 's',
+	'7': 't',
+	'8': 'b',
+	'9': 'g',
+	'@': 'a',
+	'$': 's',
+	'!': 'i',
+	'|': 'l',
+	'+': 't',
+	'€': 'e',
+}
+
+// ModerationWordlist is a list of blocked terms, each with a category and a
+// severity, matched against text with an Aho-Corasick automaton.
+type ModerationWordlist struct {
+	terms   []moderationTerm
+	matcher *ahoCorasick
+}
+
+type moderationTerm struct {
+	text     string
+	category string
+	severity string
+}
+
+var (
+	moderationWordlistsOnce sync.Once
+	moderationWordlists     map[string]*ModerationWordlist
+)
+
+// defaultModerationWordlists returns the embedded wordlists keyed by
+// language. The wordlists are built into the binary, so a broken one is a
+// programming error and panics.
+func defaultModerationWordlists() map[string]*ModerationWordlist {
+	moderationWordlistsOnce.Do(func() {
+		moderationWordlists = make(map[string]*ModerationWordlist)
+
+		entries, err := moderationFiles.ReadDir("data/moderation")
+		if err != nil {
+			panic(fmt.Sprintf("embedded moderation wordlists: %v", err))
+		}
+		for _, entry := range entries {
+			name := strings.TrimSuffix(entry.Name(), ".txt")
+			file, err := moderationFiles.Open("data/moderation/" + entry.Name())
+			if err != nil {
+				panic(fmt.Sprintf("embedded moderation wordlist %s: %v", name, err))
+			}
+			wordlist, err := parseModerationWordlist(file)
+			_ = file.Close()
+			if err != nil {
+				panic(fmt.Sprintf("embedded moderation wordlist %s: %v", name, err))
+			}
+			moderationWordlists[primaryLanguage(name)] = wordlist
+		}
+	})
+	return moderationWordlists
+}
+
+// LoadModerationWordlists reads the wordlists of a directory, one .txt file
+// per language named after it, such as it.txt. They are added to the
+// embedded wordlists, replacing them for the same language.
+func LoadModerationWordlists(dir string) (map[string]*ModerationWordlist, error) {
+	loaded, err := loadModerationWordlistDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	wordlists := make(map[string]*ModerationWordlist)
+	for language, wordlist := range defaultModerationWordlists() {
+		wordlists[language] = wordlist
+	}
+	for name, wordlist := range loaded {
+		wordlists[primaryLanguage(name)] = wordlist
+	}
+	return wordlists, nil
+}
+
+// LoadTenantWordlists reads the wordlists of a directory, one .txt file per
+// tenant named after its ID. A tenant list applies to the texts of its
+// users on top of the list of the text language.
+func LoadTenantWordlists(dir string) (map[string]*ModerationWordlist, error) {
+	return loadModerationWordlistDir(dir)
+}
+
+func loadModerationWordlistDir(dir string) (map[string]*ModerationWordlist, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no .txt files in wordlist directory %s", dir)
+	}
+
+	wordlists := make(map[string]*ModerationWordlist, len(paths))
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open wordlist: %w", err)
+		}
+		wordlist, err := parseModerationWordlist(file)
+		_ = file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("wordlist %s: %w", path, err)
+		}
+		wordlists[strings.TrimSuffix(filepath.Base(path), ".txt")] = wordlist
+	}
+	return wordlists, nil
+}
+
+// parseModerationWordlist reads one term per line followed by a tab, its
+// category, another tab and its severity: mild, moderate or severe. Terms may
+// hold several words.
+func parseModerationWordlist(r io.Reader) (*ModerationWordlist, error) {
+	wordlist := &ModerationWordlist{}
+	var patterns [][]rune
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Split(text, "\t")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected a term, a category and a severity separated by tabs", line)
+		}
+		term := moderationTerm{
+			text:     strings.TrimSpace(fields[0]),
+			category: strings.TrimSpace(fields[1]),
+			severity: strings.TrimSpace(fields[2]),
+		}
+		if rank := severityRanks[term.severity]; rank == 0 {
+			return nil, fmt.Errorf("line %d: invalid severity %q", line, term.severity)
+		}
+		pattern, _ := moderationSkeleton(term.text)
+		if len(pattern) == 0 || term.category == "" {
+			return nil, fmt.Errorf("line %d: empty term or category", line)
+		}
+
+		wordlist.terms = append(wordlist.terms, term)
+		patterns = append(patterns, pattern)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	wordlist.matcher = newAhoCorasick(patterns)
+	return wordlist, nil
+}
+
+// moderationSkeleton undoes the usual obfuscations of text so that blocked
+// terms can be matched literally: letters are lowercased and stripped of
+// diacritics, leetspeak digits and symbols become letters, and punctuation
+// inserted in words is dropped. Whitespace separates words with a single
+// space, except between single characters, so that "f u c k" reads as one
+// word. The offsets of the runes of the skeleton in text are returned with
+// it; spaces have offset -1.
+func moderationSkeleton(text string) ([]rune, []int) {
+	runes := []rune(text)
+
+	type chunk struct {
+		runes   []rune
+		offsets []int
+	}
+	var chunks []chunk
+	var current chunk
+	flush := func() {
+		if len(current.runes) > 0 {
+			chunks = append(chunks, current)
+		}
+		current = chunk{}
+	}
+	for i, r := range runes {
+		if unicode.IsSpace(r) {
+			flush()
+			continue
+		}
+		if mapped, ok := moderationLetter(runes, i); ok {
+			current.runes = append(current.runes, mapped)
+			current.offsets = append(current.offsets, i)
+		}
+	}
+	flush()
+
+	var skeleton []rune
+	var offsets []int
+	for i, c := range chunks {
+		if i > 0 && (len(c.runes) > 1 || len(chunks[i-1].runes) > 1) {
+			skeleton = append(skeleton, ' ')
+			offsets = append(offsets, -1)
+		}
+		skeleton = append(skeleton, c.runes...)
+		offsets = append(offsets, c.offsets...)
+	}
+	return skeleton, offsets
+}
+
+// moderationLetter returns the letter runes[i] reads as in a skeleton, or
+// false when it is dropped. An exclamation mark reads as an i only inside a
+// word, not at the end of a sentence.
+func moderationLetter(runes []rune, i int) (rune, bool) {
+	r := runes[i]
+	if leet, ok := leetLetters[r]; ok {
+		if r == '!' && (i+1 == len(runes) || !isWordRune(runes[i+1])) {
+			return 0, false
+		}
+		return leet, true
+	}
+	if unicode.IsLetter(r) || unicode.IsDigit(r) {
+		return unicode.ToLower(baseLetter(r)), true
+	}
+	return 0, false
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+type moderationAnalyzer struct {
+	languages map[string]*ModerationWordlist
+	tenants   map[string]*ModerationWordlist
+}
+
+// NewModerationAnalyzer returns an analyzer matching text against the
+// wordlist of its language and the wordlist of the tenant of the user found
+// in the request context. A nil languages map selects the embedded wordlists;
+// tenants may be nil.
+func NewModerationAnalyzer(languages, tenants map[string]*ModerationWordlist) Analyzer {
+	if languages == nil {
+		languages = defaultModerationWordlists()
+	}
+	return &moderationAnalyzer{languages: languages, tenants: tenants}
+}
+
+func (a *moderationAnalyzer) Name() string {
+	return "moderation"
+}
+
+func (a *moderationAnalyzer) Version() string {
+	return "1.0.0"
+}
+
+func (a *moderationAnalyzer) Description() string {
+	return "Profanity and blocked terms of per-language and per-tenant wordlists, including leetspeak and " +
+		"punctuated spellings, with categories, severities and an optional censored text"
+}
+
+func (a *moderationAnalyzer) Analyze(ctx context.Context, doc *Document) (interface{}, error) {
+	var wordlists []*ModerationWordlist
+	if wordlist, ok := a.languages[doc.Language]; ok {
+		wordlists = append(wordlists, wordlist)
+	}
+	if user, ok := domain.UserFromContext(ctx); ok && user.TenantID != "" {
+		if wordlist, ok := a.tenants[user.TenantID]; ok {
+			wordlists = append(wordlists, wordlist)
+		}
+	}
+	if len(wordlists) == 0 {
+		languages := make([]string, 0, len(a.languages))
+		for language := range a.languages {
+			languages = append(languages, language)
+		}
+		sort.Strings(languages)
+		return nil, fmt.Errorf("%w: no moderation wordlist for language %q, supported languages: %s",
+			domain.ErrInvalidInput, doc.Language, strings.Join(languages, ", "))
+	}
+
+	matches := moderate(doc.Text, wordlists)
+
+	result := &domain.ModerationResult{
+		Flagged:    len(matches) > 0,
+		Severity:   severityNone,
+		Categories: []string{},
+		Matches:    matches,
+	}
+	categories := make(map[string]bool)
+	for _, match := range matches {
+		if severityRanks[match.Severity] > severityRanks[result.Severity] {
+			result.Severity = match.Severity
+		}
+		if !categories[match.Category] {
+			categories[match.Category] = true
+			result.Categories = append(result.Categories, match.Category)
+		}
+	}
+	sort.Strings(result.Categories)
+
+	if doc.Options.Censor {
+		result.Censored = censor(doc.Text, matches)
+	}
+
+	return result, nil
+}
+
+// moderate returns the blocked terms of wordlists found in text, ordered by
+// offset. Only whole words match, and of overlapping matches the longest,
+// then the most severe, is kept.
+func moderate(text string, wordlists []*ModerationWordlist) []domain.ModerationMatch {
+	runes := []rune(text)
+	skeleton, offsets := moderationSkeleton(text)
+
+	var candidates []domain.ModerationMatch
+	for _, wordlist := range wordlists {
+		for _, found := range wordlist.matcher.FindAll(skeleton) {
+			// A match covers whole words of the skeleton, so that a term
+			// inside a word joined by dropped punctuation, as in
+			// "cock-tail", does not match.
+			if found.Start > 0 && skeleton[found.Start-1] != ' ' || found.End < len(skeleton) && skeleton[found.End] != ' ' {
+				continue
+			}
+			start, end := offsets[found.Start], offsets[found.End-1]+1
+			if strings.IndexFunc(string(runes[start:end]), unicode.IsLetter) < 0 {
+				continue
+			}
+			term := wordlist.terms[found.Pattern]
+			candidates = append(candidates, domain.ModerationMatch{
+				Text:     string(runes[start:end]),
+				Term:     term.text,
+				Category: term.category,
+				Severity: term.severity,
+				Start:    start,
+				End:      end,
+			})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Start != b.Start {
+			return a.Start < b.Start
+		}
+		if a.End != b.End {
+			return a.End > b.End
+		}
+		return severityRanks[a.Severity] > severityRanks[b.Severity]
+	})
+
+	matches := []domain.ModerationMatch{}
+	for _, candidate := range candidates {
+		if len(matches) > 0 && candidate.Start < matches[len(matches)-1].End {
+			continue
+		}
+		matches = append(matches, candidate)
+	}
+	return matches
+}
+
+// censor replaces the characters of the matches in text with asterisks,
+// keeping whitespace.
+func censor(text string, matches []domain.ModerationMatch) string {
+	runes := []rune(text)
+	for _, match := range matches {
+		for i := match.Start; i < match.End; i++ {
+			if !unicode.IsSpace(runes[i]) {
+				runes[i] = '*'
+			}
+		}
+	}
+	return string(runes)
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"vm-chan/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAhoCorasick(t *testing.T) {
+	patterns := [][]rune{[]rune("he"), []rune("she"), []rune("his"), []rune("hers")}
+	matcher := newAhoCorasick(patterns)
+
+	assert.Equal(t, []ahoCorasickMatch{
+		{Pattern: 1, Start: 1, End: 4},
+		{Pattern: 0, Start: 2, End: 4},
+		{Pattern: 3, Start: 2, End: 6},
+	}, matcher.FindAll([]rune("ushers")))
+	assert.Empty(t, matcher.FindAll([]rune("xyz")))
+}
+
+func TestModerationAnalyzer(t *testing.T) {
+	tenants := map[string]*ModerationWordlist{}
+	var err error
+	tenants["acme"], err = parseModerationWordlist(strings.NewReader("# Competitors\nglobex\tcompetitor\tmild\n"))
+	require.NoError(t, err)
+	analyzer := NewModerationAnalyzer(nil, tenants)
+
+	analyze := func(t *testing.T, ctx context.Context, text, language string, censor bool) *domain.ModerationResult {
+		doc := testDocument(text, language)
+		doc.Options.Censor = censor
+		return analyzeDocument[*domain.ModerationResult](t, ctx, analyzer, doc)
+	}
+
+	t.Run("Matches, severity and censored text", func(t *testing.T) {
+		result := analyze(t, context.Background(), "What a shit day, you damn idiot", "en", true)
+
+		assert.True(t, result.Flagged)
+		assert.Equal(t, "moderate", result.Severity)
+		assert.Equal(t, []string{"insult", "profanity"}, result.Categories)
+		assert.Equal(t, []domain.ModerationMatch{
+			{Text: "shit", Term: "shit", Category: "profanity", Severity: "moderate", Start: 7, End: 11},
+			{Text: "damn", Term: "damn", Category: "profanity", Severity: "mild", Start: 21, End: 25},
+			{Text: "idiot", Term: "idiot", Category: "insult", Severity: "mild", Start: 26, End: 31},
+		}, result.Matches)
+		assert.Equal(t, "What a **** day, you **** *****", result.Censored)
+	})
+
+	t.Run("Obfuscated words", func(t *testing.T) {
+		tests := []struct {
+			text     string
+			language string
+			match    string
+			term     string
+		}{
+			{"oh f.u.c.k this", "en", "f.u.c.k", "fuck"},
+			{"that is sh1t!", "en", "sh1t", "shit"},
+			{"you are an @$$hole", "en", "@$$hole", "asshole"},
+			{"F U C K", "en", "F U C K", "fuck"},
+			{"SH!T happens", "en", "SH!T", "shit"},
+			{"du Arschl0ch", "de", "Arschl0ch", "arschloch"},
+			{"quel encule", "fr", "encule", "enculé"},
+		}
+		for _, test := range tests {
+			t.Run(test.text, func(t *testing.T) {
+				result := analyze(t, context.Background(), test.text, test.language, false)
+
+				require.Len(t, result.Matches, 1)
+				assert.Equal(t, test.match, result.Matches[0].Text)
+				assert.Equal(t, test.term, result.Matches[0].Term)
+			})
+		}
+	})
+
+	t.Run("Only whole words match", func(t *testing.T) {
+		result := analyze(t, context.Background(), "The class passed the assessment, as she hit the ball. Scunthorpe won 3-0.", "en", true)
+
+		assert.False(t, result.Flagged)
+		assert.Equal(t, "none", result.Severity)
+		assert.Empty(t, result.Matches)
+		assert.Equal(t, "The class passed the assessment, as she hit the ball. Scunthorpe won 3-0.", result.Censored)
+	})
+
+	t.Run("Terms inside hyphenated words do not match", func(t *testing.T) {
+		result := analyze(t, context.Background(), "A cock-tail at the bar, then a mass-market ass-essment.", "en", false)
+
+		assert.Empty(t, result.Matches)
+		assert.Len(t, analyze(t, context.Background(), "What a cock - really", "en", false).Matches, 1)
+	})
+
+	t.Run("Longest match wins", func(t *testing.T) {
+		result := analyze(t, context.Background(), "You son of a bitch", "en", false)
+
+		require.Len(t, result.Matches, 1)
+		assert.Equal(t, "son of a bitch", result.Matches[0].Term)
+	})
+
+	t.Run("Tenant wordlist", func(t *testing.T) {
+		text := "Try Globex instead"
+		assert.False(t, analyze(t, context.Background(), text, "en", false).Flagged)
+
+		ctx := domain.WithUser(context.Background(), &domain.User{ID: "1", TenantID: "acme"})
+		result := analyze(t, ctx, text, "en", false)
+		require.Len(t, result.Matches, 1)
+		assert.Equal(t, "competitor", result.Matches[0].Category)
+
+		result = analyze(t, ctx, text, "xx", false)
+		assert.Len(t, result.Matches, 1)
+	})
+
+	t.Run("Unsupported language", func(t *testing.T) {
+		_, err := analyzer.Analyze(context.Background(), testDocument("Un testo", "it"))
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+
+	t.Run("Invalid wordlists", func(t *testing.T) {
+		for _, list := range []string{"shit\tprofanity\n", "shit\tprofanity\tterrible\n", "...\tprofanity\tmild\n"} {
+			_, err := parseModerationWordlist(strings.NewReader(list))
+			assert.Error(t, err, list)
+		}
+	})
+}
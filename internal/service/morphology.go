@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"vm-chan/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	morphologyStem  = "stem"
+	morphologyLemma = "lemma"
+)
+
+// stemmers maps languages to their Snowball stemmer. Stemmers take a
+// case-folded word.
+var stemmers = map[string]func(string) string{
+	"da": stemDanish,
+	"de": stemGerman,
+	"en": stemEnglish,
+	"es": stemSpanish,
+	"fr": stemFrench,
+	"it": stemItalian,
+	"nl": stemDutch,
+	"no": stemNorwegian,
+	"pt": stemPortuguese,
+	"ru": stemRussian,
+	"sv": stemSwedish,
+}
+
+// morphologyFor returns the function reducing a case-folded word of a
+// language to its stem or lemma, as selected by mode.
+func morphologyFor(language, mode string) (func(string) string, error) {
+	switch mode {
+	case morphologyStem:
+		if stem, ok := stemmers[language]; ok {
+			return stem, nil
+		}
+		languages := make([]string, 0, len(stemmers))
+		for lang := range stemmers {
+			languages = append(languages, lang)
+		}
+		sort.Strings(languages)
+		return nil, fmt.Errorf("%w: no stemmer for language %q, supported languages: %s",
+			domain.ErrInvalidInput, language, strings.Join(languages, ", "))
+	case morphologyLemma:
+		if language == "en" {
+			return lemmatizeEnglish, nil
+		}
+		return nil, fmt.Errorf("%w: lemmatization is only available for en", domain.ErrInvalidInput)
+	}
+	return nil, fmt.Errorf("%w: unknown mode %q, expected %s or %s",
+		domain.ErrInvalidInput, mode, morphologyStem, morphologyLemma)
+}
+
+type morphologyService struct {
+	tokenizer Tokenizer
+	logger    *zap.Logger
+}
+
+func NewMorphologyService(tokenizer Tokenizer, logger *zap.Logger) domain.MorphologyService {
+	return &morphologyService{
+		tokenizer: tokenizer,
+		logger:    logger,
+	}
+}
+
+func (s *morphologyService) Stem(ctx context.Context, req *domain.StemRequest) (*domain.StemResponse, error) {
+	mode := req.Mode
+	if mode == "" {
+		mode = morphologyStem
+	}
+
+	language := primaryLanguage(req.Language)
+	detected := false
+	if language == "" {
+		detection := detectLanguage(req.Text)
+		if detection.Confidence < minDetectionConfidence {
+			return nil, fmt.Errorf("%w: the language of the text could not be detected, set language",
+				domain.ErrInvalidInput)
+		}
+		language = detection.Language
+		detected = true
+	}
+
+	reduce, err := morphologyFor(language, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &domain.StemResponse{
+		Language:         language,
+		LanguageDetected: detected,
+		Mode:             mode,
+		Tokens:           []domain.StemmedToken{},
+	}
+	for _, token := range s.tokenizer.Tokenize(req.Text) {
+		if token.Kind != TokenWord {
+			continue
+		}
+		response.Tokens = append(response.Tokens, domain.StemmedToken{
+			Text:  token.Text,
+			Form:  reduce(foldTerm(token.Text)),
+			Start: token.Start,
+			End:   token.End,
+		})
+	}
+
+	s.logger.Info("Stemming completed",
+		zap.String("language", language),
+		zap.String("mode", mode),
+		zap.Int("words", len(response.Tokens)),
+	)
+
+	return response, nil
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"vm-chan/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestStemmers(t *testing.T) {
+	tests := []struct {
+		language string
+		word     string
+		expected string
+	}{
+		{"en", "running", "run"},
+		{"en", "generously", "generous"},
+		{"en", "happiness", "happi"},
+		{"en", "caresses", "caress"},
+		{"en", "hoping", "hope"},
+		{"en", "communication", "communic"},
+		{"en", "skies", "sky"},
+		{"en", "skis", "ski"},
+		{"de", "möglichkeit", "moglich"},
+		{"de", "häuser", "haus"},
+		{"de", "aufeinanderfolgenden", "aufeinanderfolg"},
+		{"nl", "lichamelijke", "licham"},
+		{"nl", "aanbevelingen", "aanbevel"},
+		{"fr", "continuellement", "continuel"},
+		{"fr", "abandonnée", "abandon"},
+		{"fr", "importantes", "import"},
+		{"es", "cabalgaban", "cabalg"},
+		{"es", "acompañamientos", "acompañ"},
+		{"it", "abbandonata", "abbandon"},
+		{"it", "abbattimento", "abbatt"},
+		{"pt", "abandonados", "abandon"},
+		{"pt", "cantando", "cant"},
+		{"ru", "играли", "игра"},
+		{"ru", "красивейший", "красив"},
+		{"ru", "домами", "дом"},
+		{"sv", "jaktkarlarne", "jaktkarl"},
+		{"sv", "klokheten", "klok"},
+		{"da", "undervisningen", "undervisning"},
+		{"no", "havnedistriktene", "havnedistrikt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language+"/"+tt.word, func(t *testing.T) {
+			stem, err := morphologyFor(tt.language, morphologyStem)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, stem(tt.word))
+		})
+	}
+}
+
+func TestLemmatizeEnglish(t *testing.T) {
+	tests := map[string]string{
+		"running":  "run",
+		"hoping":   "hope",
+		"caused":   "cause",
+		"agreed":   "agree",
+		"studies":  "study",
+		"boxes":    "box",
+		"cats":     "cat",
+		"children": "child",
+		"went":     "go",
+		"was":      "be",
+		"better":   "good",
+		"isn't":    "be",
+		"series":   "series",
+		"focus":    "focus",
+		"thing":    "thing",
+	}
+
+	for word, expected := range tests {
+		assert.Equal(t, expected, lemmatizeEnglish(word), word)
+	}
+}
+
+func TestMorphologyService_Stem(t *testing.T) {
+	service := NewMorphologyService(NewTokenizer(), zap.NewNop())
+
+	t.Run("Stems with offsets", func(t *testing.T) {
+		result, err := service.Stem(context.Background(), &domain.StemRequest{
+			Text:     "Runners were running, 3 times.",
+			Language: "en-GB",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "en", result.Language)
+		assert.False(t, result.LanguageDetected)
+		assert.Equal(t, "stem", result.Mode)
+		assert.Equal(t, []domain.StemmedToken{
+			{Text: "Runners", Form: "runner", Start: 0, End: 7},
+			{Text: "were", Form: "were", Start: 8, End: 12},
+			{Text: "running", Form: "run", Start: 13, End: 20},
+			{Text: "times", Form: "time", Start: 24, End: 29},
+		}, result.Tokens)
+	})
+
+	t.Run("Lemmas", func(t *testing.T) {
+		result, err := service.Stem(context.Background(), &domain.StemRequest{
+			Text:     "The children were running",
+			Language: "en",
+			Mode:     "lemma",
+		})
+		require.NoError(t, err)
+
+		forms := make([]string, 0, len(result.Tokens))
+		for _, token := range result.Tokens {
+			forms = append(forms, token.Form)
+		}
+		assert.Equal(t, []string{"the", "child", "be", "run"}, forms)
+	})
+
+	t.Run("Detected language", func(t *testing.T) {
+		result, err := service.Stem(context.Background(), &domain.StemRequest{
+			Text: "Die Kinder spielten gestern den ganzen Nachmittag im Garten hinter dem Haus.",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "de", result.Language)
+		assert.True(t, result.LanguageDetected)
+	})
+
+	t.Run("Invalid requests", func(t *testing.T) {
+		requests := []*domain.StemRequest{
+			{Text: "Hyvää huomenta", Language: "fi"},
+			{Text: "Bonjour", Language: "fr", Mode: "lemma"},
+			{Text: "Hello", Language: "en", Mode: "root"},
+		}
+
+		for _, req := range requests {
+			_, err := service.Stem(context.Background(), req)
+			assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		}
+	})
+}
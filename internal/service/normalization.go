@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"vm-chan/internal/domain"
+
+	"go.uber.org/zap"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	normalizeNFC                = "nfc"
+	normalizeNFD                = "nfd"
+	normalizeNFKC               = "nfkc"
+	normalizeNFKD               = "nfkd"
+	normalizeCaseFold           = "case_fold"
+	normalizeStripDiacritics    = "strip_diacritics"
+	normalizeCollapseWhitespace = "collapse_whitespace"
+	normalizePunctuation        = "canonicalize_punctuation"
+	normalizeZeroWidth          = "remove_zero_width"
+	normalizeContractions       = "expand_contractions"
+)
+
+// defaultNormalizationSteps are applied when a normalization request lists no
+// steps. They change how the text is encoded, not what it says.
+var defaultNormalizationSteps = []string{
+	normalizeNFC,
+	normalizeZeroWidth,
+	normalizePunctuation,
+	normalizeCollapseWhitespace,
+}
+
+// normalizationSteps maps step names to their implementation, which receives
+// the primary language of the text, possibly empty.
+var normalizationSteps = map[string]func(text, language string) (string, error){
+	normalizeNFC:                form(norm.NFC),
+	normalizeNFD:                form(norm.NFD),
+	normalizeNFKC:               form(norm.NFKC),
+	normalizeNFKD:               form(norm.NFKD),
+	normalizeCaseFold:           caseFold,
+	normalizeStripDiacritics:    stripDiacritics,
+	normalizeCollapseWhitespace: collapseWhitespace,
+	normalizePunctuation:        canonicalizePunctuation,
+	normalizeZeroWidth:          removeZeroWidth,
+	normalizeContractions:       expandContractions,
+}
+
+// normalizeText applies steps to text in order.
+func normalizeText(text string, steps []string, language string) (string, error) {
+	for _, step := range steps {
+		apply, ok := normalizationSteps[step]
+		if !ok {
+			return "", fmt.Errorf("%w: unknown normalization step %q, available steps: %s",
+				domain.ErrInvalidInput, step, strings.Join(normalizationStepNames(), ", "))
+		}
+		var err error
+		if text, err = apply(text, language); err != nil {
+			return "", err
+		}
+	}
+	return text, nil
+}
+
+func normalizationStepNames() []string {
+	return []string{
+		normalizeNFC, normalizeNFD, normalizeNFKC, normalizeNFKD, normalizeCaseFold, normalizeStripDiacritics,
+		normalizeCollapseWhitespace, normalizePunctuation, normalizeZeroWidth, normalizeContractions,
+	}
+}
+
+func form(f norm.Form) func(string, string) (string, error) {
+	return func(text, _ string) (string, error) {
+		return f.String(text), nil
+	}
+}
+
+// caseFold applies full Unicode case folding. Turkish and Azerbaijani fold
+// dotted and dotless capital I to their own lowercase letters: İ to i and I
+// to ı.
+func caseFold(text, language string) (string, error) {
+	if language == "tr" || language == "az" {
+		text = strings.NewReplacer("I", "ı", "İ", "i").Replace(text)
+	}
+	return cases.Fold().String(text), nil
+}
+
+// undecomposedLetters are the letters whose diacritic is not a combining mark
+// in Unicode, so that decomposition does not strip it.
+var undecomposedLetters = strings.NewReplacer(
+	"ł", "l", "Ł", "L",
+	"ø", "o", "Ø", "O",
+	"đ", "d", "Đ", "D",
+	"ħ", "h", "Ħ", "H",
+)
+
+func stripDiacritics(text, _ string) (string, error) {
+	stripped, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), text)
+	if err != nil {
+		return "", err
+	}
+	return undecomposedLetters.Replace(stripped), nil
+}
+
+// collapseWhitespace replaces runs of whitespace with a single space and
+// trims the text.
+func collapseWhitespace(text, _ string) (string, error) {
+	return strings.Join(strings.Fields(text), " "), nil
+}
+
+var punctuationReplacer = strings.NewReplacer(
+	"‘", "'", "’", "'", "‚", "'", "‛", "'", "′", "'",
+	"“", `"`, "”", `"`, "„", `"`, "‟", `"`, "″", `"`, "«", `"`, "»", `"`,
+	"\u2010", "-", "\u2011", "-", "\u2012", "-", "–", "-", "—", "-", "\u2015", "-", "\u2212", "-",
+)
+
+// canonicalizePunctuation replaces typographic quotes, apostrophes and
+// dashes with their ASCII counterparts.
+func canonicalizePunctuation(text, _ string) (string, error) {
+	return punctuationReplacer.Replace(text), nil
+}
+
+// removeZeroWidth removes zero-width spaces, joiners and non-joiners, word
+// joiners, byte order marks and soft hyphens.
+func removeZeroWidth(text, _ string) (string, error) {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '\u200b', '\u200c', '\u200d', '\u2060', '\ufeff', '\u00ad':
+			return -1
+		}
+		return r
+	}, text), nil
+}
+
+var contractionPattern = regexp.MustCompile(`\p{L}+['’]\p{L}+`)
+
+// irregularContractions are the English contractions whose expansion is not
+// the base followed by the expanded suffix.
+var irregularContractions = map[string]string{
+	"won't":  "will not",
+	"can't":  "cannot",
+	"shan't": "shall not",
+	"ain't":  "is not",
+	"let's":  "let us",
+	"y'all":  "you all",
+}
+
+// contractionSuffixes expand the contracted suffixes of English words.
+var contractionSuffixes = []struct {
+	suffix    string
+	expansion string
+}{
+	{"n't", "not"},
+	{"'re", "are"},
+	{"'ve", "have"},
+	{"'ll", "will"},
+	{"'m", "am"},
+	{"'d", "would"},
+}
+
+// isContractions are the words whose 's stands for is rather than a
+// possessive.
+var isContractions = map[string]bool{
+	"it": true, "he": true, "she": true, "that": true, "what": true, "there": true, "here": true,
+	"who": true, "where": true, "how": true, "when": true, "why": true,
+}
+
+// expandContractions expands English contractions such as "don't" and
+// "she's", following the case of the contracted word. Possessives are left
+// unchanged.
+func expandContractions(text, language string) (string, error) {
+	if language != "" && language != "en" {
+		return "", fmt.Errorf("%w: contraction expansion is only available for en", domain.ErrInvalidInput)
+	}
+
+	return contractionPattern.ReplaceAllStringFunc(text, func(word string) string {
+		expanded := expandContraction(strings.ReplaceAll(word, "’", "'"))
+		if expanded == "" {
+			return word
+		}
+		switch {
+		case isAllCaps(word):
+			return strings.ToUpper(expanded)
+		case unicode.IsUpper([]rune(word)[0]):
+			return capitalize(expanded)
+		}
+		return expanded
+	}), nil
+}
+
+// expandContraction returns the lowercase expansion of an English
+// contraction with an ASCII apostrophe, or an empty string.
+func expandContraction(word string) string {
+	lower := strings.ToLower(word)
+	if expansion, ok := irregularContractions[lower]; ok {
+		return expansion
+	}
+	for _, contraction := range contractionSuffixes {
+		if base, ok := strings.CutSuffix(lower, contraction.suffix); ok && base != "" {
+			return base + " " + contraction.expansion
+		}
+	}
+	if base, ok := strings.CutSuffix(lower, "'s"); ok && isContractions[base] {
+		return base + " is"
+	}
+	return ""
+}
+
+type normalizationService struct {
+	logger *zap.Logger
+}
+
+func NewNormalizationService(logger *zap.Logger) domain.NormalizationService {
+	return &normalizationService{
+		logger: logger,
+	}
+}
+
+func (s *normalizationService) Normalize(ctx context.Context, req *domain.NormalizeRequest) (*domain.NormalizeResponse, error) {
+	steps := req.Steps
+	if len(steps) == 0 {
+		steps = defaultNormalizationSteps
+	}
+
+	text, err := normalizeText(req.Text, steps, primaryLanguage(req.Language))
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Normalization completed",
+		zap.Strings("steps", steps),
+		zap.Int("runes_before", len([]rune(req.Text))),
+		zap.Int("runes_after", len([]rune(text))),
+	)
+
+	return &domain.NormalizeResponse{Text: text, Steps: steps}, nil
+}
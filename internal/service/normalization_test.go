@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"vm-chan/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNormalizationService(t *testing.T) {
+	service := NewNormalizationService(zap.NewNop())
+
+	normalize := func(t *testing.T, text, language string, steps ...string) string {
+		response, err := service.Normalize(context.Background(), &domain.NormalizeRequest{
+			Text:     text,
+			Language: language,
+			Steps:    steps,
+		})
+		require.NoError(t, err)
+		return response.Text
+	}
+
+	t.Run("Steps", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			text     string
+			language string
+			steps    []string
+			expected string
+		}{
+			{"NFC", "cafe\u0301", "", []string{"nfc"}, "caf\u00e9"},
+			{"NFD", "caf\u00e9", "", []string{"nfd"}, "cafe\u0301"},
+			{"NFKC", "ﬁle №５", "", []string{"nfkc"}, "file No5"},
+			{"Case folding", "Straße ΣΊΣΥΦΟΣ", "", []string{"case_fold"}, "strasse σίσυφοσ"},
+			{"Turkish case folding", "İSTANBUL IRMAK", "tr", []string{"case_fold"}, "istanbul ırmak"},
+			{"Default case folding of dotted I", "IRMAK", "", []string{"case_fold"}, "irmak"},
+			{"Diacritics", "Crème brûlée à Łódź, Ørsted", "", []string{"strip_diacritics"}, "Creme brulee a Lodz, Orsted"},
+			{"Whitespace", " one\t two \n\nthree ", "", []string{"collapse_whitespace"}, "one two three"},
+			{"Punctuation", "“Quoted” ‘text’ – with — dashes", "", []string{"canonicalize_punctuation"}, `"Quoted" 'text' - with - dashes`},
+			{"Zero width", "zero\u200bwidth\u00adhy\ufeffphen", "", []string{"remove_zero_width"}, "zerowidthhyphen"},
+			{
+				"Contractions",
+				"Don't worry, I’m sure it's fine and WON'T break; the dog's bowl isn't yours",
+				"en",
+				[]string{"expand_contractions"},
+				"Do not worry, I am sure it is fine and WILL NOT break; the dog's bowl is not yours",
+			},
+			{"Composed steps", "  Don’t  SAY “Café”\u200b ", "en",
+				[]string{"remove_zero_width", "canonicalize_punctuation", "expand_contractions", "case_fold", "strip_diacritics", "collapse_whitespace"},
+				`do not say "cafe"`},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				assert.Equal(t, test.expected, normalize(t, test.text, test.language, test.steps...))
+			})
+		}
+	})
+
+	t.Run("Default steps", func(t *testing.T) {
+		response, err := service.Normalize(context.Background(), &domain.NormalizeRequest{Text: " “café”\u200b  bar "})
+		require.NoError(t, err)
+
+		assert.Equal(t, `"café" bar`, response.Text)
+		assert.Equal(t, []string{"nfc", "remove_zero_width", "canonicalize_punctuation", "collapse_whitespace"}, response.Steps)
+	})
+
+	t.Run("Invalid requests", func(t *testing.T) {
+		for name, req := range map[string]*domain.NormalizeRequest{
+			"unknown step":          {Text: "text", Steps: []string{"nfc", "uppercase"}},
+			"contractions in fr":    {Text: "l'eau", Language: "fr", Steps: []string{"expand_contractions"}},
+			"contractions in pt-BR": {Text: "d'água", Language: "pt-BR", Steps: []string{"expand_contractions"}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := service.Normalize(context.Background(), req)
+				assert.ErrorIs(t, err, domain.ErrInvalidInput)
+			})
+		}
+	})
+}
+
+func TestTextAnalysisService_AnalyzeTextNormalize(t *testing.T) {
+	registry, err := NewAnalyzerRegistry(DefaultAnalyzers(AnalyzerResources{})...)
+	require.NoError(t, err)
+	service := NewTextAnalysisService(registry, NewTokenizer(), nil, zap.NewNop())
+
+	analyze := func(sentence string) *domain.TextAnalysisResponse {
+		response, err := service.AnalyzeText(context.Background(), &domain.TextAnalysisRequest{
+			Sentence:  sentence,
+			Language:  "fr",
+			Analyses:  []string{"counts", "frequency"},
+			Normalize: []string{"nfc"},
+		})
+		require.NoError(t, err)
+		return response
+	}
+
+	composed, decomposed := analyze("Un caf\u00e9 cr\u00e8me"), analyze("Un cafe\u0301 cre\u0300me")
+	assert.Equal(t, composed, decomposed)
+	assert.Equal(t, "Un caf\u00e9 cr\u00e8me", decomposed.Sentence)
+
+	_, err = service.AnalyzeText(context.Background(), &domain.TextAnalysisRequest{
+		Sentence:  "text",
+		Normalize: []string{"unknown"},
+	})
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+}
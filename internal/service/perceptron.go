@@ -0,0 +1,342 @@
+package service
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// tagDictionaryMinCount and tagDictionaryMinShare select the words tagged
+	// without the model: those seen at least this often in training, with
+	// the same tag at least this share of the time.
+	tagDictionaryMinCount = 20
+	tagDictionaryMinShare = 0.97
+	// perceptronMinWeight drops the averaged weights too small to change a
+	// prediction from saved models.
+	perceptronMinWeight = 0.001
+)
+
+// TaggedWord is a word with its part-of-speech tag.
+type TaggedWord struct {
+	Word string
+	Tag  string
+}
+
+// PerceptronTagger is a greedy averaged perceptron part-of-speech tagger. It
+// predicts the tag of every word from the word, its affixes and shape, its
+// neighbors and the two previously predicted tags.
+type PerceptronTagger struct {
+	weights       map[string]map[string]float64
+	tags          []string
+	tagDictionary map[string]string
+}
+
+// Tag returns the tag of every word of a sentence.
+func (t *PerceptronTagger) Tag(words []string) []string {
+	context := taggerContext(words)
+	tags := make([]string, len(words))
+	previous, beforePrevious := "-START-", "-START2-"
+	for i, word := range words {
+		tag, ok := t.tagDictionary[normalizeTaggedWord(word)]
+		if !ok {
+			tag = t.predict(taggerFeatures(i, words, context, previous, beforePrevious))
+		}
+		tags[i] = tag
+		beforePrevious, previous = previous, tag
+	}
+	return tags
+}
+
+func (t *PerceptronTagger) predict(features []string) string {
+	scores := make(map[string]float64, len(t.tags))
+	for _, feature := range features {
+		for tag, weight := range t.weights[feature] {
+			scores[tag] += weight
+		}
+	}
+	best, bestScore := "", math.Inf(-1)
+	for _, tag := range t.tags {
+		if score := scores[tag]; score > bestScore {
+			best, bestScore = tag, score
+		}
+	}
+	return best
+}
+
+// taggerContext returns the normalized words of a sentence surrounded by
+// start and end markers, so that features can look two words away.
+func taggerContext(words []string) []string {
+	context := make([]string, 0, len(words)+4)
+	context = append(context, "-START-", "-START2-")
+	for _, word := range words {
+		context = append(context, normalizeTaggedWord(word))
+	}
+	return append(context, "-END-", "-END2-")
+}
+
+// normalizeTaggedWord lowercases a word, straightens its apostrophes and
+// replaces numbers and hyphenated words by placeholders, whose individual
+// spellings tell nothing of their tag.
+func normalizeTaggedWord(word string) string {
+	switch {
+	case strings.Contains(word, "-") && !strings.HasPrefix(word, "-"):
+		return "!HYPHEN"
+	case len(word) == 4 && strings.IndexFunc(word, func(r rune) bool { return !unicode.IsDigit(r) }) < 0:
+		return "!YEAR"
+	case word != "" && unicode.IsDigit([]rune(word)[0]):
+		return "!DIGITS"
+	}
+	return strings.ToLower(strings.ReplaceAll(word, "\u2019", "'"))
+}
+
+func taggerFeatures(i int, words, context []string, previous, beforePrevious string) []string {
+	word := context[i+2]
+	return []string{
+		"bias",
+		"i suffix " + suffix(word, 3),
+		"i suffix2 " + suffix(word, 2),
+		"i pref1 " + prefix(word, 1),
+		"i shape " + wordShape(words[i], i == 0),
+		"i-1 tag " + previous,
+		"i-2 tag " + beforePrevious,
+		"i tag+i-2 tag " + previous + " " + beforePrevious,
+		"i word " + word,
+		"i-1 tag+i word " + previous + " " + word,
+		"i-1 word " + context[i+1],
+		"i-1 suffix " + suffix(context[i+1], 3),
+		"i-2 word " + context[i],
+		"i+1 word " + context[i+3],
+		"i+1 suffix " + suffix(context[i+3], 3),
+		"i+2 word " + context[i+4],
+	}
+}
+
+func suffix(word string, n int) string {
+	runes := []rune(word)
+	return string(runes[max(0, len(runes)-n):])
+}
+
+func prefix(word string, n int) string {
+	runes := []rune(word)
+	return string(runes[:min(n, len(runes))])
+}
+
+// wordShape tells capitalized words apart from lowercase and all-caps ones,
+// distinguishing the capital of the first word of a sentence.
+func wordShape(word string, first bool) string {
+	r, _ := utf8.DecodeRuneInString(word)
+	switch {
+	case isAllCaps(word):
+		return "XX"
+	case unicode.IsUpper(r) && first:
+		return "Xx first"
+	case unicode.IsUpper(r):
+		return "Xx"
+	}
+	return "x"
+}
+
+// TrainPerceptronTagger trains a tagger on tagged sentences, visiting them
+// in a random order derived from seed for every iteration.
+func TrainPerceptronTagger(sentences [][]TaggedWord, iterations int, seed int64) *PerceptronTagger {
+	t := &PerceptronTagger{
+		weights:       make(map[string]map[string]float64),
+		tagDictionary: buildTagDictionary(sentences),
+	}
+	tags := make(map[string]bool)
+	for _, sentence := range sentences {
+		for _, word := range sentence {
+			tags[word.Tag] = true
+		}
+	}
+	for tag := range tags {
+		t.tags = append(t.tags, tag)
+	}
+	sort.Strings(t.tags)
+
+	// Averaging keeps the sum of every weight over all updates, adding the
+	// weight once for every update it stayed unchanged.
+	totals := make(map[string]map[string]float64)
+	stamps := make(map[string]map[string]int)
+	updates := 0
+	update := func(feature, tag string, delta float64) {
+		if t.weights[feature] == nil {
+			t.weights[feature] = make(map[string]float64)
+			totals[feature] = make(map[string]float64)
+			stamps[feature] = make(map[string]int)
+		}
+		totals[feature][tag] += float64(updates-stamps[feature][tag]) * t.weights[feature][tag]
+		stamps[feature][tag] = updates
+		t.weights[feature][tag] += delta
+	}
+
+	order := make([]int, len(sentences))
+	for i := range order {
+		order[i] = i
+	}
+	random := rand.New(rand.NewSource(seed))
+	for iteration := 0; iteration < iterations; iteration++ {
+		random.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+		for _, index := range order {
+			sentence := sentences[index]
+			words := make([]string, len(sentence))
+			for i, word := range sentence {
+				words[i] = word.Word
+			}
+			context := taggerContext(words)
+
+			previous, beforePrevious := "-START-", "-START2-"
+			for i, word := range sentence {
+				guess, ok := t.tagDictionary[normalizeTaggedWord(word.Word)]
+				if !ok {
+					features := taggerFeatures(i, words, context, previous, beforePrevious)
+					guess = t.predict(features)
+					updates++
+					if guess != word.Tag {
+						for _, feature := range features {
+							update(feature, word.Tag, 1)
+							update(feature, guess, -1)
+						}
+					}
+				}
+				beforePrevious, previous = previous, guess
+			}
+		}
+	}
+
+	for feature, weights := range t.weights {
+		for tag, weight := range weights {
+			total := totals[feature][tag] + float64(updates-stamps[feature][tag])*weight
+			averaged := total / float64(max(updates, 1))
+			if math.Abs(averaged) < perceptronMinWeight {
+				delete(weights, tag)
+				continue
+			}
+			weights[tag] = averaged
+		}
+		if len(weights) == 0 {
+			delete(t.weights, feature)
+		}
+	}
+	return t
+}
+
+func buildTagDictionary(sentences [][]TaggedWord) map[string]string {
+	counts := make(map[string]map[string]int)
+	for _, sentence := range sentences {
+		for _, word := range sentence {
+			normalized := normalizeTaggedWord(word.Word)
+			if counts[normalized] == nil {
+				counts[normalized] = make(map[string]int)
+			}
+			counts[normalized][word.Tag]++
+		}
+	}
+
+	dictionary := make(map[string]string)
+	for word, tags := range counts {
+		total, best, bestCount := 0, "", 0
+		for tag, count := range tags {
+			total += count
+			if count > bestCount || count == bestCount && tag < best {
+				best, bestCount = tag, count
+			}
+		}
+		if total >= tagDictionaryMinCount && float64(bestCount)/float64(total) >= tagDictionaryMinShare {
+			dictionary[word] = best
+		}
+	}
+	return dictionary
+}
+
+// Save writes the tagger as a gzip-compressed text model: its tags, its
+// dictionary of unambiguous words and the weights of its features, one per
+// line with tab-separated fields.
+func (t *PerceptronTagger) Save(w io.Writer) error {
+	compressed := gzip.NewWriter(w)
+	out := bufio.NewWriter(compressed)
+
+	fmt.Fprintf(out, "tags\t%s\n", strings.Join(t.tags, "\t"))
+
+	words := make([]string, 0, len(t.tagDictionary))
+	for word := range t.tagDictionary {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	for _, word := range words {
+		fmt.Fprintf(out, "word\t%s\t%s\n", word, t.tagDictionary[word])
+	}
+
+	features := make([]string, 0, len(t.weights))
+	for feature := range t.weights {
+		features = append(features, feature)
+	}
+	sort.Strings(features)
+	for _, feature := range features {
+		fmt.Fprintf(out, "feature\t%s", feature)
+		for _, tag := range t.tags {
+			if weight, ok := t.weights[feature][tag]; ok {
+				fmt.Fprintf(out, "\t%s\t%s", tag, strconv.FormatFloat(weight, 'g', 4, 64))
+			}
+		}
+		out.WriteByte('\n')
+	}
+
+	if err := out.Flush(); err != nil {
+		return err
+	}
+	return compressed.Close()
+}
+
+// LoadPerceptronTagger reads a tagger written by Save.
+func LoadPerceptronTagger(r io.Reader) (*PerceptronTagger, error) {
+	decompressed, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer decompressed.Close()
+
+	t := &PerceptronTagger{
+		weights:       make(map[string]map[string]float64),
+		tagDictionary: make(map[string]string),
+	}
+	scanner := bufio.NewScanner(decompressed)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Split(scanner.Text(), "\t")
+		switch {
+		case fields[0] == "tags":
+			t.tags = fields[1:]
+		case fields[0] == "word" && len(fields) == 3:
+			t.tagDictionary[fields[1]] = fields[2]
+		case fields[0] == "feature" && len(fields)%2 == 0:
+			weights := make(map[string]float64, (len(fields)-2)/2)
+			for i := 2; i < len(fields); i += 2 {
+				weight, err := strconv.ParseFloat(fields[i+1], 64)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid weight %q", line, fields[i+1])
+				}
+				weights[fields[i]] = weight
+			}
+			t.weights[fields[1]] = weights
+		default:
+			return nil, fmt.Errorf("line %d: unexpected %q entry", line, fields[0])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(t.tags) == 0 {
+		return nil, fmt.Errorf("model has no tags")
+	}
+	return t, nil
+}
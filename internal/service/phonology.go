@@ -0,0 +1,202 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+type letterClass int
+
+const (
+	letterNone letterClass = iota
+	letterVowel
+	letterConsonant
+	letterOther
+)
+
+type yRule int
+
+const (
+	yConsonant yRule = iota
+	yVowel
+	yPositional
+)
+
+const (
+	cyrillicVowels = "аеёиоуыэюяіїє"
+	greekVowels    = "αεηιουω"
+	// Cyrillic letters that decompose to a vowel but are pronounced as consonants.
+	nonSyllabicLetters = "йў"
+)
+
+// phonology describes how letters of the Latin, Cyrillic and Greek scripts are
+// classified for a language. Vowels are matched against the lowercase letter
+// and against its base letter after canonical decomposition, so "é" and "ü"
+// count as vowels whenever "e" and "u" do.
+type phonology struct {
+	language string
+	vowels   string
+	neither  string
+	y        yRule
+}
+
+var defaultPhonology = &phonology{
+	vowels:  "aeiouæœøı" + cyrillicVowels + greekVowels,
+	neither: "ъь",
+	y:       yPositional,
+}
+
+var phonologies = map[string]*phonology{
+	"en": {language: "en", vowels: "aeiouæœ" + cyrillicVowels + greekVowels, neither: "ъь", y: yPositional},
+	"fr": {language: "fr", vowels: "aeiouæœ" + cyrillicVowels + greekVowels, neither: "ъь", y: yVowel},
+	"de": {language: "de", vowels: "aeiou" + cyrillicVowels + greekVowels, neither: "ъь", y: yVowel},
+	"es": {language: "es", vowels: "aeiou" + cyrillicVowels + greekVowels, neither: "ъь", y: yPositional},
+	"it": {language: "it", vowels: "aeiou" + cyrillicVowels + greekVowels, neither: "ъь", y: yVowel},
+	"pt": {language: "pt", vowels: "aeiou" + cyrillicVowels + greekVowels, neither: "ъь", y: yVowel},
+	"nl": {language: "nl", vowels: "aeiou" + cyrillicVowels + greekVowels, neither: "ъь", y: yVowel},
+	"pl": {language: "pl", vowels: "aeiou" + cyrillicVowels + greekVowels, neither: "ъь", y: yVowel},
+	"cs": {language: "cs", vowels: "aeiou" + cyrillicVowels + greekVowels, neither: "ъь", y: yVowel},
+	"sk": {language: "sk", vowels: "aeiouä" + cyrillicVowels + greekVowels, neither: "ъь", y: yVowel},
+	"ro": {language: "ro", vowels: "aeiou" + cyrillicVowels + greekVowels, neither: "ъь", y: yVowel},
+	"hu": {language: "hu", vowels: "aeiou" + cyrillicVowels + greekVowels, neither: "ъь", y: yConsonant},
+	"tr": {language: "tr", vowels: "aeiouı" + cyrillicVowels + greekVowels, neither: "ъь", y: yConsonant},
+	"sv": {language: "sv", vowels: "aeiou" + cyrillicVowels + greekVowels, neither: "ъь", y: yVowel},
+	"fi": {language: "fi", vowels: "aeiou" + cyrillicVowels + greekVowels, neither: "ъь", y: yVowel},
+	"da": {language: "da", vowels: "aeiouæø" + cyrillicVowels + greekVowels, neither: "ъь", y: yVowel},
+	"no": {language: "no", vowels: "aeiouæø" + cyrillicVowels + greekVowels, neither: "ъь", y: yVowel},
+	"ru": {language: "ru", vowels: "aeiou" + "аеёиоуыэюя" + greekVowels, neither: "ъь", y: yPositional},
+	"uk": {language: "uk", vowels: "aeiou" + "аеєиіїоуюя" + greekVowels, neither: "ь", y: yPositional},
+	"bg": {language: "bg", vowels: "aeiou" + "аеиоуъюя" + greekVowels, neither: "ь", y: yPositional},
+	"el": {language: "el", vowels: "aeiou" + cyrillicVowels + greekVowels, neither: "ъь", y: yPositional},
+}
+
+// lookupPhonology resolves a language tag such as "fr" or "pt-BR" to its
+// phonology table. An empty tag selects the multilingual default table.
+func lookupPhonology(language string) (*phonology, bool) {
+	tag := primaryLanguage(language)
+	if tag == "" {
+		return defaultPhonology, true
+	}
+
+	p, ok := phonologies[tag]
+	return p, ok
+}
+
+// supportedLanguages lists the languages with a phonology table or a detection
+// profile. The latter are analyzed with the default table.
+func supportedLanguages() []string {
+	languages := make([]string, 0, len(languageNames))
+	for language := range languageNames {
+		languages = append(languages, language)
+	}
+	for language := range phonologies {
+		if !isKnownLanguage(language) {
+			languages = append(languages, language)
+		}
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+func primaryLanguage(language string) string {
+	tag := strings.ToLower(strings.TrimSpace(language))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+func (p *phonology) classify(r, prev, next rune) letterClass {
+	if !unicode.IsLetter(r) {
+		return letterNone
+	}
+	if !unicode.In(r, unicode.Latin, unicode.Cyrillic, unicode.Greek) {
+		return letterOther
+	}
+
+	lower := unicode.ToLower(r)
+	if strings.ContainsRune(p.neither, lower) {
+		return letterOther
+	}
+	if strings.ContainsRune(nonSyllabicLetters, lower) {
+		return letterConsonant
+	}
+
+	base := baseLetter(lower)
+	if base == 'y' {
+		return p.classifyY(prev, next)
+	}
+	if strings.ContainsRune(p.vowels, lower) || strings.ContainsRune(p.vowels, base) {
+		return letterVowel
+	}
+
+	return letterConsonant
+}
+
+// classifyY applies the positional rule for "y": it is a consonant when it
+// starts a word and is followed by a vowel ("yes", "yellow") and a vowel
+// everywhere else ("my", "rhythm", "Yvonne").
+func (p *phonology) classifyY(prev, next rune) letterClass {
+	switch p.y {
+	case yVowel:
+		return letterVowel
+	case yConsonant:
+		return letterConsonant
+	case yPositional:
+		if !unicode.IsLetter(prev) && p.classify(next, 0, 0) == letterVowel {
+			return letterConsonant
+		}
+		return letterVowel
+	}
+
+	return letterConsonant
+}
+
+func baseLetter(r rune) rune {
+	if r < utf8.RuneSelf {
+		return r
+	}
+
+	decomposed := norm.NFD.String(string(r))
+	base, _ := utf8.DecodeRuneInString(decomposed)
+	return base
+}
+
+type letterCounts struct {
+	vowels     int
+	consonants int
+	other      int
+}
+
+// countLetters normalizes text to NFC so that precomposed and decomposed input
+// produce identical counts, then classifies every letter.
+func (p *phonology) countLetters(text string) letterCounts {
+	runes := []rune(norm.NFC.String(text))
+
+	var counts letterCounts
+	for i, r := range runes {
+		var prev, next rune
+		if i > 0 {
+			prev = runes[i-1]
+		}
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		switch p.classify(r, prev, next) {
+		case letterVowel:
+			counts.vowels++
+		case letterConsonant:
+			counts.consonants++
+		case letterOther:
+			counts.other++
+		case letterNone:
+		}
+	}
+
+	return counts
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"os"
+	"sync"
+
+	"vm-chan/internal/domain"
+)
+
+//go:embed data/pos/en.model.gz
+var posFiles embed.FS
+
+var (
+	posTaggerOnce sync.Once
+	posTagger     *PerceptronTagger
+)
+
+// DefaultPOSTagger returns the embedded English tagger, trained with
+// cmd/postrain on the corpus written by cmd/posgen. The model is built into
+// the binary, so failing to read it is a programming error and panics.
+func DefaultPOSTagger() *PerceptronTagger {
+	posTaggerOnce.Do(func() {
+		file, err := posFiles.Open("data/pos/en.model.gz")
+		if err != nil {
+			panic(fmt.Sprintf("embedded part-of-speech model: %v", err))
+		}
+		tagger, err := LoadPerceptronTagger(file)
+		_ = file.Close()
+		if err != nil {
+			panic(fmt.Sprintf("embedded part-of-speech model: %v", err))
+		}
+		posTagger = tagger
+	})
+	return posTagger
+}
+
+// LoadPOSTagger reads an English tagger model trained with cmd/postrain that
+// replaces the embedded one.
+func LoadPOSTagger(path string) (*PerceptronTagger, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open part-of-speech model: %w", err)
+	}
+
+	tagger, err := LoadPerceptronTagger(file)
+	_ = file.Close()
+	if err != nil {
+		return nil, fmt.Errorf("part-of-speech model %s: %w", path, err)
+	}
+	return tagger, nil
+}
+
+type posAnalyzer struct {
+	tagger *PerceptronTagger
+}
+
+// NewPOSAnalyzer returns an analyzer tagging English words with their
+// Universal Dependencies part of speech, using the embedded model when tagger
+// is nil.
+func NewPOSAnalyzer(tagger *PerceptronTagger) Analyzer {
+	if tagger == nil {
+		tagger = DefaultPOSTagger()
+	}
+	return &posAnalyzer{tagger: tagger}
+}
+
+func (a *posAnalyzer) Name() string {
+	return "pos"
+}
+
+func (a *posAnalyzer) Version() string {
+	return "1.0.0"
+}
+
+func (a *posAnalyzer) Description() string {
+	return "Universal Dependencies part-of-speech tag of every word, counts by category, " +
+		"noun/verb ratio and lexical density (English only)"
+}
+
+func (a *posAnalyzer) Analyze(ctx context.Context, doc *Document) (interface{}, error) {
+	if doc.Language != "en" {
+		return nil, fmt.Errorf("%w: part-of-speech tagging supports en, got %q", domain.ErrInvalidInput, doc.Language)
+	}
+
+	result := &domain.POSResult{
+		Tokens:    make([]domain.TaggedToken, 0, len(doc.Tokens())),
+		TagCounts: map[string]int{},
+	}
+	for _, sentence := range doc.sentences() {
+		tokens := doc.tokensIn(sentence.start, sentence.end)
+		words := make([]string, len(tokens))
+		for i, token := range tokens {
+			words[i] = token.Text
+		}
+		for i, tag := range a.tagger.Tag(words) {
+			// Numbers are told apart by the tokenizer more reliably than
+			// by the model.
+			if tokens[i].Kind == TokenNumber {
+				tag = "NUM"
+			}
+			result.Tokens = append(result.Tokens, domain.TaggedToken{
+				Text:  tokens[i].Text,
+				Tag:   tag,
+				Start: tokens[i].Start,
+				End:   tokens[i].End,
+			})
+			result.TagCounts[tag]++
+		}
+	}
+
+	counts := result.TagCounts
+	result.Nouns = counts["NOUN"] + counts["PROPN"]
+	result.Verbs = counts["VERB"]
+	result.Adjectives = counts["ADJ"]
+	result.Adverbs = counts["ADV"]
+	if result.Verbs > 0 {
+		result.NounVerbRatio = round4(float64(result.Nouns) / float64(result.Verbs))
+	}
+	if len(result.Tokens) > 0 {
+		content := result.Nouns + result.Verbs + result.Adjectives + result.Adverbs
+		result.LexicalDensity = round4(float64(content) / float64(len(result.Tokens)))
+	}
+
+	return result, nil
+}
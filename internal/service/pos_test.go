@@ -0,0 +1,107 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"vm-chan/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPOSAnalyzer(t *testing.T) {
+	analyzer := NewPOSAnalyzer(nil)
+
+	t.Run("Tags", func(t *testing.T) {
+		result := analyzeDoc[*domain.POSResult](t, analyzer, "The cat sat on the mat. She doesn’t like 3 cold days!", "en")
+
+		tags := make([]string, len(result.Tokens))
+		for i, token := range result.Tokens {
+			tags[i] = token.Text + "/" + token.Tag
+		}
+		assert.Equal(t, []string{
+			"The/DET", "cat/NOUN", "sat/VERB", "on/ADP", "the/DET", "mat/NOUN",
+			"She/PRON", "doesn’t/AUX", "like/VERB", "3/NUM", "cold/ADJ", "days/NOUN",
+		}, tags)
+		assert.Equal(t, domain.TaggedToken{Text: "cat", Tag: "NOUN", Start: 4, End: 7}, result.Tokens[1])
+	})
+
+	t.Run("Counts", func(t *testing.T) {
+		result := analyzeDoc[*domain.POSResult](t, analyzer, "The old man quickly wrote two long letters.", "en")
+
+		assert.Equal(t, map[string]int{"DET": 1, "ADJ": 2, "NOUN": 2, "ADV": 1, "VERB": 1, "NUM": 1}, result.TagCounts)
+		assert.Equal(t, 2, result.Nouns)
+		assert.Equal(t, 1, result.Verbs)
+		assert.Equal(t, 2, result.Adjectives)
+		assert.Equal(t, 1, result.Adverbs)
+		assert.Equal(t, 2.0, result.NounVerbRatio)
+		assert.Equal(t, 0.75, result.LexicalDensity)
+	})
+
+	t.Run("Held-out accuracy", func(t *testing.T) {
+		data, err := os.ReadFile("testdata/pos/en.txt")
+		require.NoError(t, err)
+
+		correct, total := 0, 0
+		for _, line := range strings.Split(string(data), "\n") {
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			var words, tags []string
+			for _, field := range strings.Fields(line) {
+				separator := strings.LastIndex(field, "/")
+				words = append(words, field[:separator])
+				tags = append(tags, field[separator+1:])
+			}
+			result := analyzeDoc[*domain.POSResult](t, analyzer, strings.Join(words, " ")+".", "en")
+			require.Len(t, result.Tokens, len(words), line)
+			for i, token := range result.Tokens {
+				if token.Tag == tags[i] {
+					correct++
+				}
+				total++
+			}
+		}
+		// The embedded model tags 95.6% of these words right; see
+		// data/pos/README.md.
+		assert.GreaterOrEqual(t, float64(correct)/float64(total), 0.94)
+	})
+
+	t.Run("Unsupported language", func(t *testing.T) {
+		_, err := analyzer.Analyze(context.Background(), testDocument("Le chat dort.", "fr"))
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+}
+
+func TestPerceptronTagger(t *testing.T) {
+	var sentences [][]TaggedWord
+	for _, line := range []string{
+		"the/DET dog/NOUN runs/VERB",
+		"a/DET cat/NOUN sleeps/VERB",
+		"the/DET bird/NOUN sings/VERB",
+		"a/DET fish/NOUN swims/VERB",
+	} {
+		var sentence []TaggedWord
+		for _, field := range strings.Fields(line) {
+			word, tag, _ := strings.Cut(field, "/")
+			sentence = append(sentence, TaggedWord{Word: word, Tag: tag})
+		}
+		sentences = append(sentences, sentence)
+	}
+
+	tagger := TrainPerceptronTagger(sentences, 5, 1)
+	assert.Equal(t, []string{"DET", "NOUN", "VERB"}, tagger.Tag([]string{"A", "horse", "jumps"}))
+
+	var saved bytes.Buffer
+	require.NoError(t, tagger.Save(&saved))
+	loaded, err := LoadPerceptronTagger(&saved)
+	require.NoError(t, err)
+	assert.Equal(t, tagger.Tag([]string{"the", "cow", "eats"}), loaded.Tag([]string{"the", "cow", "eats"}))
+
+	_, err = LoadPerceptronTagger(strings.NewReader("not gzip"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"math"
+	"strings"
+	"unicode"
+
+	"vm-chan/internal/domain"
+)
+
+// localizedEase is a Flesch reading ease formula re-fitted for a language:
+// base - sentenceWeight*ASL - syllableWeight*ASW, where ASL is the average
+// sentence length in words and ASW the average number of syllables per word.
+type localizedEase struct {
+	name           string
+	base           float64
+	sentenceWeight float64
+	syllableWeight float64
+}
+
+var localizedEaseFormulas = map[string]localizedEase{
+	"de": {name: "flesch_amstad", base: 180, sentenceWeight: 1, syllableWeight: 58.5},
+	"es": {name: "fernandez_huerta", base: 206.84, sentenceWeight: 1.02, syllableWeight: 60},
+	"fr": {name: "kandel_moles", base: 207, sentenceWeight: 1.015, syllableWeight: 73.6},
+	"it": {name: "flesch_vacca", base: 206, sentenceWeight: 1, syllableWeight: 65},
+	"nl": {name: "douma", base: 206.835, sentenceWeight: 0.93, syllableWeight: 77},
+	"pt": {name: "flesch_martins", base: 248.835, sentenceWeight: 1.015, syllableWeight: 84.6},
+	"ru": {name: "flesch_oborneva", base: 206.836, sentenceWeight: 1.52, syllableWeight: 65.14},
+}
+
+type readabilityAnalyzer struct{}
+
+func NewReadabilityAnalyzer() Analyzer {
+	return &readabilityAnalyzer{}
+}
+
+func (a *readabilityAnalyzer) Name() string {
+	return "readability"
+}
+
+func (a *readabilityAnalyzer) Version() string {
+	return "1.0.0"
+}
+
+func (a *readabilityAnalyzer) Description() string {
+	return "Flesch reading ease, Flesch-Kincaid, Gunning Fog, SMOG, Coleman-Liau and ARI scores " +
+		"with US grade levels, plus language-specific reading ease formulas"
+}
+
+func (a *readabilityAnalyzer) Analyze(ctx context.Context, doc *Document) (interface{}, error) {
+	stats := readabilityStatsFor(doc)
+
+	result := &domain.ReadabilityResult{
+		Sentences:         stats.sentences,
+		Words:             stats.words,
+		Syllables:         stats.syllables,
+		Characters:        stats.characters,
+		ComplexWords:      stats.complexWords,
+		PolysyllableWords: stats.polysyllables,
+		Scores:            []domain.ReadabilityScore{},
+	}
+	if stats.words == 0 {
+		return result, nil
+	}
+
+	words := float64(stats.words)
+	sentences := float64(stats.sentences)
+	asl := words / sentences
+	asw := float64(stats.syllables) / words
+
+	ease := 206.835 - 1.015*asl - 84.6*asw
+	fleschKincaid := 0.39*asl + 11.8*asw - 15.59
+	gunningFog := 0.4 * (asl + 100*float64(stats.complexWords)/words)
+	smog := 1.0430*math.Sqrt(float64(stats.polysyllables)*30/sentences) + 3.1291
+	colemanLiau := 0.0588*(100*float64(stats.characters)/words) - 0.296*(100*sentences/words) - 15.8
+	ari := 4.71*float64(stats.characters)/words + 0.5*asl - 21.43
+
+	result.Scores = append(result.Scores,
+		readabilityScore("flesch_reading_ease", ease, easeGradeLevel(ease), ""),
+		readabilityScore("flesch_kincaid_grade", fleschKincaid, fleschKincaid, ""),
+		readabilityScore("gunning_fog", gunningFog, gunningFog, ""),
+		readabilityScore("smog", smog, smog, ""),
+		readabilityScore("coleman_liau", colemanLiau, colemanLiau, ""),
+		readabilityScore("automated_readability_index", ari, math.Ceil(ari), ""),
+	)
+
+	if formula, ok := localizedEaseFormulas[doc.Language]; ok {
+		score := formula.base - formula.sentenceWeight*asl - formula.syllableWeight*asw
+		result.Scores = append(result.Scores,
+			readabilityScore(formula.name, score, easeGradeLevel(score), doc.Language))
+	}
+
+	gradeLevels := []float64{fleschKincaid, gunningFog, smog, colemanLiau, math.Ceil(ari)}
+	result.GradeLevel = round2(clampGrade(mean(gradeLevels)))
+
+	return result, nil
+}
+
+type readabilityStats struct {
+	sentences     int
+	words         int
+	syllables     int
+	characters    int
+	complexWords  int
+	polysyllables int
+}
+
+func readabilityStatsFor(doc *Document) readabilityStats {
+	var stats readabilityStats
+
+	for _, sentence := range doc.sentences() {
+		sentenceWords := 0
+		for i, token := range doc.tokensIn(sentence.start, sentence.end) {
+			word := token.Text
+			sentenceWords++
+
+			for _, r := range word {
+				if unicode.IsLetter(r) || unicode.IsDigit(r) {
+					stats.characters++
+				}
+			}
+
+			syllables := countSyllables(word, doc.phonology)
+			stats.syllables += syllables
+			if syllables >= 3 {
+				stats.polysyllables++
+				if isComplexWord(word, i == 0, doc.phonology) {
+					stats.complexWords++
+				}
+			}
+		}
+
+		if sentenceWords > 0 {
+			stats.sentences++
+			stats.words += sentenceWords
+		}
+	}
+
+	return stats
+}
+
+// isComplexWord follows Gunning's definition: three or more syllables, not a
+// proper noun, and not reaching three syllables only through an inflectional
+// suffix.
+func isComplexWord(word string, sentenceInitial bool, p *phonology) bool {
+	if !sentenceInitial && unicode.IsUpper([]rune(word)[0]) {
+		return false
+	}
+
+	if p.language == "en" {
+		lower := strings.ToLower(word)
+		for _, suffix := range []string{"es", "ed", "ing"} {
+			if strings.HasSuffix(lower, suffix) && countSyllables(strings.TrimSuffix(lower, suffix), p) < 3 {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// easeGradeLevel maps a reading ease score to the US school grade of Flesch's
+// original interpretation table.
+func easeGradeLevel(score float64) float64 {
+	switch {
+	case score >= 90:
+		return 5
+	case score >= 80:
+		return 6
+	case score >= 70:
+		return 7
+	case score >= 60:
+		return 8.5
+	case score >= 50:
+		return 11
+	case score >= 30:
+		return 14
+	default:
+		return 17
+	}
+}
+
+func readabilityScore(name string, score, gradeLevel float64, language string) domain.ReadabilityScore {
+	return domain.ReadabilityScore{
+		Name:       name,
+		Score:      round2(score),
+		GradeLevel: round2(clampGrade(gradeLevel)),
+		Language:   language,
+	}
+}
+
+func clampGrade(grade float64) float64 {
+	return math.Max(0, grade)
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
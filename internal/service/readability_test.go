@@ -0,0 +1,106 @@
+package service
+
+import (
+	"testing"
+
+	"vm-chan/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountSyllables(t *testing.T) {
+	tests := []struct {
+		word     string
+		language string
+		expected int
+	}{
+		{word: "the", language: "en", expected: 1},
+		{word: "cake", language: "en", expected: 1},
+		{word: "table", language: "en", expected: 2},
+		{word: "wanted", language: "en", expected: 2},
+		{word: "beautiful", language: "en", expected: 3},
+		{word: "readability", language: "en", expected: 5},
+		{word: "ciudad", language: "es", expected: 2},
+		{word: "Schule", language: "de", expected: 2},
+		{word: "grande", language: "fr", expected: 1},
+		{word: "молоко", language: "ru", expected: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			p, ok := lookupPhonology(tt.language)
+			require.True(t, ok)
+
+			assert.Equal(t, tt.expected, countSyllables(tt.word, p))
+		})
+	}
+}
+
+func TestReadabilityAnalyzer(t *testing.T) {
+	analyzer := NewReadabilityAnalyzer()
+
+	analyze := func(t *testing.T, text, language string) *domain.ReadabilityResult {
+		_, ok := lookupPhonology(language)
+		require.True(t, ok)
+		return analyzeDoc[*domain.ReadabilityResult](t, analyzer, text, language)
+	}
+
+	scores := func(result *domain.ReadabilityResult) map[string]domain.ReadabilityScore {
+		byName := make(map[string]domain.ReadabilityScore, len(result.Scores))
+		for _, score := range result.Scores {
+			byName[score.Name] = score
+		}
+		return byName
+	}
+
+	t.Run("Simple English text", func(t *testing.T) {
+		result := analyze(t, "The cat sat on the mat. The dog ran!", "en")
+
+		assert.Equal(t, 2, result.Sentences)
+		assert.Equal(t, 9, result.Words)
+		assert.Equal(t, 9, result.Syllables)
+		assert.Equal(t, 0, result.ComplexWords)
+
+		byName := scores(result)
+		assert.InDelta(t, 117.67, byName["flesch_reading_ease"].Score, 0.01)
+		assert.Equal(t, 5.0, byName["flesch_reading_ease"].GradeLevel)
+		assert.InDelta(t, -2.035, byName["flesch_kincaid_grade"].Score, 0.01)
+		assert.Equal(t, 0.0, byName["flesch_kincaid_grade"].GradeLevel)
+		assert.Contains(t, byName, "gunning_fog")
+		assert.Contains(t, byName, "smog")
+		assert.Contains(t, byName, "coleman_liau")
+		assert.Contains(t, byName, "automated_readability_index")
+		assert.NotContains(t, byName, "flesch_amstad")
+	})
+
+	t.Run("Complex words raise the grade level", func(t *testing.T) {
+		simple := analyze(t, "We like the sun. It is warm.", "en")
+		complexText := analyze(t, "Institutional considerations necessitate comprehensive evaluation.", "en")
+
+		assert.Equal(t, 5, complexText.ComplexWords)
+		assert.Greater(t, complexText.GradeLevel, simple.GradeLevel)
+	})
+
+	t.Run("German uses Flesch-Amstad", func(t *testing.T) {
+		result := analyze(t, "Der Hund läuft schnell nach Hause.", "de")
+
+		score, ok := scores(result)["flesch_amstad"]
+		require.True(t, ok)
+		assert.Equal(t, "de", score.Language)
+		assert.InDelta(t, 180-6-58.5*7.0/6.0, score.Score, 0.01)
+	})
+
+	t.Run("Spanish uses Fernandez Huerta", func(t *testing.T) {
+		result := analyze(t, "La casa es muy bonita.", "es")
+
+		assert.Contains(t, scores(result), "fernandez_huerta")
+	})
+
+	t.Run("Empty text has no scores", func(t *testing.T) {
+		result := analyze(t, "", "en")
+
+		assert.Equal(t, 0, result.Words)
+		assert.Empty(t, result.Scores)
+	})
+}
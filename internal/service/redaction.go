@@ -0,0 +1,399 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"vm-chan/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	piiCreditCard = "credit_card"
+	piiIBAN       = "iban"
+	piiNationalID = "national_id"
+	piiName       = "name"
+)
+
+const (
+	strategyMask      = "mask"
+	strategyPartial   = "partial"
+	strategyType      = "type"
+	strategyPseudonym = "pseudonym"
+)
+
+const (
+	redactionMask     = "********"
+	partialKeptRunes  = 4
+	pseudonymHexChars = 8
+)
+
+const dniLetters = "TRWAGMYFPDXBNJZSQVHLCKE"
+
+// piiPatterns are in order of precedence, like entityPatterns whose email, IP
+// address and phone patterns they reuse.
+var piiPatterns = append([]entityPattern{
+	{
+		kind:      piiCreditCard,
+		pattern:   regexp.MustCompile(`\b[2-6](?:[ \-]?\d){12,18}\b`),
+		normalize: normalizeCreditCard,
+	},
+	{
+		kind:      piiIBAN,
+		pattern:   regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,3})?\b`),
+		normalize: normalizeIBAN,
+	},
+	{
+		kind:      piiNationalID,
+		pattern:   regexp.MustCompile(`\b(\d{3})-(\d{2})-(\d{4})\b`),
+		normalize: normalizeSSN,
+	},
+	{
+		kind: piiNationalID,
+		pattern: regexp.MustCompile(
+			`\b([A-CEGHJ-PR-TW-Z][A-CEGHJ-NPR-TW-Z]) ?(\d{2}) ?(\d{2}) ?(\d{2}) ?([A-D])\b`),
+		normalize: normalizeNINO,
+	},
+	{
+		kind:      piiNationalID,
+		pattern:   regexp.MustCompile(`\b([XYZ]?)(\d{7,8})-?([A-Z])\b`),
+		normalize: normalizeDNI,
+	},
+}, entityPatternsOf(entityEmail, entityIPAddress, entityPhone)...)
+
+func entityPatternsOf(kinds ...string) []entityPattern {
+	var patterns []entityPattern
+	for _, kind := range kinds {
+		for _, p := range entityPatterns {
+			if p.kind == kind {
+				patterns = append(patterns, p)
+			}
+		}
+	}
+	return patterns
+}
+
+type redactionService struct {
+	tokenizer Tokenizer
+	names     map[string][][]string
+	secret    []byte
+	logger    *zap.Logger
+}
+
+// NewRedactionService returns a service redacting personal data. names lists
+// the person names to redact besides the detected formats, and secret keys
+// the pseudonyms; without a secret the pseudonym strategy is unavailable.
+func NewRedactionService(tokenizer Tokenizer, names []string, secret string, logger *zap.Logger) domain.RedactionService {
+	s := &redactionService{
+		tokenizer: tokenizer,
+		names:     make(map[string][][]string),
+		secret:    []byte(secret),
+		logger:    logger,
+	}
+
+	for _, name := range names {
+		var terms []string
+		for _, token := range tokenizer.Tokenize(name) {
+			terms = append(terms, foldTerm(token.Text))
+		}
+		if len(terms) > 0 {
+			s.names[terms[0]] = append(s.names[terms[0]], terms)
+		}
+	}
+	for first := range s.names {
+		sort.SliceStable(s.names[first], func(i, j int) bool {
+			return len(s.names[first][i]) > len(s.names[first][j])
+		})
+	}
+
+	return s
+}
+
+func (s *redactionService) Redact(ctx context.Context, req *domain.RedactionRequest) (*domain.RedactionResponse, error) {
+	strategy := req.Strategy
+	switch strategy {
+	case "":
+		strategy = strategyType
+	case strategyMask, strategyPartial, strategyType:
+	case strategyPseudonym:
+		if len(s.secret) == 0 {
+			return nil, fmt.Errorf("%w: the pseudonym strategy is not configured", domain.ErrInvalidInput)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown strategy %q, expected %s, %s, %s or %s", domain.ErrInvalidInput,
+			strategy, strategyMask, strategyPartial, strategyType, strategyPseudonym)
+	}
+
+	types := make(map[string]bool)
+	for _, kind := range req.Types {
+		if !isPIIType(kind) {
+			return nil, fmt.Errorf("%w: unknown personal data type %q", domain.ErrInvalidInput, kind)
+		}
+		types[kind] = true
+	}
+
+	language := primaryLanguage(req.Language)
+	if language == "" {
+		if detection := detectLanguage(req.Text); detection.Confidence >= minDetectionConfidence {
+			language = detection.Language
+		}
+	}
+
+	matches := append(matchEntityPatterns(req.Text, language, piiPatterns), s.matchNames(req.Text)...)
+	matches = resolveEntityMatches(req.Text, matches)
+
+	response := &domain.RedactionResponse{Redactions: []domain.Redaction{}}
+	var text strings.Builder
+	end := 0
+	for _, match := range matches {
+		if len(types) > 0 && !types[match.entity.Type] {
+			continue
+		}
+
+		replacement := s.replacement(match.entity, strategy)
+		text.WriteString(req.Text[end:match.start])
+		text.WriteString(replacement)
+		end = match.end
+
+		response.Redactions = append(response.Redactions, domain.Redaction{
+			Type:        match.entity.Type,
+			Start:       match.entity.Start,
+			End:         match.entity.End,
+			Replacement: replacement,
+		})
+	}
+	text.WriteString(req.Text[end:])
+	response.Text = text.String()
+
+	s.logger.Info("Redaction completed",
+		zap.String("strategy", strategy),
+		zap.Int("redactions", len(response.Redactions)),
+	)
+
+	return response, nil
+}
+
+func isPIIType(kind string) bool {
+	if kind == piiName {
+		return true
+	}
+	for _, p := range piiPatterns {
+		if p.kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// matchNames finds the configured names as runs of words separated only by
+// spaces, preferring the longest name starting at a word.
+func (s *redactionService) matchNames(text string) []entityMatch {
+	if len(s.names) == 0 {
+		return nil
+	}
+
+	runes := []rune(text)
+	tokens := s.tokenizer.Tokenize(text)
+	var matches []entityMatch
+	for i := 0; i < len(tokens); i++ {
+		for _, name := range s.names[foldTerm(tokens[i].Text)] {
+			if !matchesName(runes, tokens[i:], name) {
+				continue
+			}
+
+			last := tokens[i+len(name)-1]
+			start := len(string(runes[:tokens[i].Start]))
+			end := start + len(string(runes[tokens[i].Start:last.End]))
+			matches = append(matches, entityMatch{
+				entity: domain.Entity{
+					Type:       piiName,
+					Text:       text[start:end],
+					Normalized: strings.Join(name, " "),
+				},
+				start:      start,
+				end:        end,
+				precedence: len(piiPatterns),
+			})
+			i += len(name) - 1
+			break
+		}
+	}
+	return matches
+}
+
+func matchesName(runes []rune, tokens []Token, name []string) bool {
+	if len(tokens) < len(name) {
+		return false
+	}
+	for j, term := range name {
+		if foldTerm(tokens[j].Text) != term {
+			return false
+		}
+		if j > 0 && !onlySpaces(runes[tokens[j-1].End:tokens[j].Start]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *redactionService) replacement(entity domain.Entity, strategy string) string {
+	token := strings.ToUpper(entity.Type)
+	switch strategy {
+	case strategyMask:
+		return redactionMask
+	case strategyPartial:
+		return partialMask(entity)
+	case strategyPseudonym:
+		mac := hmac.New(sha256.New, s.secret)
+		mac.Write([]byte(entity.Type + "\x00" + entity.Normalized))
+		return "[" + token + "_" + hex.EncodeToString(mac.Sum(nil))[:pseudonymHexChars] + "]"
+	}
+	return "[" + token + "]"
+}
+
+// partialMask keeps the first letter of every word of a name, the first
+// character and the domain of an email, and the last four letters or digits
+// of anything else. Separators are kept.
+func partialMask(entity domain.Entity) string {
+	runes := []rune(entity.Text)
+	var keep func(i int) bool
+	switch entity.Type {
+	case piiName:
+		keep = func(i int) bool { return i == 0 || !unicode.IsLetter(runes[i-1]) }
+	case entityEmail:
+		at := strings.LastIndex(entity.Text, "@")
+		at = utf8.RuneCountInString(entity.Text[:at])
+		keep = func(i int) bool { return i == 0 || i >= at }
+	default:
+		kept := 0
+		from := len(runes)
+		for from > 0 && kept < partialKeptRunes {
+			from--
+			if unicode.IsLetter(runes[from]) || unicode.IsDigit(runes[from]) {
+				kept++
+			}
+		}
+		keep = func(i int) bool { return i >= from }
+	}
+
+	masked := make([]rune, len(runes))
+	for i, r := range runes {
+		if !keep(i) && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			r = '*'
+		}
+		masked[i] = r
+	}
+	return string(masked)
+}
+
+// normalizeCreditCard accepts card numbers passing the Luhn check.
+func normalizeCreditCard(match []string, language string) (string, bool) {
+	number := digitsOf(match[0])
+	sum := 0
+	for i := len(number) - 1; i >= 0; i-- {
+		digit := int(number[i] - '0')
+		if (len(number)-i)%2 == 0 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return number, sum%10 == 0
+}
+
+// normalizeIBAN accepts IBANs whose check digits are valid: moving the first
+// four characters to the end and reading letters as 10 to 35 gives a number
+// whose remainder modulo 97 is 1.
+func normalizeIBAN(match []string, language string) (string, bool) {
+	iban := strings.ReplaceAll(match[0], " ", "")
+	if len(iban) < 15 || len(iban) > 34 {
+		return "", false
+	}
+
+	var digits strings.Builder
+	for _, r := range iban[4:] + iban[:4] {
+		if r >= 'A' && r <= 'Z' {
+			fmt.Fprintf(&digits, "%d", r-'A'+10)
+		} else {
+			digits.WriteRune(r)
+		}
+	}
+	number, ok := new(big.Int).SetString(digits.String(), 10)
+	if !ok {
+		return "", false
+	}
+	return iban, new(big.Int).Mod(number, big.NewInt(97)).Int64() == 1
+}
+
+// normalizeSSN accepts US social security numbers outside the ranges that are
+// never assigned.
+func normalizeSSN(match []string, language string) (string, bool) {
+	area, group, serial := match[1], match[2], match[3]
+	if area == "000" || area == "666" || area[0] == '9' || group == "00" || serial == "0000" {
+		return "", false
+	}
+	return area + group + serial, true
+}
+
+// normalizeNINO accepts UK National Insurance numbers without the prefixes
+// that are never allocated.
+func normalizeNINO(match []string, language string) (string, bool) {
+	switch match[1] {
+	case "BG", "GB", "KN", "NK", "NT", "TN", "ZZ":
+		return "", false
+	}
+	return strings.Join(match[1:], ""), true
+}
+
+// normalizeDNI accepts Spanish DNI and NIE numbers whose control letter
+// matches.
+func normalizeDNI(match []string, language string) (string, bool) {
+	prefix, number, letter := match[1], match[2], match[3]
+	if prefix == "" && len(number) != 8 || prefix != "" && len(number) != 7 {
+		return "", false
+	}
+
+	digits := number
+	if prefix != "" {
+		digits = strconv.Itoa(strings.Index("XYZ", prefix)) + number
+	}
+	value, err := strconv.Atoi(digits)
+	return prefix + number + letter, err == nil && dniLetters[value%23] == letter[0]
+}
+
+// LoadNameList reads the names to redact from a file holding one name per
+// line. Empty lines and lines starting with # are skipped.
+func LoadNameList(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open name list: %w", err)
+	}
+	defer file.Close()
+
+	var names []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if name := strings.TrimSpace(scanner.Text()); name != "" && !strings.HasPrefix(name, "#") {
+			names = append(names, name)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("name list %s: %w", path, err)
+	}
+	return names, nil
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"vm-chan/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRedactionService(t *testing.T) {
+	service := NewRedactionService(NewTokenizer(), []string{"Jane Doe", "Jane", "José Álvarez"}, "secret", zap.NewNop())
+
+	redact := func(t *testing.T, req *domain.RedactionRequest) *domain.RedactionResponse {
+		response, err := service.Redact(context.Background(), req)
+		require.NoError(t, err)
+		return response
+	}
+
+	t.Run("Type strategy with offsets", func(t *testing.T) {
+		response := redact(t, &domain.RedactionRequest{
+			Text:     "Écrivez à ana@example.com ou appelez +33 1 42 68 53 00.",
+			Language: "fr",
+		})
+
+		assert.Equal(t, "Écrivez à [EMAIL] ou appelez [PHONE].", response.Text)
+		assert.Equal(t, []domain.Redaction{
+			{Type: "email", Start: 10, End: 25, Replacement: "[EMAIL]"},
+			{Type: "phone", Start: 37, End: 54, Replacement: "[PHONE]"},
+		}, response.Redactions)
+	})
+
+	t.Run("Detected formats", func(t *testing.T) {
+		tests := []struct {
+			text string
+			kind string
+		}{
+			{"card 4111 1111 1111 1111 expires soon", "credit_card"},
+			{"iban DE89 3704 0044 0532 0130 00 please", "iban"},
+			{"ssn 123-45-6789 on file", "national_id"},
+			{"NI number AB 12 34 56 C here", "national_id"},
+			{"DNI 12345678Z del cliente", "national_id"},
+			{"NIE X1234567L del cliente", "national_id"},
+			{"from 192.168.1.20 today", "ip_address"},
+			{"signed by Jane Doe yesterday", "name"},
+			{"signed by JOSÉ ÁLVAREZ yesterday", "name"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.text, func(t *testing.T) {
+				response := redact(t, &domain.RedactionRequest{Text: tt.text, Language: "en"})
+				require.Len(t, response.Redactions, 1)
+				assert.Equal(t, tt.kind, response.Redactions[0].Type)
+			})
+		}
+	})
+
+	t.Run("Invalid check digits are kept", func(t *testing.T) {
+		for _, text := range []string{
+			"card 4111 1111 1111 1112 expires soon",
+			"iban DE88 3704 0044 0532 0130 00 please",
+			"ssn 000-45-6789 on file",
+			"DNI 12345678A del cliente",
+		} {
+			response := redact(t, &domain.RedactionRequest{Text: text, Language: "en"})
+			assert.Empty(t, response.Redactions, text)
+			assert.Equal(t, text, response.Text)
+		}
+	})
+
+	t.Run("Longest name wins", func(t *testing.T) {
+		response := redact(t, &domain.RedactionRequest{Text: "Jane Doe met Jane.", Language: "en"})
+
+		assert.Equal(t, "[NAME] met [NAME].", response.Text)
+		assert.Equal(t, []domain.Redaction{
+			{Type: "name", Start: 0, End: 8, Replacement: "[NAME]"},
+			{Type: "name", Start: 13, End: 17, Replacement: "[NAME]"},
+		}, response.Redactions)
+	})
+
+	t.Run("Mask and partial strategies", func(t *testing.T) {
+		text := "Jane Doe, jane@example.com, 4111-1111-1111-1111"
+
+		masked := redact(t, &domain.RedactionRequest{Text: text, Language: "en", Strategy: "mask"})
+		assert.Equal(t, "********, ********, ********", masked.Text)
+
+		partial := redact(t, &domain.RedactionRequest{Text: text, Language: "en", Strategy: "partial"})
+		assert.Equal(t, "J*** D**, j***@example.com, ****-****-****-1111", partial.Text)
+	})
+
+	t.Run("Pseudonyms are consistent", func(t *testing.T) {
+		response := redact(t, &domain.RedactionRequest{
+			Text:     "ana@Example.com wrote to ana@example.com and bob@example.com",
+			Language: "en",
+			Strategy: "pseudonym",
+		})
+
+		require.Len(t, response.Redactions, 3)
+		first, second, third := response.Redactions[0], response.Redactions[1], response.Redactions[2]
+		assert.Regexp(t, `^\[EMAIL_[0-9a-f]{8}\]$`, first.Replacement)
+		assert.Equal(t, first.Replacement, second.Replacement)
+		assert.NotEqual(t, first.Replacement, third.Replacement)
+	})
+
+	t.Run("Types filter", func(t *testing.T) {
+		response := redact(t, &domain.RedactionRequest{
+			Text:     "Jane Doe, jane@example.com",
+			Language: "en",
+			Types:    []string{"email"},
+		})
+
+		assert.Equal(t, "Jane Doe, [EMAIL]", response.Text)
+	})
+
+	t.Run("Invalid requests", func(t *testing.T) {
+		unconfigured := NewRedactionService(NewTokenizer(), nil, "", zap.NewNop())
+
+		tests := []struct {
+			name    string
+			service domain.RedactionService
+			req     *domain.RedactionRequest
+		}{
+			{"unknown strategy", service, &domain.RedactionRequest{Text: "text", Strategy: "blur"}},
+			{"unknown type", service, &domain.RedactionRequest{Text: "text", Types: []string{"address"}}},
+			{"pseudonym without secret", unconfigured, &domain.RedactionRequest{Text: "text", Strategy: "pseudonym"}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := tt.service.Redact(context.Background(), tt.req)
+				assert.ErrorIs(t, err, domain.ErrInvalidInput)
+			})
+		}
+	})
+}
@@ -0,0 +1,518 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"unicode/utf8"
+
+	"vm-chan/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// bm25K1 and bm25B are the term frequency saturation and document length
+	// normalization of BM25.
+	bm25K1 = 1.2
+	bm25B  = 0.75
+
+	maxSearchQueryRunes = 1000
+	defaultSearchLimit  = 10
+	maxSearchLimit      = 100
+	snippetWords        = 30
+	snippetLeadingWords = 5
+	// minCorpusDocuments is the number of documents a user stores before
+	// their inverse document frequencies are used to weight keywords.
+	minCorpusDocuments = 10
+)
+
+// SearchIndex is an inverted index of the words of the documents of each
+// user, tokenized and case-folded like analyzed text so that searching agrees
+// with word counts. When it is backed by a file, every change is appended to
+// the file, which is compacted when the index is loaded.
+type SearchIndex struct {
+	tokenizer Tokenizer
+	mu        sync.RWMutex
+	users     map[string]*userSearchIndex
+	path      string
+}
+
+// userSearchIndex holds the words of the documents of a user in text order
+// and, for every word, the positions where each document holds it. stamps
+// holds the update time of the indexed version of each document.
+type userSearchIndex struct {
+	documents   map[string][]string
+	stamps      map[string]int64
+	postings    map[string]map[string][]int
+	totalLength int
+}
+
+// searchIndexEntry is a line of the file backing a search index: the words of
+// a document added or replacing its previous words, or its removal. Stamp is
+// the update time of the document in nanoseconds since the Unix epoch.
+type searchIndexEntry struct {
+	Op         string   `json:"op"`
+	UserID     string   `json:"user_id"`
+	DocumentID string   `json:"document_id"`
+	Stamp      int64    `json:"stamp,omitempty"`
+	Terms      []string `json:"terms,omitempty"`
+}
+
+const (
+	searchIndexAdd    = "add"
+	searchIndexRemove = "remove"
+)
+
+// NewSearchIndex returns an empty in-memory search index.
+func NewSearchIndex(tokenizer Tokenizer) *SearchIndex {
+	return &SearchIndex{
+		tokenizer: tokenizer,
+		users:     make(map[string]*userSearchIndex),
+	}
+}
+
+// LoadSearchIndex returns a search index backed by a file, reading the index
+// saved in it. The file is created if it does not exist.
+func LoadSearchIndex(path string, tokenizer Tokenizer) (*SearchIndex, error) {
+	index := NewSearchIndex(tokenizer)
+
+	file, err := os.Open(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to open search index: %w", err)
+	}
+	if err == nil {
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for line := 1; scanner.Scan(); line++ {
+			var entry searchIndexEntry
+			if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+				return nil, fmt.Errorf("search index %s: line %d: %w", path, line, err)
+			}
+			switch entry.Op {
+			case searchIndexAdd:
+				index.user(entry.UserID).add(entry.DocumentID, entry.Stamp, entry.Terms)
+			case searchIndexRemove:
+				index.user(entry.UserID).remove(entry.DocumentID)
+			default:
+				return nil, fmt.Errorf("search index %s: line %d: unknown operation %q", path, line, entry.Op)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("search index %s: %w", path, err)
+		}
+	}
+
+	if err := index.compact(path); err != nil {
+		return nil, err
+	}
+	index.path = path
+	return index, nil
+}
+
+// compact rewrites the file of the index with one entry per document.
+func (x *SearchIndex) compact(path string) error {
+	temporary := path + ".tmp"
+	file, err := os.OpenFile(temporary, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	out := bufio.NewWriter(file)
+	encoder := json.NewEncoder(out)
+	for userID, user := range x.users {
+		for id, terms := range user.documents {
+			entry := searchIndexEntry{Op: searchIndexAdd, UserID: userID, DocumentID: id, Stamp: user.stamps[id], Terms: terms}
+			if err := encoder.Encode(entry); err != nil {
+				_ = file.Close()
+				return fmt.Errorf("failed to write search index: %w", err)
+			}
+		}
+	}
+	if err := out.Flush(); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	return os.Rename(temporary, path)
+}
+
+func (x *SearchIndex) append(entry searchIndexEntry) error {
+	if x.path == "" {
+		return nil
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(x.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open search index: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	return file.Close()
+}
+
+func (x *SearchIndex) user(userID string) *userSearchIndex {
+	user, ok := x.users[userID]
+	if !ok {
+		user = &userSearchIndex{
+			documents: make(map[string][]string),
+			stamps:    make(map[string]int64),
+			postings:  make(map[string]map[string][]int),
+		}
+		x.users[userID] = user
+	}
+	return user
+}
+
+// Add indexes the text of a document, replacing its previous text.
+func (x *SearchIndex) Add(document *domain.Document) error {
+	terms := x.terms(document.Text)
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	return x.add(document.UserID, document.ID, document.UpdatedAt.UnixNano(), terms)
+}
+
+func (x *SearchIndex) terms(text string) []string {
+	tokens := x.tokenizer.Tokenize(text)
+	terms := make([]string, len(tokens))
+	for i, token := range tokens {
+		terms[i] = foldTerm(token.Text)
+	}
+	return terms
+}
+
+func (x *SearchIndex) add(userID, id string, stamp int64, terms []string) error {
+	entry := searchIndexEntry{Op: searchIndexAdd, UserID: userID, DocumentID: id, Stamp: stamp, Terms: terms}
+	if err := x.append(entry); err != nil {
+		return err
+	}
+	x.user(userID).add(id, stamp, terms)
+	return nil
+}
+
+// Remove drops a document of a user from the index.
+func (x *SearchIndex) Remove(userID, id string) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if _, ok := x.users[userID].stamp(id); !ok {
+		return nil
+	}
+	return x.remove(userID, id)
+}
+
+func (x *SearchIndex) remove(userID, id string) error {
+	if err := x.append(searchIndexEntry{Op: searchIndexRemove, UserID: userID, DocumentID: id}); err != nil {
+		return err
+	}
+	x.users[userID].remove(id)
+	return nil
+}
+
+// Sync brings the index in line with the documents of a repository, indexing
+// the documents it misses or holds another version of and dropping the
+// documents no longer stored, as when the index is kept in memory or a write
+// to it failed. Versions are told apart by their update time, so that only
+// the documents that changed are tokenized again. It returns the number of
+// documents indexed and removed.
+func (x *SearchIndex) Sync(ctx context.Context, repo domain.DocumentRepository) (indexed, removed int, err error) {
+	stored := make(map[string]map[string]bool)
+	err = repo.Walk(ctx, func(document *domain.Document) error {
+		if stored[document.UserID] == nil {
+			stored[document.UserID] = make(map[string]bool)
+		}
+		stored[document.UserID][document.ID] = true
+
+		x.mu.RLock()
+		stamp, ok := x.users[document.UserID].stamp(document.ID)
+		x.mu.RUnlock()
+		if ok && stamp == document.UpdatedAt.UnixNano() {
+			return nil
+		}
+		indexed++
+		return x.Add(document)
+	})
+	if err != nil {
+		return indexed, removed, fmt.Errorf("failed to index stored documents: %w", err)
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for userID, user := range x.users {
+		for id := range user.documents {
+			if stored[userID][id] {
+				continue
+			}
+			if err := x.remove(userID, id); err != nil {
+				return indexed, removed, err
+			}
+			removed++
+		}
+	}
+	return indexed, removed, nil
+}
+
+func (u *userSearchIndex) stamp(id string) (int64, bool) {
+	if u == nil {
+		return 0, false
+	}
+	stamp, ok := u.stamps[id]
+	return stamp, ok
+}
+
+func (u *userSearchIndex) add(id string, stamp int64, terms []string) {
+	u.remove(id)
+	u.documents[id] = terms
+	u.stamps[id] = stamp
+	u.totalLength += len(terms)
+	for position, term := range terms {
+		if u.postings[term] == nil {
+			u.postings[term] = make(map[string][]int)
+		}
+		u.postings[term][id] = append(u.postings[term][id], position)
+	}
+}
+
+func (u *userSearchIndex) remove(id string) {
+	terms, ok := u.documents[id]
+	if !ok {
+		return
+	}
+	for _, term := range terms {
+		delete(u.postings[term], id)
+		if len(u.postings[term]) == 0 {
+			delete(u.postings, term)
+		}
+	}
+	u.totalLength -= len(terms)
+	delete(u.documents, id)
+	delete(u.stamps, id)
+}
+
+// phraseAt reports whether a document holds words from position start on.
+func (u *userSearchIndex) phraseAt(id string, words []string, start int) bool {
+	terms := u.documents[id]
+	if start+len(words) > len(terms) {
+		return false
+	}
+	for i, word := range words {
+		if terms[start+i] != word {
+			return false
+		}
+	}
+	return true
+}
+
+// bm25 scores a document for the terms of a query.
+func (u *userSearchIndex) bm25(id string, terms map[string]bool) float64 {
+	count := float64(len(u.documents))
+	averageLength := float64(u.totalLength) / count
+	length := float64(len(u.documents[id]))
+
+	score := 0.0
+	for term := range terms {
+		frequency := float64(len(u.postings[term][id]))
+		if frequency == 0 {
+			continue
+		}
+		documents := float64(len(u.postings[term]))
+		idf := math.Log(1 + (count-documents+0.5)/(documents+0.5))
+		score += idf * frequency * (bm25K1 + 1) / (frequency + bm25K1*(1-bm25B+bm25B*length/averageLength))
+	}
+	return score
+}
+
+// idfTable returns the idf of some terms over the documents of a user, or nil
+// when the user has fewer than minCorpusDocuments documents.
+func (x *SearchIndex) idfTable(userID string, terms []string) *IDFTable {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	user, ok := x.users[userID]
+	if !ok || len(user.documents) < minCorpusDocuments {
+		return nil
+	}
+	total := len(user.documents)
+	table := &IDFTable{
+		weights: make(map[string]float64, len(terms)),
+		unknown: smoothedIDF(total, 0),
+	}
+	for _, term := range terms {
+		if frequency := len(user.postings[term]); frequency > 0 {
+			table.weights[term] = smoothedIDF(total, frequency)
+		}
+	}
+	return table
+}
+
+// searchHit is a document matching a query with its BM25 score.
+type searchHit struct {
+	id    string
+	score float64
+}
+
+// search returns the documents of a user matching a query, best first, and
+// the indexed terms the query matched on.
+func (x *SearchIndex) search(userID string, query searchNode) ([]searchHit, map[string]bool) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	user, ok := x.users[userID]
+	if !ok || len(user.documents) == 0 {
+		return nil, nil
+	}
+
+	terms := make(map[string]bool)
+	query.terms(user, func(term string) { terms[term] = true })
+
+	var hits []searchHit
+	for id := range query.match(user) {
+		hits = append(hits, searchHit{id: id, score: user.bm25(id, terms)})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		return hits[i].id < hits[j].id
+	})
+	return hits, terms
+}
+
+type searchService struct {
+	index  *SearchIndex
+	repo   domain.DocumentRepository
+	logger *zap.Logger
+}
+
+// NewSearchService returns a service searching the documents of the
+// authenticated user, ranked by BM25.
+func NewSearchService(index *SearchIndex, repo domain.DocumentRepository, logger *zap.Logger) domain.SearchService {
+	return &searchService{
+		index:  index,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (s *searchService) Search(ctx context.Context, req *domain.SearchRequest) (*domain.SearchResponse, error) {
+	user, ok := domain.UserFromContext(ctx)
+	if !ok {
+		return nil, errNoUser
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+	if limit < 0 || limit > maxSearchLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidInput, maxSearchLimit)
+	}
+	if utf8.RuneCountInString(req.Query) > maxSearchQueryRunes {
+		return nil, fmt.Errorf("%w: query is longer than %d characters", domain.ErrInvalidInput, maxSearchQueryRunes)
+	}
+	query, err := parseSearchQuery(req.Query, s.index.tokenizer)
+	if err != nil {
+		return nil, err
+	}
+
+	hits, terms := s.index.search(user.ID, query)
+	response := &domain.SearchResponse{
+		Query:   req.Query,
+		Total:   len(hits),
+		Results: []domain.SearchResult{},
+	}
+	for _, hit := range hits {
+		if len(response.Results) == limit {
+			break
+		}
+		document, err := s.repo.Get(ctx, user.ID, hit.id)
+		if errors.Is(err, domain.ErrNotFound) {
+			// The index outlived the document, as when removing it from the
+			// index failed.
+			response.Total--
+			if err := s.index.Remove(user.ID, hit.id); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		snippet, highlights := s.snippet(document.Text, terms)
+		response.Results = append(response.Results, domain.SearchResult{
+			DocumentID: document.ID,
+			Title:      document.Title,
+			Score:      round4(hit.score),
+			Snippet:    snippet,
+			Highlights: highlights,
+		})
+	}
+
+	s.logger.Info("Search completed",
+		zap.String("user_id", user.ID),
+		zap.Int("total", response.Total),
+		zap.Int("returned", len(response.Results)),
+	)
+
+	return response, nil
+}
+
+// snippet returns the passage of snippetWords words of a text holding the
+// most matched words, starting a few words before the first of them, with
+// the offsets of the matched words within it.
+func (s *searchService) snippet(text string, terms map[string]bool) (string, []domain.Highlight) {
+	tokens := s.index.tokenizer.Tokenize(text)
+	if len(tokens) == 0 {
+		return "", []domain.Highlight{}
+	}
+	matched := make([]bool, len(tokens))
+	for i, token := range tokens {
+		matched[i] = terms[foldTerm(token.Text)]
+	}
+
+	best, bestCount := 0, -1
+	for i := range tokens {
+		if !matched[i] {
+			continue
+		}
+		start := max(0, i-snippetLeadingWords)
+		count := 0
+		for _, m := range matched[start:min(len(tokens), start+snippetWords)] {
+			if m {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = start, count
+		}
+	}
+	end := min(len(tokens), best+snippetWords)
+
+	runes := []rune(text)
+	offset := tokens[best].Start
+	highlights := []domain.Highlight{}
+	for i := best; i < end; i++ {
+		if matched[i] {
+			highlights = append(highlights, domain.Highlight{
+				Start: tokens[i].Start - offset,
+				End:   tokens[i].End - offset,
+			})
+		}
+	}
+	return string(runes[offset:tokens[end-1].End]), highlights
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"vm-chan/internal/domain"
+)
+
+// searchNode is a node of a parsed search query.
+type searchNode interface {
+	// match returns the documents of the index matching the node.
+	match(index *userSearchIndex) map[string]bool
+	// terms calls fn with the indexed terms the node matches on, which score
+	// the documents it matches. Negated nodes do not call it.
+	terms(index *userSearchIndex, fn func(term string))
+}
+
+type termNode struct {
+	term   string
+	prefix bool
+}
+
+type phraseNode struct {
+	words []string
+}
+
+type andNode struct {
+	children []searchNode
+}
+
+type orNode struct {
+	children []searchNode
+}
+
+type notNode struct {
+	child searchNode
+}
+
+func (n *termNode) match(index *userSearchIndex) map[string]bool {
+	matched := make(map[string]bool)
+	n.terms(index, func(term string) {
+		for id := range index.postings[term] {
+			matched[id] = true
+		}
+	})
+	return matched
+}
+
+func (n *termNode) terms(index *userSearchIndex, fn func(term string)) {
+	if !n.prefix {
+		if _, ok := index.postings[n.term]; ok {
+			fn(n.term)
+		}
+		return
+	}
+	for term := range index.postings {
+		if strings.HasPrefix(term, n.term) {
+			fn(term)
+		}
+	}
+}
+
+func (n *phraseNode) match(index *userSearchIndex) map[string]bool {
+	matched := make(map[string]bool)
+	for id, positions := range index.postings[n.words[0]] {
+		for _, start := range positions {
+			if index.phraseAt(id, n.words, start) {
+				matched[id] = true
+				break
+			}
+		}
+	}
+	return matched
+}
+
+func (n *phraseNode) terms(index *userSearchIndex, fn func(term string)) {
+	for _, word := range n.words {
+		fn(word)
+	}
+}
+
+func (n *andNode) match(index *userSearchIndex) map[string]bool {
+	matched := n.children[0].match(index)
+	for _, child := range n.children[1:] {
+		other := child.match(index)
+		for id := range matched {
+			if !other[id] {
+				delete(matched, id)
+			}
+		}
+	}
+	return matched
+}
+
+func (n *andNode) terms(index *userSearchIndex, fn func(term string)) {
+	for _, child := range n.children {
+		child.terms(index, fn)
+	}
+}
+
+func (n *orNode) match(index *userSearchIndex) map[string]bool {
+	matched := make(map[string]bool)
+	for _, child := range n.children {
+		for id := range child.match(index) {
+			matched[id] = true
+		}
+	}
+	return matched
+}
+
+func (n *orNode) terms(index *userSearchIndex, fn func(term string)) {
+	for _, child := range n.children {
+		child.terms(index, fn)
+	}
+}
+
+func (n *notNode) match(index *userSearchIndex) map[string]bool {
+	excluded := n.child.match(index)
+	matched := make(map[string]bool)
+	for id := range index.documents {
+		if !excluded[id] {
+			matched[id] = true
+		}
+	}
+	return matched
+}
+
+func (n *notNode) terms(index *userSearchIndex, fn func(term string)) {}
+
+// searchParser parses queries of words, "quoted phrases" and word* prefixes,
+// combined with AND (or mere juxtaposition), OR, NOT or a leading minus, and
+// grouped with parentheses. AND binds tighter than OR. Words are split and
+// case-folded like analyzed text, and a word the tokenizer splits, such as
+// "e-mail", is searched as a phrase.
+type searchParser struct {
+	tokenizer Tokenizer
+	tokens    []string
+	position  int
+}
+
+func parseSearchQuery(query string, tokenizer Tokenizer) (searchNode, error) {
+	tokens, err := lexSearchQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &searchParser{tokenizer: tokenizer, tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.position < len(p.tokens) {
+		return nil, fmt.Errorf("%w: unexpected %q in query", domain.ErrInvalidInput, p.tokens[p.position])
+	}
+	if node == nil {
+		return nil, fmt.Errorf("%w: query has no words", domain.ErrInvalidInput)
+	}
+	return node, nil
+}
+
+// lexSearchQuery splits a query into parentheses, quoted phrases (kept with
+// their opening quote), minus signs and words.
+func lexSearchQuery(query string) ([]string, error) {
+	var tokens []string
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, string(r))
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("%w: unterminated phrase in query", domain.ErrInvalidInput)
+			}
+			tokens = append(tokens, string(runes[i:end]))
+			i = end + 1
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) && (i == 0 || unicode.IsSpace(runes[i-1]) || runes[i-1] == '('):
+			tokens = append(tokens, "-")
+			i++
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(`()"`, runes[end]) {
+				end++
+			}
+			tokens = append(tokens, string(runes[i:end]))
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+func (p *searchParser) peek() string {
+	if p.position < len(p.tokens) {
+		return p.tokens[p.position]
+	}
+	return ""
+}
+
+func (p *searchParser) parseOr() (searchNode, error) {
+	var children []searchNode
+	for {
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			children = append(children, node)
+		}
+		if p.peek() != "OR" {
+			break
+		}
+		p.position++
+	}
+	switch len(children) {
+	case 0:
+		return nil, nil
+	case 1:
+		return children[0], nil
+	}
+	return &orNode{children: children}, nil
+}
+
+func (p *searchParser) parseAnd() (searchNode, error) {
+	var children []searchNode
+	for {
+		switch p.peek() {
+		case "", ")", "OR":
+			switch len(children) {
+			case 0:
+				return nil, nil
+			case 1:
+				return children[0], nil
+			}
+			return &andNode{children: children}, nil
+		case "AND":
+			p.position++
+			continue
+		}
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			children = append(children, node)
+		}
+	}
+}
+
+func (p *searchParser) parseUnary() (searchNode, error) {
+	if token := p.peek(); token == "NOT" || token == "-" {
+		p.position++
+		child, err := p.parseUnary()
+		if err != nil || child == nil {
+			return nil, err
+		}
+		return &notNode{child: child}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *searchParser) parsePrimary() (searchNode, error) {
+	token := p.peek()
+	p.position++
+	switch {
+	case token == "(":
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("%w: unbalanced parentheses in query", domain.ErrInvalidInput)
+		}
+		p.position++
+		return node, nil
+	case token == ")":
+		return nil, fmt.Errorf("%w: unbalanced parentheses in query", domain.ErrInvalidInput)
+	case strings.HasPrefix(token, `"`):
+		return p.words(token[1:], false), nil
+	}
+	word, prefix := strings.CutSuffix(token, "*")
+	return p.words(word, prefix), nil
+}
+
+// words returns the node searching the words of a query word or phrase, or
+// nil when it has none.
+func (p *searchParser) words(text string, prefix bool) searchNode {
+	var words []string
+	for _, token := range p.tokenizer.Tokenize(text) {
+		words = append(words, foldTerm(token.Text))
+	}
+	switch len(words) {
+	case 0:
+		return nil
+	case 1:
+		return &termNode{term: words[0], prefix: prefix}
+	}
+	return &phraseNode{words: words}
+}
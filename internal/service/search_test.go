@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"vm-chan/internal/domain"
+	"vm-chan/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSearchService(t *testing.T) {
+	registry, err := NewAnalyzerRegistry(DefaultAnalyzers(AnalyzerResources{})...)
+	require.NoError(t, err)
+	analysis := NewTextAnalysisService(registry, NewTokenizer(), nil, zap.NewNop())
+	repo := repository.NewDocumentRepository(zap.NewNop())
+	path := filepath.Join(t.TempDir(), "search.jsonl")
+	index, err := LoadSearchIndex(path, NewTokenizer())
+	require.NoError(t, err)
+	documents := NewDocumentService(repo, analysis, index, nil, zap.NewNop())
+	search := NewSearchService(index, repo, zap.NewNop())
+
+	alice := domain.WithUser(context.Background(), &domain.User{ID: "alice"})
+	bob := domain.WithUser(context.Background(), &domain.User{ID: "bob"})
+
+	create := func(title, text string) string {
+		document, err := documents.Create(alice, &domain.DocumentRequest{Title: title, Text: text, Language: "en"})
+		require.NoError(t, err)
+		return document.ID
+	}
+	report := create("Report", "The quarterly report shows that revenue grew. The quarterly report was late.")
+	memo := create("Memo", "Revenue targets for the next report are in the memo.")
+	notes := create("Notes", "Meeting notes: the team reviewed the roadmap and the hiring plan.")
+
+	ids := func(t *testing.T, ctx context.Context, query string) []string {
+		response, err := search.Search(ctx, &domain.SearchRequest{Query: query})
+		require.NoError(t, err)
+		assert.Equal(t, len(response.Results), response.Total)
+		found := []string{}
+		for _, result := range response.Results {
+			found = append(found, result.DocumentID)
+		}
+		return found
+	}
+
+	t.Run("Ranking", func(t *testing.T) {
+		assert.Equal(t, []string{report, memo}, ids(t, alice, "report"))
+		assert.Equal(t, []string{report, memo}, ids(t, alice, "REPORT revenue"))
+		assert.Empty(t, ids(t, bob, "report"), "documents of other users are not searched")
+	})
+
+	t.Run("Operators", func(t *testing.T) {
+		assert.Equal(t, []string{report}, ids(t, alice, `"quarterly report"`))
+		assert.Empty(t, ids(t, alice, `"report quarterly"`))
+		assert.Equal(t, []string{memo}, ids(t, alice, "report -quarterly"))
+		assert.Equal(t, []string{memo}, ids(t, alice, "report AND NOT quarterly"))
+		assert.ElementsMatch(t, []string{memo, notes}, ids(t, alice, "memo OR roadmap"))
+		assert.ElementsMatch(t, []string{report, notes}, ids(t, alice, "(grew OR hiring) the"))
+		assert.Equal(t, []string{notes}, ids(t, alice, "road*"))
+		assert.Equal(t, []string{notes}, ids(t, alice, "-report"))
+		assert.ElementsMatch(t, []string{report, memo}, ids(t, alice, "reve*"))
+		assert.ElementsMatch(t, []string{report, memo, notes}, ids(t, alice, "rev*"))
+	})
+
+	t.Run("Snippet", func(t *testing.T) {
+		response, err := search.Search(alice, &domain.SearchRequest{Query: "revenue", Limit: 1})
+		require.NoError(t, err)
+		assert.Equal(t, 2, response.Total)
+		require.Len(t, response.Results, 1)
+		result := response.Results[0]
+		assert.Equal(t, "Memo", result.Title, "the shorter document ranks first")
+		assert.Greater(t, result.Score, 0.0)
+		require.Len(t, result.Highlights, 1)
+		highlight := result.Highlights[0]
+		assert.Equal(t, "Revenue", string([]rune(result.Snippet)[highlight.Start:highlight.End]))
+	})
+
+	t.Run("Writes", func(t *testing.T) {
+		_, err := documents.Update(alice, notes, &domain.DocumentRequest{Text: "The roadmap moved to the wiki."})
+		require.NoError(t, err)
+		assert.Empty(t, ids(t, alice, "hiring"))
+		assert.Equal(t, []string{notes}, ids(t, alice, "wiki"))
+
+		require.NoError(t, documents.Delete(alice, notes))
+		assert.Empty(t, ids(t, alice, "roadmap"))
+	})
+
+	t.Run("Persistence", func(t *testing.T) {
+		reloaded, err := LoadSearchIndex(path, NewTokenizer())
+		require.NoError(t, err)
+		search := NewSearchService(reloaded, repo, zap.NewNop())
+
+		response, err := search.Search(alice, &domain.SearchRequest{Query: `"quarterly report" OR memo`})
+		require.NoError(t, err)
+		assert.Equal(t, 2, response.Total)
+		response, err = search.Search(alice, &domain.SearchRequest{Query: "roadmap"})
+		require.NoError(t, err)
+		assert.Zero(t, response.Total)
+
+		indexed, removed, err := reloaded.Sync(context.Background(), repo)
+		require.NoError(t, err)
+		assert.Zero(t, indexed, "update times are saved with the index")
+		assert.Zero(t, removed)
+	})
+
+	t.Run("Sync", func(t *testing.T) {
+		index := NewSearchIndex(NewTokenizer())
+		stored, err := repo.Get(alice, "alice", report)
+		require.NoError(t, err)
+		require.NoError(t, index.Add(&domain.Document{UserID: "alice", ID: "gone", Text: "The roadmap of a deleted document."}))
+		require.NoError(t, index.Add(&domain.Document{UserID: "alice", ID: memo, Text: "An outdated text."}))
+		require.NoError(t, index.Add(&domain.Document{
+			UserID: "alice", ID: report, Text: "An unchanged report.", UpdatedAt: stored.UpdatedAt,
+		}))
+
+		indexed, removed, err := index.Sync(context.Background(), repo)
+		require.NoError(t, err)
+		assert.Equal(t, 1, indexed, "only the outdated document is indexed")
+		assert.Equal(t, 1, removed)
+
+		search := NewSearchService(index, repo, zap.NewNop())
+		response, err := search.Search(alice, &domain.SearchRequest{Query: "memo OR outdated OR roadmap"})
+		require.NoError(t, err)
+		require.Len(t, response.Results, 1)
+		assert.Equal(t, memo, response.Results[0].DocumentID)
+		response, err = search.Search(alice, &domain.SearchRequest{Query: "unchanged"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, response.Total, "documents indexed at their update time are not tokenized again")
+
+		indexed, removed, err = index.Sync(context.Background(), repo)
+		require.NoError(t, err)
+		assert.Zero(t, indexed)
+		assert.Zero(t, removed)
+	})
+
+	t.Run("Indexing failures do not fail writes", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "index")
+		require.NoError(t, os.Mkdir(dir, 0o700))
+		index, err := LoadSearchIndex(filepath.Join(dir, "search.jsonl"), NewTokenizer())
+		require.NoError(t, err)
+		require.NoError(t, index.Add(&domain.Document{UserID: "alice", ID: report, Text: "The quarterly report."}))
+		require.NoError(t, os.RemoveAll(dir))
+		documents := NewDocumentService(repo, analysis, index, nil, zap.NewNop())
+
+		document, err := documents.Create(alice, &domain.DocumentRequest{Text: "An unindexed draft.", Language: "en"})
+		require.NoError(t, err)
+		_, err = repo.Get(alice, "alice", document.ID)
+		require.NoError(t, err)
+		_, err = documents.Update(alice, document.ID, &domain.DocumentRequest{Text: "A revised draft."})
+		require.NoError(t, err)
+		require.NoError(t, documents.Delete(alice, report))
+	})
+
+	t.Run("Invalid requests", func(t *testing.T) {
+		for name, req := range map[string]*domain.SearchRequest{
+			"unbalanced parenthesis": {Query: "(report OR memo"},
+			"unterminated phrase":    {Query: `"quarterly report`},
+			"empty query":            {Query: "AND"},
+			"limit above maximum":    {Query: "report", Limit: 1000},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := search.Search(alice, req)
+				assert.ErrorIs(t, err, domain.ErrInvalidInput)
+			})
+		}
+	})
+}
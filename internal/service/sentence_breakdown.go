@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"vm-chan/internal/domain"
+)
+
+type sentencesAnalyzer struct{}
+
+func NewSentencesAnalyzer() Analyzer {
+	return &sentencesAnalyzer{}
+}
+
+func (a *sentencesAnalyzer) Name() string {
+	return "sentences"
+}
+
+func (a *sentencesAnalyzer) Version() string {
+	return "1.0.0"
+}
+
+func (a *sentencesAnalyzer) Description() string {
+	return "Per-sentence word and letter counts with character offsets, plus average, median and longest sentence length"
+}
+
+func (a *sentencesAnalyzer) Analyze(ctx context.Context, doc *Document) (interface{}, error) {
+	spans := doc.sentences()
+
+	result := &domain.SentencesResult{
+		SentenceCount: len(spans),
+		Sentences:     make([]domain.SentenceBreakdown, 0, len(spans)),
+	}
+	if len(spans) == 0 {
+		return result, nil
+	}
+
+	lengths := make([]int, 0, len(spans))
+	for i, span := range spans {
+		words := len(doc.tokensIn(span.start, span.end))
+		letters := doc.phonology.countLetters(span.text)
+
+		result.Sentences = append(result.Sentences, domain.SentenceBreakdown{
+			Text:             span.text,
+			Start:            span.start,
+			End:              span.end,
+			WordCount:        words,
+			VowelCount:       letters.vowels,
+			ConsonantCount:   letters.consonants,
+			OtherLetterCount: letters.other,
+		})
+		lengths = append(lengths, words)
+
+		if words > result.LongestLength {
+			result.LongestLength = words
+			result.LongestIndex = i
+		}
+	}
+
+	sorted := append([]int(nil), lengths...)
+	sort.Ints(sorted)
+
+	total := 0
+	for _, length := range sorted {
+		total += length
+	}
+
+	result.ShortestLength = sorted[0]
+	result.AverageLength = round2(float64(total) / float64(len(sorted)))
+	result.MedianLength = median(sorted)
+
+	return result, nil
+}
+
+// median expects sorted input.
+func median(sorted []int) float64 {
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return float64(sorted[middle])
+	}
+	return float64(sorted[middle-1]+sorted[middle]) / 2
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+)
+
+// sentenceSpan is a sentence with its position in the analyzed text, expressed
+// in Unicode code points. End is exclusive.
+type sentenceSpan struct {
+	text  string
+	start int
+	end   int
+}
+
+// Abbreviations whose trailing period does not end a sentence, lowercase and
+// without the period. Multi-part abbreviations such as "e.g." and "U.S." are
+// recognized by their internal periods and do not need to be listed.
+var sentenceAbbreviations = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "prof": true,
+	"st": true, "mt": true, "rev": true, "gen": true, "col": true, "capt": true, "lt": true, "sgt": true,
+	"vs": true, "cf": true, "bzw": true, "vgl": true, "ggf": true, "evtl": true,
+	"mme": true, "mlle": true, "sra": true, "srta": true, "sig": true, "см": true,
+}
+
+// Abbreviations that are also words or often end a sentence, such as "no.",
+// "Dec." or "p.m.", whose trailing period does not end a sentence only when a
+// number follows, as in "no. 5" or "Dec. 24".
+var numberAbbreviations = map[string]bool{
+	"no": true, "nr": true, "vol": true, "fig": true, "pp": true, "pág": true, "ed": true,
+	"jan": true, "feb": true, "mar": true, "apr": true, "jun": true, "jul": true, "aug": true,
+	"sep": true, "sept": true, "oct": true, "nov": true, "dec": true, "a.m": true, "p.m": true,
+	"co": true, "inc": true, "ltd": true, "corp": true, "dept": true, "est": true, "sr": true, "jr": true,
+	"etc": true, "al": true, "approx": true, "ca": true, "usw": true, "ecc": true,
+	"т": true, "г": true, "др": true, "пр": true,
+}
+
+var closingPunctuation = "\"'”’»)]}」』"
+
+// splitSentences segments text into sentences. A run of terminal punctuation
+// ends a sentence when it is followed by whitespace or the end of the text,
+// unless the period belongs to a known abbreviation or an initial, or an
+// ellipsis or period is followed by a lowercase continuation. Closing quotes and
+// brackets after the terminator stay with the sentence they close. Decimal
+// numbers never split because their period is not followed by whitespace.
+func splitSentences(text string) []sentenceSpan {
+	runes := []rune(text)
+
+	var sentences []sentenceSpan
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		if !isSentenceTerminal(runes[i]) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(runes) && isSentenceTerminal(runes[end]) {
+			end++
+		}
+		for end < len(runes) && strings.ContainsRune(closingPunctuation, runes[end]) {
+			end++
+		}
+
+		if isSentenceBoundary(runes, i, end) {
+			sentences = appendSentence(sentences, runes, start, end)
+			start = end
+		}
+		i = end - 1
+	}
+
+	return appendSentence(sentences, runes, start, len(runes))
+}
+
+// isSentenceBoundary decides whether the terminator run starting at runes[i]
+// and ending before runes[end] (including trailing closers) ends a sentence.
+func isSentenceBoundary(runes []rune, i, end int) bool {
+	if isFullwidthTerminal(runes[i]) {
+		return true
+	}
+	if end < len(runes) && !unicode.IsSpace(runes[end]) {
+		return false
+	}
+
+	next := nextNonSpace(runes, end)
+	if next == 0 {
+		return true
+	}
+
+	terminators := string(runes[i:end])
+	if strings.ContainsAny(terminators, "!?") {
+		return true
+	}
+
+	if unicode.IsLower(next) {
+		return false
+	}
+
+	isEllipsis := strings.Contains(terminators, "…") || strings.Contains(terminators, "..")
+	if !isEllipsis && isAbbreviation(runes, i, end, next) {
+		return false
+	}
+
+	return true
+}
+
+// isAbbreviation reports whether the period at runes[i] ends an abbreviation
+// rather than the sentence, given the first letter or digit of the next
+// sentence. A single letter is an initial only before a number or next to
+// another initial, as in "J. K. Rowling", so that "Plan B. Next" splits.
+func isAbbreviation(runes []rune, i, end int, next rune) bool {
+	word, start := precedingWord(runes, i)
+	if word == "" {
+		return false
+	}
+
+	lower := strings.ToLower(word)
+	switch {
+	case numberAbbreviations[lower]:
+		return unicode.IsDigit(next)
+	case isInitial(word + "."):
+		if unicode.IsDigit(next) || isInitial(followingWord(runes, end)) {
+			return true
+		}
+		previous, _ := precedingWord(runes, lastNonSpace(runes, start))
+		return isInitial(previous)
+	case strings.Contains(word, "."):
+		return isDottedAbbreviation(word)
+	}
+	return sentenceAbbreviations[lower]
+}
+
+// isInitial reports whether word is a single letter followed by a period.
+func isInitial(word string) bool {
+	runes := []rune(word)
+	return len(runes) == 2 && unicode.IsLetter(runes[0]) && runes[1] == '.'
+}
+
+// isDottedAbbreviation reports whether word consists of short letter groups
+// separated by periods, such as "e.g" or "U.S", as opposed to "2.72".
+func isDottedAbbreviation(word string) bool {
+	for _, part := range strings.Split(word, ".") {
+		if part == "" || len([]rune(part)) > 3 {
+			return false
+		}
+		for _, r := range part {
+			if !unicode.IsLetter(r) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// precedingWord returns the token directly before position i, without leading
+// opening punctuation, and the position the token starts at.
+func precedingWord(runes []rune, i int) (string, int) {
+	start := i
+	for start > 0 && !unicode.IsSpace(runes[start-1]) {
+		start--
+	}
+
+	return strings.TrimLeftFunc(string(runes[start:i]), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), start
+}
+
+// followingWord returns the token starting after the whitespace at position
+// i.
+func followingWord(runes []rune, i int) string {
+	for i < len(runes) && unicode.IsSpace(runes[i]) {
+		i++
+	}
+	end := i
+	for end < len(runes) && !unicode.IsSpace(runes[end]) {
+		end++
+	}
+	return string(runes[i:end])
+}
+
+// lastNonSpace returns the position after the last non-whitespace rune before
+// position i, or 0.
+func lastNonSpace(runes []rune, i int) int {
+	for i > 0 && unicode.IsSpace(runes[i-1]) {
+		i--
+	}
+	return i
+}
+
+func nextNonSpace(runes []rune, i int) rune {
+	for ; i < len(runes); i++ {
+		if !unicode.IsSpace(runes[i]) {
+			if strings.ContainsRune("\"'“‘«(", runes[i]) {
+				continue
+			}
+			return runes[i]
+		}
+	}
+	return 0
+}
+
+func appendSentence(sentences []sentenceSpan, runes []rune, start, end int) []sentenceSpan {
+	for start < end && unicode.IsSpace(runes[start]) {
+		start++
+	}
+	for end > start && unicode.IsSpace(runes[end-1]) {
+		end--
+	}
+	if start == end {
+		return sentences
+	}
+
+	return append(sentences, sentenceSpan{
+		text:  string(runes[start:end]),
+		start: start,
+		end:   end,
+	})
+}
+
+func isSentenceTerminal(r rune) bool {
+	switch r {
+	case '.', '!', '?', '…', '。', '！', '？':
+		return true
+	}
+	return false
+}
+
+func isFullwidthTerminal(r rune) bool {
+	return r == '。' || r == '！' || r == '？'
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"testing"
+
+	"vm-chan/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitSentences(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{
+			name:     "Terminal punctuation",
+			text:     "Hello world. How are you? Great!",
+			expected: []string{"Hello world.", "How are you?", "Great!"},
+		},
+		{
+			name:     "Abbreviations",
+			text:     "Dr. Smith met Mr. Jones at 5 p.m. today. They talked.",
+			expected: []string{"Dr. Smith met Mr. Jones at 5 p.m. today.", "They talked."},
+		},
+		{
+			name:     "Multi-part abbreviation",
+			text:     "Bring fruit, e.g. Apples or pears. Then leave.",
+			expected: []string{"Bring fruit, e.g. Apples or pears.", "Then leave."},
+		},
+		{
+			name:     "Decimal numbers",
+			text:     "Pi is about 3.14 and e is 2.72. Both are irrational.",
+			expected: []string{"Pi is about 3.14 and e is 2.72.", "Both are irrational."},
+		},
+		{
+			name:     "Ellipsis",
+			text:     "Well... maybe. I waited… Nothing happened.",
+			expected: []string{"Well... maybe.", "I waited…", "Nothing happened."},
+		},
+		{
+			name:     "Quotes stay with their sentence",
+			text:     `He said "Stop." Then he left. "Why?" she asked.`,
+			expected: []string{`He said "Stop."`, "Then he left.", `"Why?"`, "she asked."},
+		},
+		{
+			name:     "Initials",
+			text:     "J. K. Rowling wrote it. Fans loved it.",
+			expected: []string{"J. K. Rowling wrote it.", "Fans loved it."},
+		},
+		{
+			name: "Abbreviations that are also words",
+			text: "I said no. Then I left. It closed in Dec. The shop reopened. Ask the co. It knows. " +
+				"Go with Plan B. Next we meet at 5 p.m. He will come.",
+			expected: []string{
+				"I said no.", "Then I left.", "It closed in Dec.", "The shop reopened.", "Ask the co.", "It knows.",
+				"Go with Plan B.", "Next we meet at 5 p.m.", "He will come.",
+			},
+		},
+		{
+			name:     "Abbreviations before numbers",
+			text:     "See no. 5 and fig. 2 of the Dec. 24 issue.",
+			expected: []string{"See no. 5 and fig. 2 of the Dec. 24 issue."},
+		},
+		{
+			name:     "Fullwidth terminators",
+			text:     "今天很好。明天见！",
+			expected: []string{"今天很好。", "明天见！"},
+		},
+		{
+			name:     "No terminator",
+			text:     "  just some words  ",
+			expected: []string{"just some words"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spans := splitSentences(tt.text)
+
+			texts := make([]string, 0, len(spans))
+			for _, span := range spans {
+				texts = append(texts, span.text)
+				assert.Equal(t, span.text, string([]rune(tt.text)[span.start:span.end]))
+			}
+			assert.Equal(t, tt.expected, texts)
+		})
+	}
+}
+
+func TestSentencesAnalyzer(t *testing.T) {
+	analyzer := NewSentencesAnalyzer()
+
+	sentences := analyzeDoc[*domain.SentencesResult](t, analyzer, "Héllo world. This one is longer. Ok.", "en")
+	require.Equal(t, 3, sentences.SentenceCount)
+	assert.Equal(t, domain.SentenceBreakdown{
+		Text:           "This one is longer.",
+		Start:          13,
+		End:            32,
+		WordCount:      4,
+		VowelCount:     6,
+		ConsonantCount: 9,
+	}, sentences.Sentences[1])
+	assert.Equal(t, 0, sentences.Sentences[0].Start)
+	assert.Equal(t, 12, sentences.Sentences[0].End)
+	assert.Equal(t, 4, sentences.LongestLength)
+	assert.Equal(t, 1, sentences.LongestIndex)
+	assert.Equal(t, 1, sentences.ShortestLength)
+	assert.Equal(t, 2.33, sentences.AverageLength)
+	assert.Equal(t, 2.0, sentences.MedianLength)
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"math"
+	"strings"
+	"unicode"
+
+	"vm-chan/internal/domain"
+)
+
+type sentimentScore struct {
+	compound float64
+	positive float64
+	negative float64
+	neutral  float64
+	label    string
+}
+
+const (
+	boosterIncrement     = 0.293
+	capsIncrement        = 0.733
+	negationScalar       = -0.74
+	exclamationIncrement = 0.292
+	questionIncrement    = 0.18
+	compoundNormalizer   = 15
+	sentimentThreshold   = 0.05
+	modifierWindow       = 3
+)
+
+// Words that strengthen or weaken the sentiment of the words following them.
+var sentimentBoosters = map[string]float64{
+	"absolutely": boosterIncrement, "amazingly": boosterIncrement, "completely": boosterIncrement,
+	"considerably": boosterIncrement, "deeply": boosterIncrement, "enormously": boosterIncrement,
+	"entirely": boosterIncrement, "especially": boosterIncrement, "exceptionally": boosterIncrement,
+	"extremely": boosterIncrement, "greatly": boosterIncrement, "highly": boosterIncrement,
+	"hugely": boosterIncrement, "incredibly": boosterIncrement, "particularly": boosterIncrement,
+	"really": boosterIncrement, "remarkably": boosterIncrement, "so": boosterIncrement,
+	"substantially": boosterIncrement, "thoroughly": boosterIncrement, "totally": boosterIncrement,
+	"tremendously": boosterIncrement, "truly": boosterIncrement, "utterly": boosterIncrement,
+	"very": boosterIncrement, "most": boosterIncrement, "more": boosterIncrement, "too": boosterIncrement,
+	"almost": -boosterIncrement, "barely": -boosterIncrement, "hardly": -boosterIncrement,
+	"kinda": -boosterIncrement, "less": -boosterIncrement, "little": -boosterIncrement,
+	"marginally": -boosterIncrement, "occasionally": -boosterIncrement, "partly": -boosterIncrement,
+	"scarcely": -boosterIncrement, "slightly": -boosterIncrement, "somewhat": -boosterIncrement,
+	"sorta": -boosterIncrement,
+}
+
+var sentimentNegations = map[string]bool{
+	"not": true, "no": true, "never": true, "none": true, "nobody": true, "nothing": true,
+	"neither": true, "nor": true, "nowhere": true, "without": true, "cannot": true,
+	"aint": true, "dont": true, "doesnt": true, "didnt": true, "isnt": true, "wasnt": true,
+	"arent": true, "werent": true, "cant": true, "couldnt": true, "wont": true, "wouldnt": true,
+	"shouldnt": true, "hasnt": true, "havent": true, "hadnt": true,
+}
+
+type sentimentAnalyzer struct {
+	lexicon *SentimentLexicon
+}
+
+// NewSentimentAnalyzer returns an analyzer scoring text against lexicon, or
+// against the embedded English lexicon when lexicon is nil.
+func NewSentimentAnalyzer(lexicon *SentimentLexicon) Analyzer {
+	if lexicon == nil {
+		lexicon = DefaultSentimentLexicon()
+	}
+	return &sentimentAnalyzer{lexicon: lexicon}
+}
+
+func (a *sentimentAnalyzer) Name() string {
+	return "sentiment"
+}
+
+func (a *sentimentAnalyzer) Version() string {
+	return "1.0.0"
+}
+
+func (a *sentimentAnalyzer) Description() string {
+	return "Lexicon-based sentiment with negation, intensifiers, capitalization, punctuation and emoji emphasis; " +
+		"compound, positive, negative and neutral scores for the text and each sentence"
+}
+
+func (a *sentimentAnalyzer) Analyze(ctx context.Context, doc *Document) (interface{}, error) {
+	spans := doc.sentences()
+	terms := doc.terms()
+
+	result := &domain.SentimentResult{
+		Sentences: make([]domain.SentenceSentiment, 0, len(spans)),
+	}
+
+	var all []float64
+	for _, span := range spans {
+		from, to := doc.tokenRange(span.start, span.end)
+		valences := a.valences(span.text, doc.Tokens()[from:to], terms[from:to])
+		all = append(all, valences...)
+
+		scores := sentimentScores(valences, span.text)
+		result.Sentences = append(result.Sentences, domain.SentenceSentiment{
+			Text:     span.text,
+			Start:    span.start,
+			End:      span.end,
+			Compound: scores.compound,
+			Positive: scores.positive,
+			Negative: scores.negative,
+			Neutral:  scores.neutral,
+			Label:    scores.label,
+		})
+	}
+
+	scores := sentimentScores(all, doc.Text)
+	result.Compound = scores.compound
+	result.Positive = scores.positive
+	result.Negative = scores.negative
+	result.Neutral = scores.neutral
+	result.Label = scores.label
+
+	return result, nil
+}
+
+// valences returns the valence of every word of a sentence after applying the
+// preceding intensifiers and negations, capitalization emphasis and the shift
+// of weight to the clause after "but", followed by the valence of its emoji.
+func (a *sentimentAnalyzer) valences(text string, tokens []Token, terms []string) []float64 {
+	emphasizeCaps := hasMixedCapitalization(tokens)
+
+	butIndex := -1
+	for i, term := range terms {
+		if term == "but" {
+			butIndex = i
+		}
+	}
+
+	valences := make([]float64, 0, len(tokens))
+	for i, term := range terms {
+		valence := a.lexicon.valence(term)
+		if valence == 0 || sentimentBoosters[term] != 0 {
+			valences = append(valences, 0)
+			continue
+		}
+
+		if emphasizeCaps && isAllCaps(tokens[i].Text) {
+			valence += math.Copysign(capsIncrement, valence)
+		}
+
+		negated := false
+		for distance := 1; distance <= modifierWindow && i-distance >= 0; distance++ {
+			previous := terms[i-distance]
+			if boost := sentimentBoosters[previous]; boost != 0 {
+				scaled := boost * (1 - 0.05*float64(distance-1))
+				if valence < 0 {
+					scaled = -scaled
+				}
+				valence += scaled
+			}
+			if isNegation(previous) {
+				negated = true
+			}
+		}
+		if negated {
+			valence *= negationScalar
+		}
+
+		switch {
+		case butIndex >= 0 && i < butIndex:
+			valence *= 0.5
+		case butIndex >= 0 && i > butIndex:
+			valence *= 1.5
+		}
+
+		valences = append(valences, valence)
+	}
+
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) {
+			continue
+		}
+		if valence := a.lexicon.valence(string(r)); valence != 0 {
+			valences = append(valences, valence)
+		}
+	}
+
+	return valences
+}
+
+// sentimentScores combines valences into the normalized compound score in
+// [-1, 1] and the proportions of positive, negative and neutral words.
+// Exclamation and question marks in text amplify the prevailing sentiment.
+func sentimentScores(valences []float64, text string) sentimentScore {
+	scores := sentimentScore{label: "neutral"}
+	if len(valences) == 0 {
+		return scores
+	}
+
+	emphasis := punctuationEmphasis(text)
+
+	sum := 0.0
+	positive, negative, neutral := 0.0, 0.0, 0.0
+	for _, valence := range valences {
+		sum += valence
+		switch {
+		case valence > 0:
+			positive += valence + 1
+		case valence < 0:
+			negative += valence - 1
+		default:
+			neutral++
+		}
+	}
+
+	switch {
+	case sum > 0:
+		sum += emphasis
+	case sum < 0:
+		sum -= emphasis
+	}
+	switch {
+	case positive > -negative:
+		positive += emphasis
+	case positive < -negative:
+		negative -= emphasis
+	}
+
+	compound := sum / math.Sqrt(sum*sum+compoundNormalizer)
+	scores.compound = round4(math.Max(-1, math.Min(1, compound)))
+
+	total := positive - negative + neutral
+	scores.positive = round4(positive / total)
+	scores.negative = round4(-negative / total)
+	scores.neutral = round4(neutral / total)
+
+	switch {
+	case scores.compound >= sentimentThreshold:
+		scores.label = "positive"
+	case scores.compound <= -sentimentThreshold:
+		scores.label = "negative"
+	}
+
+	return scores
+}
+
+func punctuationEmphasis(text string) float64 {
+	exclamations := math.Min(float64(strings.Count(text, "!")), 4)
+	emphasis := exclamations * exclamationIncrement
+
+	questions := float64(strings.Count(text, "?"))
+	if questions > 1 {
+		emphasis += math.Min(questions*questionIncrement, 0.96)
+	}
+
+	return emphasis
+}
+
+func isNegation(term string) bool {
+	if strings.HasSuffix(term, "n't") || strings.HasSuffix(term, "n’t") {
+		return true
+	}
+	return sentimentNegations[strings.NewReplacer("'", "", "’", "").Replace(term)]
+}
+
+// hasMixedCapitalization reports whether some but not all words are written
+// in capitals, in which case the capitalized ones are emphasized.
+func hasMixedCapitalization(tokens []Token) bool {
+	caps := 0
+	words := 0
+	for _, token := range tokens {
+		if token.Kind != TokenWord {
+			continue
+		}
+		words++
+		if isAllCaps(token.Text) {
+			caps++
+		}
+	}
+	return caps > 0 && caps < words
+}
+
+func isAllCaps(word string) bool {
+	letters := 0
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			if !unicode.IsUpper(r) {
+				return false
+			}
+			letters++
+		}
+	}
+	return letters > 1
+}
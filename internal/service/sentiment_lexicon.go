@@ -0,0 +1,94 @@
+package service
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:embed data/sentiment/en.txt
+var sentimentFiles embed.FS
+
+// SentimentLexicon maps case-folded words and emoji to their valence, from -4
+// (most negative) to 4 (most positive).
+type SentimentLexicon struct {
+	valences map[string]float64
+}
+
+var (
+	sentimentLexiconOnce sync.Once
+	sentimentLexicon     *SentimentLexicon
+)
+
+// DefaultSentimentLexicon returns the embedded English lexicon. The lexicon is
+// built into the binary, so failing to read it is a programming error and
+// panics.
+func DefaultSentimentLexicon() *SentimentLexicon {
+	sentimentLexiconOnce.Do(func() {
+		file, err := sentimentFiles.Open("data/sentiment/en.txt")
+		if err != nil {
+			panic(fmt.Sprintf("embedded sentiment lexicon: %v", err))
+		}
+		lexicon, err := parseSentimentLexicon(file)
+		_ = file.Close()
+		if err != nil {
+			panic(fmt.Sprintf("embedded sentiment lexicon: %v", err))
+		}
+		sentimentLexicon = lexicon
+	})
+	return sentimentLexicon
+}
+
+// LoadSentimentLexicon reads a lexicon file that replaces the embedded one.
+// Every line holds a word or emoji and its valence separated by whitespace;
+// blank lines and lines starting with "#" are ignored.
+func LoadSentimentLexicon(path string) (*SentimentLexicon, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sentiment lexicon: %w", err)
+	}
+	defer file.Close()
+
+	lexicon, err := parseSentimentLexicon(file)
+	if err != nil {
+		return nil, fmt.Errorf("sentiment lexicon %s: %w", path, err)
+	}
+	return lexicon, nil
+}
+
+func parseSentimentLexicon(r io.Reader) (*SentimentLexicon, error) {
+	lexicon := &SentimentLexicon{valences: make(map[string]float64)}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected a term and a valence", line)
+		}
+		valence, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid valence %q", line, fields[1])
+		}
+
+		lexicon.valences[foldTerm(fields[0])] = valence
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return lexicon, nil
+}
+
+func (l *SentimentLexicon) valence(term string) float64 {
+	return l.valences[term]
+}
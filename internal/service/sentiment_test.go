@@ -0,0 +1,83 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"vm-chan/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func analyzeSentiment(t *testing.T, text string) *domain.SentimentResult {
+	t.Helper()
+
+	return analyzeDoc[*domain.SentimentResult](t, NewSentimentAnalyzer(nil), text, "")
+}
+
+func TestSentimentAnalyzer(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{name: "Positive", text: "The support team was helpful and the product is great.", expected: "positive"},
+		{name: "Negative", text: "The update is terrible and the app keeps crashing.", expected: "negative"},
+		{name: "Neutral", text: "The meeting is on Thursday at the office.", expected: "neutral"},
+		{name: "Negation", text: "The service was not good.", expected: "negative"},
+		{name: "Contracted negation", text: "I don't like it.", expected: "negative"},
+		{name: "Emoji", text: "See you tomorrow 😍", expected: "positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := analyzeSentiment(t, tt.text)
+
+			assert.Equal(t, tt.expected, result.Label)
+			assert.InDelta(t, 1, result.Positive+result.Negative+result.Neutral, 0.001)
+		})
+	}
+}
+
+func TestSentimentAnalyzer_Emphasis(t *testing.T) {
+	plain := analyzeSentiment(t, "The food was good.")
+	boosted := analyzeSentiment(t, "The food was very good.")
+	exclaimed := analyzeSentiment(t, "The food was good!!!")
+	capitalized := analyzeSentiment(t, "The food was GOOD.")
+	dampened := analyzeSentiment(t, "The food was slightly good.")
+
+	assert.Greater(t, boosted.Compound, plain.Compound)
+	assert.Greater(t, exclaimed.Compound, plain.Compound)
+	assert.Greater(t, capitalized.Compound, plain.Compound)
+	assert.Less(t, dampened.Compound, plain.Compound)
+}
+
+func TestSentimentAnalyzer_Sentences(t *testing.T) {
+	result := analyzeSentiment(t, "I love the new design. The checkout is broken, but support was excellent.")
+
+	require.Len(t, result.Sentences, 2)
+	assert.Equal(t, "positive", result.Sentences[0].Label)
+	assert.Equal(t, 0, result.Sentences[0].Start)
+	assert.Equal(t, 22, result.Sentences[0].End)
+	assert.Equal(t, "positive", result.Sentences[1].Label, "the clause after but dominates")
+	assert.Equal(t, "positive", result.Label)
+}
+
+func TestSentimentAnalyzer_EmptyText(t *testing.T) {
+	result := analyzeSentiment(t, "")
+
+	assert.Equal(t, "neutral", result.Label)
+	assert.Zero(t, result.Compound)
+	assert.Empty(t, result.Sentences)
+}
+
+func TestParseSentimentLexicon(t *testing.T) {
+	lexicon, err := parseSentimentLexicon(strings.NewReader("# custom\nGroovy\t3\n\nmeh -1.5\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, lexicon.valence("groovy"))
+	assert.Equal(t, -1.5, lexicon.valence("meh"))
+
+	_, err = parseSentimentLexicon(strings.NewReader("groovy\tvery\n"))
+	assert.ErrorContains(t, err, "line 1")
+}
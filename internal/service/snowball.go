@@ -0,0 +1,191 @@
+package service
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// snowballWord is a word being stemmed by one of the Snowball algorithms
+// (https://snowballstem.org/algorithms/). Regions are offsets in runes and
+// are computed on the word before any suffix is removed, as the algorithms
+// require.
+type snowballWord struct {
+	runes  []rune
+	vowels string
+}
+
+func newSnowballWord(word, vowels string) *snowballWord {
+	return &snowballWord{runes: []rune(word), vowels: vowels}
+}
+
+func (w *snowballWord) String() string {
+	return string(w.runes)
+}
+
+func (w *snowballWord) isVowel(i int) bool {
+	return i >= 0 && i < len(w.runes) && strings.ContainsRune(w.vowels, w.runes[i])
+}
+
+// region returns the position after the first non-vowel following a vowel at
+// or after from, or the length of the word if there is none. R1 is
+// region(0) and R2 is region(R1).
+func (w *snowballWord) region(from int) int {
+	for i := from + 1; i < len(w.runes); i++ {
+		if !w.isVowel(i) && w.isVowel(i-1) {
+			return i + 1
+		}
+	}
+	return len(w.runes)
+}
+
+// regions returns R1, moved right so that at least three letters precede it
+// as the Germanic and Scandinavian algorithms require when minimum is 3, and
+// R2.
+func (w *snowballWord) regions(minimum int) (int, int) {
+	r1 := w.region(0)
+	r2 := w.region(r1)
+	if r1 < minimum {
+		r1 = min(minimum, len(w.runes))
+	}
+	return r1, r2
+}
+
+// romanceRV returns the RV region of the Spanish, Portuguese and Italian
+// algorithms.
+func (w *snowballWord) romanceRV() int {
+	n := len(w.runes)
+	if n < 2 {
+		return n
+	}
+
+	if !w.isVowel(1) {
+		for i := 2; i < n; i++ {
+			if w.isVowel(i) {
+				return i + 1
+			}
+		}
+		return n
+	}
+	if w.isVowel(0) {
+		for i := 2; i < n; i++ {
+			if !w.isVowel(i) {
+				return i + 1
+			}
+		}
+		return n
+	}
+	return min(3, n)
+}
+
+func (w *snowballWord) hasSuffix(suffix string) bool {
+	return strings.HasSuffix(string(w.runes), suffix)
+}
+
+// start returns the position at which suffix begins, assuming the word ends
+// with it.
+func (w *snowballWord) start(suffix string) int {
+	return len(w.runes) - utf8.RuneCountInString(suffix)
+}
+
+// in reports whether the word ends with suffix and the suffix lies within the
+// region starting at region.
+func (w *snowballWord) in(suffix string, region int) bool {
+	return w.hasSuffix(suffix) && w.start(suffix) >= region
+}
+
+// longest returns the longest of suffixes the word ends with, or "".
+func (w *snowballWord) longest(suffixes []string) string {
+	word := string(w.runes)
+	best := ""
+	for _, suffix := range suffixes {
+		if len(suffix) > len(best) && strings.HasSuffix(word, suffix) {
+			best = suffix
+		}
+	}
+	return best
+}
+
+// longestIn returns the longest of suffixes the word ends with that lies
+// within the region starting at region, or "".
+func (w *snowballWord) longestIn(suffixes []string, region int) string {
+	best := ""
+	for _, suffix := range suffixes {
+		if len(suffix) > len(best) && w.in(suffix, region) {
+			best = suffix
+		}
+	}
+	return best
+}
+
+func (w *snowballWord) replace(suffix, replacement string) {
+	w.runes = append(w.runes[:w.start(suffix)], []rune(replacement)...)
+}
+
+func (w *snowballWord) remove(suffix string) {
+	w.replace(suffix, "")
+}
+
+// removeIn removes suffix if the word ends with it within region, and
+// reports whether it did.
+func (w *snowballWord) removeIn(suffix string, region int) bool {
+	if !w.in(suffix, region) {
+		return false
+	}
+	w.remove(suffix)
+	return true
+}
+
+// precededBy reports whether the word ends with suffix and the rune before
+// it is one of chars.
+func (w *snowballWord) precededBy(suffix, chars string) bool {
+	i := w.start(suffix) - 1
+	return w.hasSuffix(suffix) && i >= 0 && strings.ContainsRune(chars, w.runes[i])
+}
+
+// follows reports whether the word ends with suffix directly preceded by
+// preceding.
+func (w *snowballWord) follows(suffix, preceding string) bool {
+	return w.hasSuffix(preceding + suffix)
+}
+
+// mapRunes replaces every rune found in from by the rune at the same
+// position in to.
+func (w *snowballWord) mapRunes(from, to string) {
+	target := []rune(to)
+	for i, r := range w.runes {
+		if j := strings.IndexRune(from, r); j >= 0 {
+			w.runes[i] = target[utf8.RuneCountInString(from[:j])]
+		}
+	}
+}
+
+func suffixList(suffixes string) []string {
+	return strings.Fields(suffixes)
+}
+
+// suffixGroups maps every suffix of a step to the group deciding how it is
+// removed, so that the longest suffix can be found across all groups.
+type suffixGroups map[string]int
+
+func newSuffixGroups(groups ...string) suffixGroups {
+	result := make(suffixGroups)
+	for i, group := range groups {
+		for _, suffix := range suffixList(group) {
+			result[suffix] = i + 1
+		}
+	}
+	return result
+}
+
+// match returns the longest suffix of the word found in groups that lies
+// within the region starting at region and its group, or 0 when there is
+// none.
+func (g suffixGroups) match(w *snowballWord, region int) (string, int) {
+	best := ""
+	for suffix := range g {
+		if len(suffix) > len(best) && w.in(suffix, region) {
+			best = suffix
+		}
+	}
+	return best, g[best]
+}
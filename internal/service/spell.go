@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"vm-chan/internal/domain"
+)
+
+//go:embed data/spelling/*
+var spellingFiles embed.FS
+
+const (
+	maxSpellSuggestions = 5
+	// maxSuggestedMisspellings bounds the distinct misspelled words given
+	// suggestions, as looking up the words two edits away is expensive.
+	maxSuggestedMisspellings = 20
+)
+
+var (
+	spellDictionariesOnce sync.Once
+	spellDictionaries     map[string]*HunspellDictionary
+)
+
+// defaultSpellDictionaries returns the embedded dictionaries keyed by
+// language. The dictionaries are built into the binary, so a broken one is a
+// programming error and panics.
+func defaultSpellDictionaries() map[string]*HunspellDictionary {
+	spellDictionariesOnce.Do(func() {
+		spellDictionaries = make(map[string]*HunspellDictionary)
+
+		entries, err := spellingFiles.ReadDir("data/spelling")
+		if err != nil {
+			panic(fmt.Sprintf("embedded spell dictionaries: %v", err))
+		}
+		for _, entry := range entries {
+			name, ok := strings.CutSuffix(entry.Name(), ".aff")
+			if !ok {
+				continue
+			}
+			dictionary, err := loadEmbeddedHunspell(name)
+			if err != nil {
+				panic(fmt.Sprintf("embedded spell dictionary %s: %v", name, err))
+			}
+			spellDictionaries[primaryLanguage(name)] = dictionary
+		}
+	})
+	return spellDictionaries
+}
+
+func loadEmbeddedHunspell(name string) (*HunspellDictionary, error) {
+	aff, err := spellingFiles.Open("data/spelling/" + name + ".aff")
+	if err != nil {
+		return nil, err
+	}
+	dic, err := spellingFiles.Open("data/spelling/" + name + ".dic")
+	if err != nil {
+		_ = aff.Close()
+		return nil, err
+	}
+
+	dictionary, err := ParseHunspell(aff, dic)
+	_ = aff.Close()
+	_ = dic.Close()
+	return dictionary, err
+}
+
+// LoadSpellDictionaries reads the Hunspell dictionaries of a directory, each
+// a pair of .aff and .dic files named after their language, such as
+// de_DE.aff and de_DE.dic. They are added to the embedded dictionaries,
+// replacing them for the same language.
+func LoadSpellDictionaries(dir string) (map[string]*HunspellDictionary, error) {
+	affPaths, err := filepath.Glob(filepath.Join(dir, "*.aff"))
+	if err != nil {
+		return nil, err
+	}
+	if len(affPaths) == 0 {
+		return nil, fmt.Errorf("no .aff files in spell dictionary directory %s", dir)
+	}
+
+	dictionaries := make(map[string]*HunspellDictionary)
+	for language, dictionary := range defaultSpellDictionaries() {
+		dictionaries[language] = dictionary
+	}
+	for _, affPath := range affPaths {
+		dicPath := strings.TrimSuffix(affPath, ".aff") + ".dic"
+		dictionary, err := loadHunspellFiles(affPath, dicPath)
+		if err != nil {
+			return nil, fmt.Errorf("spell dictionary %s: %w", affPath, err)
+		}
+		dictionaries[primaryLanguage(strings.TrimSuffix(filepath.Base(affPath), ".aff"))] = dictionary
+	}
+	return dictionaries, nil
+}
+
+func loadHunspellFiles(affPath, dicPath string) (*HunspellDictionary, error) {
+	aff, err := os.Open(affPath)
+	if err != nil {
+		return nil, err
+	}
+	dic, err := os.Open(dicPath)
+	if err != nil {
+		_ = aff.Close()
+		return nil, err
+	}
+
+	dictionary, err := ParseHunspell(aff, dic)
+	_ = aff.Close()
+	_ = dic.Close()
+	return dictionary, err
+}
+
+type spellAnalyzer struct {
+	dictionaries map[string]*HunspellDictionary
+	customWords  domain.DictionaryRepository
+}
+
+// NewSpellAnalyzer returns an analyzer checking words against the dictionary
+// of the document language, or against the embedded dictionaries when
+// dictionaries is nil. Words of the custom dictionary of the user found in
+// the request context are accepted too; customWords may be nil.
+func NewSpellAnalyzer(dictionaries map[string]*HunspellDictionary, customWords domain.DictionaryRepository) Analyzer {
+	if dictionaries == nil {
+		dictionaries = defaultSpellDictionaries()
+	}
+	return &spellAnalyzer{dictionaries: dictionaries, customWords: customWords}
+}
+
+func (a *spellAnalyzer) Name() string {
+	return "spell"
+}
+
+func (a *spellAnalyzer) Version() string {
+	return "1.0.0"
+}
+
+func (a *spellAnalyzer) Description() string {
+	return "Misspelled words with offsets and ranked suggestions, checked against Hunspell dictionaries " +
+		"and the custom dictionary of the user"
+}
+
+func (a *spellAnalyzer) Analyze(ctx context.Context, doc *Document) (interface{}, error) {
+	dictionary, ok := a.dictionaries[doc.Language]
+	if !ok {
+		languages := make([]string, 0, len(a.dictionaries))
+		for language := range a.dictionaries {
+			languages = append(languages, language)
+		}
+		sort.Strings(languages)
+		return nil, fmt.Errorf("%w: no spell dictionary for language %q, supported languages: %s",
+			domain.ErrInvalidInput, doc.Language, strings.Join(languages, ", "))
+	}
+
+	accepted, err := a.userWords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rank func(string) float64
+	if table := defaultIDFTable(doc.Language); table != nil {
+		rank = table.weight
+	}
+
+	skipped := spellSkippedSpans(doc)
+	terms := doc.terms()
+	// Suggestions are looked up once per distinct word, and only for the
+	// first maxSuggestedMisspellings of them.
+	suggestions := make(map[string][]string)
+	result := &domain.SpellResult{Language: doc.Language, Misspellings: []domain.Misspelling{}}
+	for i, token := range doc.Tokens() {
+		if token.Kind != TokenWord || hasDigit(token.Text) || accepted[terms[i]] || skipped(token) {
+			continue
+		}
+		if dictionary.Check(token.Text) {
+			continue
+		}
+		suggested, ok := suggestions[token.Text]
+		if !ok {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			suggested = []string{}
+			if len(suggestions) < maxSuggestedMisspellings {
+				if suggested, err = dictionary.Suggest(ctx, token.Text, maxSpellSuggestions, rank); err != nil {
+					return nil, err
+				}
+			}
+			suggestions[token.Text] = suggested
+		}
+		result.Misspellings = append(result.Misspellings, domain.Misspelling{
+			Text:        token.Text,
+			Start:       token.Start,
+			End:         token.End,
+			Suggestions: suggested,
+		})
+	}
+
+	return result, nil
+}
+
+func (a *spellAnalyzer) userWords(ctx context.Context) (map[string]bool, error) {
+	user, ok := domain.UserFromContext(ctx)
+	if !ok || a.customWords == nil {
+		return nil, nil
+	}
+
+	words, err := a.customWords.Words(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("custom dictionary: %w", err)
+	}
+	accepted := make(map[string]bool, len(words))
+	for _, word := range words {
+		accepted[foldTerm(word)] = true
+	}
+	return accepted, nil
+}
+
+// spellSkippedSpans returns a function reporting whether a token lies in a
+// URL, email address, mention or hashtag, whose words are not checked.
+func spellSkippedSpans(doc *Document) func(Token) bool {
+	var spans []domain.Entity
+	for _, entity := range extractEntities(doc.Text, doc.Language) {
+		switch entity.Type {
+		case entityURL, entityEmail, entityMention, entityHashtag:
+			spans = append(spans, entity)
+		}
+	}
+	return func(token Token) bool {
+		for _, span := range spans {
+			if token.Start < span.End && token.End > span.Start {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func hasDigit(word string) bool {
+	return strings.IndexFunc(word, unicode.IsDigit) >= 0
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"vm-chan/internal/domain"
+	"vm-chan/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestParseHunspell(t *testing.T) {
+	aff := `SET UTF-8
+TRY esianrtolcdugmphbyfvkwzESIANRTOLCDUGMPHBYFVKWZ
+FORBIDDENWORD !
+
+PFX A Y 1
+PFX A 0 re .
+
+SFX S Y 3
+SFX S y ies [^aeiou]y
+SFX S 0 s [aeiou]y
+SFX S 0 s [^y]
+
+SFX D N 2
+SFX D 0 ed [^e]
+SFX D 0 d e
+`
+	dic := `5
+city/S
+play/ASD
+bake/D
+Paris
+bakeds/!
+`
+	dictionary, err := ParseHunspell(strings.NewReader(aff), strings.NewReader(dic))
+	require.NoError(t, err)
+
+	for _, word := range []string{"city", "cities", "plays", "replay", "replays", "played", "baked", "Paris", "PARIS", "City", "CITIES"} {
+		assert.True(t, dictionary.Check(word), word)
+	}
+	for _, word := range []string{"citys", "replayed", "paris", "bakeds", "rebaked"} {
+		assert.False(t, dictionary.Check(word), word)
+	}
+
+	_, err = ParseHunspell(strings.NewReader("SFX S Y 1\nSFX S 0 s [a\n"), strings.NewReader("1\ncat/S\n"))
+	assert.Error(t, err)
+}
+
+func TestHunspellDictionary_Suggest(t *testing.T) {
+	dictionary := defaultSpellDictionaries()["en"]
+	require.NotNil(t, dictionary)
+	rank := defaultIDFTable("en").weight
+
+	tests := []struct {
+		word       string
+		suggestion string
+	}{
+		{"recieve", "receive"},
+		{"teh", "the"},
+		{"definately", "definitely"},
+		{"occured", "occurred"},
+		{"alot", "a lot"},
+		{"WROLD", "WORLD"},
+		{"Speling", "Spelling"},
+	}
+	for _, test := range tests {
+		t.Run(test.word, func(t *testing.T) {
+			assert.False(t, dictionary.Check(test.word))
+			suggestions, err := dictionary.Suggest(context.Background(), test.word, maxSpellSuggestions, rank)
+			require.NoError(t, err)
+			require.NotEmpty(t, suggestions)
+			assert.Equal(t, test.suggestion, suggestions[0])
+		})
+	}
+
+	t.Run("Bounded lookups", func(t *testing.T) {
+		suggestions, err := dictionary.Suggest(context.Background(), strings.Repeat("qwertyuiop", 12), maxSpellSuggestions, rank)
+		require.NoError(t, err)
+		assert.Empty(t, suggestions, "words longer than maxSuggestedRunes get no suggestions")
+
+		suggestions, err = dictionary.Suggest(context.Background(), "irresponsbilty", maxSpellSuggestions, rank)
+		require.NoError(t, err)
+		assert.Empty(t, suggestions, "long words are not edited twice")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = dictionary.Suggest(ctx, "xqzvbnmwk", maxSpellSuggestions, rank)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	for _, word := range []string{"unhappily", "restarted", "churches", "travelled", "Monday's", "NASA", "don’t"} {
+		assert.True(t, dictionary.Check(word), word)
+	}
+}
+
+func TestSpellAnalyzer(t *testing.T) {
+	words := repository.NewDictionaryRepository(zap.NewNop())
+	require.NoError(t, words.AddWords(context.Background(), "1", []string{"Kubernetes"}))
+	analyzer := NewSpellAnalyzer(nil, words)
+
+	analyze := func(t *testing.T, ctx context.Context, text string) *domain.SpellResult {
+		return analyzeDocument[*domain.SpellResult](t, ctx, analyzer, testDocument(text, "en"))
+	}
+
+	t.Run("Misspellings with offsets", func(t *testing.T) {
+		result := analyze(t, context.Background(), "We recieve 3 parcels a day.")
+
+		require.Len(t, result.Misspellings, 1)
+		misspelling := result.Misspellings[0]
+		assert.Equal(t, "recieve", misspelling.Text)
+		assert.Equal(t, 3, misspelling.Start)
+		assert.Equal(t, 10, misspelling.End)
+		assert.Equal(t, "receive", misspelling.Suggestions[0])
+	})
+
+	t.Run("Custom words of the user", func(t *testing.T) {
+		text := "We deploy on kubernetes."
+
+		result := analyze(t, context.Background(), text)
+		require.Len(t, result.Misspellings, 1)
+
+		ctx := domain.WithUser(context.Background(), &domain.User{ID: "1"})
+		result = analyze(t, ctx, text)
+		assert.Empty(t, result.Misspellings)
+
+		ctx = domain.WithUser(context.Background(), &domain.User{ID: "2"})
+		result = analyze(t, ctx, text)
+		assert.Len(t, result.Misspellings, 1)
+	})
+
+	t.Run("URLs, emails, mentions and tokens with digits are skipped", func(t *testing.T) {
+		result := analyze(t, context.Background(),
+			"Mail zqxj@wrkspc.io or see https://qwzx.example.com/abcd for the v2x build, @jdoex #blorp")
+		assert.Empty(t, result.Misspellings)
+	})
+
+	t.Run("Suggestions are looked up once per word and capped", func(t *testing.T) {
+		words := []string{"recieve", "recieve"}
+		for i := 0; i < maxSuggestedMisspellings+5; i++ {
+			words = append(words, "qz"+strings.Repeat("x", i+1))
+		}
+
+		result := analyze(t, context.Background(), strings.Join(words, " "))
+		require.Len(t, result.Misspellings, len(words))
+
+		assert.Equal(t, result.Misspellings[0].Suggestions, result.Misspellings[1].Suggestions)
+		assert.Contains(t, result.Misspellings[0].Suggestions, "receive")
+		last := result.Misspellings[len(words)-1]
+		assert.NotNil(t, last.Suggestions)
+		assert.Empty(t, last.Suggestions)
+	})
+
+	t.Run("Unsupported language", func(t *testing.T) {
+		_, err := analyzer.Analyze(context.Background(), testDocument("Ein kurzer Satz.", "de"))
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+}
+
+func TestDictionaryService(t *testing.T) {
+	service := NewDictionaryService(repository.NewDictionaryRepository(zap.NewNop()), NewTokenizer(), zap.NewNop())
+	ctx := domain.WithUser(context.Background(), &domain.User{ID: "1"})
+
+	response, err := service.AddWords(ctx, &domain.DictionaryRequest{Words: []string{"Kubernetes", "gRPC", "kubernetes"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gRPC", "kubernetes"}, response.Words)
+
+	response, err = service.RemoveWord(ctx, "GRPC")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kubernetes"}, response.Words)
+
+	_, err = service.RemoveWord(ctx, "gRPC")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	for _, word := range []string{"two words", "", strings.Repeat("a", maxCustomWordRunes+1)} {
+		_, err = service.AddWords(ctx, &domain.DictionaryRequest{Words: []string{word}})
+		assert.ErrorIs(t, err, domain.ErrInvalidInput, word)
+	}
+
+	_, err = service.Words(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,226 @@
+package service
+
+import "strings"
+
+const englishVowels = "aeiouy"
+
+var (
+	englishExceptions = map[string]string{
+		"skis": "ski", "skies": "sky", "dying": "die", "lying": "lie", "tying": "tie",
+		"idly": "idl", "gently": "gentl", "ugly": "ugli", "early": "earli", "only": "onli", "singly": "singl",
+		"sky": "sky", "news": "news", "howe": "howe", "atlas": "atlas", "cosmos": "cosmos", "bias": "bias",
+		"andes": "andes",
+	}
+	englishPostStep1aExceptions = map[string]bool{
+		"inning": true, "outing": true, "canning": true, "herring": true, "earring": true,
+		"proceed": true, "exceed": true, "succeed": true,
+	}
+	englishStep1bSuffixes = suffixList("eed eedly ed edly ing ingly")
+	englishStep2Suffixes  = map[string]string{
+		"tional": "tion", "enci": "ence", "anci": "ance", "abli": "able", "entli": "ent",
+		"izer": "ize", "ization": "ize", "ational": "ate", "ation": "ate", "ator": "ate",
+		"alism": "al", "aliti": "al", "alli": "al", "fulness": "ful", "ousli": "ous", "ousness": "ous",
+		"iveness": "ive", "iviti": "ive", "biliti": "ble", "bli": "ble", "ogi": "og", "fulli": "ful",
+		"lessli": "less", "li": "",
+	}
+	englishStep3Suffixes = map[string]string{
+		"tional": "tion", "ational": "ate", "alize": "al", "icate": "ic", "iciti": "ic",
+		"ical": "ic", "ful": "", "ness": "", "ative": "",
+	}
+	englishStep4Suffixes = suffixList("al ance ence er ic able ible ant ement ment ent ism ate iti ous ive ize ion")
+)
+
+// stemEnglish implements the English (Porter2) Snowball stemmer.
+func stemEnglish(word string) string {
+	if len([]rune(word)) <= 2 {
+		return word
+	}
+	if stem, ok := englishExceptions[word]; ok {
+		return stem
+	}
+
+	w := newSnowballWord(strings.TrimPrefix(word, "'"), englishVowels)
+	for i, r := range w.runes {
+		if r == 'y' && (i == 0 || w.isVowel(i-1)) {
+			w.runes[i] = 'Y'
+		}
+	}
+
+	r1 := w.region(0)
+	for _, prefix := range []string{"gener", "commun", "arsen"} {
+		if strings.HasPrefix(w.String(), prefix) {
+			r1 = len(prefix)
+		}
+	}
+	r2 := w.region(r1)
+
+	englishStep0(w)
+	englishStep1a(w)
+	if englishPostStep1aExceptions[w.String()] {
+		return w.String()
+	}
+	englishStep1b(w, r1)
+	englishStep1c(w)
+	englishStep2(w, r1)
+	englishStep3(w, r1, r2)
+	englishStep4(w, r2)
+	englishStep5(w, r1, r2)
+
+	return strings.ReplaceAll(w.String(), "Y", "y")
+}
+
+func englishStep0(w *snowballWord) {
+	if suffix := w.longest(suffixList("' 's 's'")); suffix != "" {
+		w.remove(suffix)
+	}
+}
+
+func englishStep1a(w *snowballWord) {
+	switch suffix := w.longest(suffixList("sses ied ies s us ss")); suffix {
+	case "sses":
+		w.replace(suffix, "ss")
+	case "ied", "ies":
+		if w.start(suffix) > 1 {
+			w.replace(suffix, "i")
+		} else {
+			w.replace(suffix, "ie")
+		}
+	case "s":
+		for i := 0; i < w.start(suffix)-1; i++ {
+			if w.isVowel(i) {
+				w.remove(suffix)
+				return
+			}
+		}
+	}
+}
+
+func englishStep1b(w *snowballWord, r1 int) {
+	suffix := w.longest(englishStep1bSuffixes)
+	switch suffix {
+	case "":
+		return
+	case "eed", "eedly":
+		if w.in(suffix, r1) {
+			w.replace(suffix, "ee")
+		}
+		return
+	}
+
+	hasVowel := false
+	for i := 0; i < w.start(suffix); i++ {
+		if w.isVowel(i) {
+			hasVowel = true
+			break
+		}
+	}
+	if !hasVowel {
+		return
+	}
+	w.remove(suffix)
+
+	switch {
+	case w.hasSuffix("at") || w.hasSuffix("bl") || w.hasSuffix("iz"):
+		w.runes = append(w.runes, 'e')
+	case w.longest(suffixList("bb dd ff gg mm nn pp rr tt")) != "":
+		w.runes = w.runes[:len(w.runes)-1]
+	case englishShortWord(w, r1):
+		w.runes = append(w.runes, 'e')
+	}
+}
+
+func englishStep1c(w *snowballWord) {
+	n := len(w.runes)
+	if n > 2 && (w.runes[n-1] == 'y' || w.runes[n-1] == 'Y') && !w.isVowel(n-2) {
+		w.runes[n-1] = 'i'
+	}
+}
+
+func englishStep2(w *snowballWord, r1 int) {
+	suffix := englishLongestMapped(w, englishStep2Suffixes)
+	if suffix == "" || !w.in(suffix, r1) {
+		return
+	}
+
+	switch suffix {
+	case "ogi":
+		if w.precededBy(suffix, "l") {
+			w.replace(suffix, "og")
+		}
+	case "li":
+		if w.precededBy(suffix, "cdeghkmnrt") {
+			w.remove(suffix)
+		}
+	default:
+		w.replace(suffix, englishStep2Suffixes[suffix])
+	}
+}
+
+func englishStep3(w *snowballWord, r1, r2 int) {
+	suffix := englishLongestMapped(w, englishStep3Suffixes)
+	if suffix == "" || !w.in(suffix, r1) {
+		return
+	}
+	if suffix == "ative" && !w.in(suffix, r2) {
+		return
+	}
+	w.replace(suffix, englishStep3Suffixes[suffix])
+}
+
+func englishStep4(w *snowballWord, r2 int) {
+	suffix := w.longest(englishStep4Suffixes)
+	if suffix == "" || !w.in(suffix, r2) {
+		return
+	}
+	if suffix == "ion" && !w.precededBy(suffix, "st") {
+		return
+	}
+	w.remove(suffix)
+}
+
+func englishStep5(w *snowballWord, r1, r2 int) {
+	switch {
+	case w.hasSuffix("e"):
+		if w.in("e", r2) {
+			w.remove("e")
+			return
+		}
+		if w.in("e", r1) {
+			stem := &snowballWord{runes: w.runes[:len(w.runes)-1], vowels: w.vowels}
+			if !englishEndsWithShortSyllable(stem) {
+				w.remove("e")
+			}
+		}
+	case w.hasSuffix("ll") && w.in("l", r2):
+		w.remove("l")
+	}
+}
+
+func englishLongestMapped(w *snowballWord, suffixes map[string]string) string {
+	best := ""
+	for suffix := range suffixes {
+		if len(suffix) > len(best) && w.hasSuffix(suffix) {
+			best = suffix
+		}
+	}
+	return best
+}
+
+// englishEndsWithShortSyllable reports whether the word ends with a vowel
+// followed by a non-vowel other than w, x or Y and preceded by a non-vowel, or
+// consists of a vowel followed by a non-vowel.
+func englishEndsWithShortSyllable(w *snowballWord) bool {
+	n := len(w.runes)
+	if n == 2 {
+		return w.isVowel(0) && !w.isVowel(1)
+	}
+	if n < 3 {
+		return false
+	}
+	last := w.runes[n-1]
+	return !w.isVowel(n-3) && w.isVowel(n-2) && !w.isVowel(n-1) && last != 'w' && last != 'x' && last != 'Y'
+}
+
+func englishShortWord(w *snowballWord, r1 int) bool {
+	return r1 >= len(w.runes) && englishEndsWithShortSyllable(w)
+}
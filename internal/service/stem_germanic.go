@@ -0,0 +1,164 @@
+package service
+
+import "strings"
+
+const (
+	germanVowels = "aeiouyäöü"
+	dutchVowels  = "aeiouyè"
+)
+
+// stemGerman implements the German Snowball stemmer.
+func stemGerman(word string) string {
+	w := newSnowballWord(strings.ReplaceAll(word, "ß", "ss"), germanVowels)
+	for i := 1; i+1 < len(w.runes); i++ {
+		if (w.runes[i] == 'u' || w.runes[i] == 'y') && w.isVowel(i-1) && w.isVowel(i+1) {
+			w.runes[i] = w.runes[i] - 'a' + 'A'
+		}
+	}
+	r1, r2 := w.regions(3)
+
+	switch suffix := w.longest(suffixList("em ern er e en es s")); suffix {
+	case "em", "ern", "er":
+		w.removeIn(suffix, r1)
+	case "e", "en", "es":
+		if w.removeIn(suffix, r1) && w.hasSuffix("niss") {
+			w.remove("s")
+		}
+	case "s":
+		if w.precededBy(suffix, "bdfghklmnrt") {
+			w.removeIn(suffix, r1)
+		}
+	}
+
+	switch suffix := w.longest(suffixList("en er est st")); suffix {
+	case "en", "er", "est":
+		w.removeIn(suffix, r1)
+	case "st":
+		if w.precededBy(suffix, "bdfghklmnt") && w.start(suffix) >= 4 {
+			w.removeIn(suffix, r1)
+		}
+	}
+
+	switch suffix := w.longest(suffixList("end ung ig ik isch lich heit keit")); suffix {
+	case "end", "ung":
+		if w.removeIn(suffix, r2) && w.in("ig", r2) && !w.precededBy("ig", "e") {
+			w.remove("ig")
+		}
+	case "ig", "ik", "isch":
+		if !w.precededBy(suffix, "e") {
+			w.removeIn(suffix, r2)
+		}
+	case "lich", "heit":
+		if w.removeIn(suffix, r2) {
+			if ending := w.longest(suffixList("er en")); ending != "" {
+				w.removeIn(ending, r1)
+			}
+		}
+	case "keit":
+		if w.removeIn(suffix, r2) {
+			if ending := w.longest(suffixList("lich ig")); ending != "" {
+				w.removeIn(ending, r2)
+			}
+		}
+	}
+
+	w.mapRunes("UYäöü", "uyaou")
+	return w.String()
+}
+
+// stemDutch implements the Dutch Snowball stemmer.
+func stemDutch(word string) string {
+	w := newSnowballWord(word, dutchVowels)
+	w.mapRunes("äëïöüáéíóú", "aeiouaeiou")
+	for i, r := range w.runes {
+		switch {
+		case r == 'y' && (i == 0 || w.isVowel(i-1)):
+			w.runes[i] = 'Y'
+		case r == 'i' && w.isVowel(i-1) && i+1 < len(w.runes) && w.isVowel(i+1):
+			w.runes[i] = 'I'
+		}
+	}
+	r1, r2 := w.regions(3)
+
+	switch suffix := w.longest(suffixList("heden ene en se s")); suffix {
+	case "heden":
+		if w.in(suffix, r1) {
+			w.replace(suffix, "heid")
+		}
+	case "en", "ene":
+		dutchRemoveEnEnding(w, suffix, r1)
+	case "s", "se":
+		if w.in(suffix, r1) && !w.isVowel(w.start(suffix)-1) && !w.precededBy(suffix, "j") {
+			w.remove(suffix)
+		}
+	}
+
+	eFound := dutchRemoveE(w, r1)
+
+	if w.in("heid", r2) && !w.precededBy("heid", "c") {
+		w.remove("heid")
+		if w.hasSuffix("en") {
+			dutchRemoveEnEnding(w, "en", r1)
+		}
+	}
+
+	switch suffix := w.longest(suffixList("end ing ig lijk baar bar")); suffix {
+	case "end", "ing":
+		if w.removeIn(suffix, r2) {
+			if w.in("ig", r2) && !w.precededBy("ig", "e") {
+				w.remove("ig")
+			} else {
+				dutchUndouble(w)
+			}
+		}
+	case "ig":
+		if !w.precededBy(suffix, "e") {
+			w.removeIn(suffix, r2)
+		}
+	case "lijk":
+		if w.removeIn(suffix, r2) {
+			dutchRemoveE(w, r1)
+		}
+	case "baar":
+		w.removeIn(suffix, r2)
+	case "bar":
+		if eFound {
+			w.removeIn(suffix, r2)
+		}
+	}
+
+	n := len(w.runes)
+	if n >= 4 && !w.isVowel(n-4) && w.runes[n-3] == w.runes[n-2] && strings.ContainsRune("aeou", w.runes[n-2]) &&
+		!w.isVowel(n-1) && w.runes[n-1] != 'I' {
+		w.runes = append(w.runes[:n-2], w.runes[n-1])
+	}
+
+	w.mapRunes("IY", "iy")
+	return w.String()
+}
+
+// dutchRemoveEnEnding removes suffix when it lies in R1 and follows a
+// non-vowel that does not end "gem", then undoubles the ending.
+func dutchRemoveEnEnding(w *snowballWord, suffix string, r1 int) {
+	start := w.start(suffix)
+	if !w.in(suffix, r1) || start == 0 || w.isVowel(start-1) || strings.HasSuffix(string(w.runes[:start]), "gem") {
+		return
+	}
+	w.remove(suffix)
+	dutchUndouble(w)
+}
+
+func dutchRemoveE(w *snowballWord, r1 int) bool {
+	if !w.in("e", r1) || w.isVowel(w.start("e")-1) || w.start("e") == 0 {
+		return false
+	}
+	w.remove("e")
+	dutchUndouble(w)
+	return true
+}
+
+func dutchUndouble(w *snowballWord) {
+	if ending := w.longest(suffixList("kk dd tt")); ending != "" {
+		w.runes = w.runes[:len(w.runes)-1]
+	}
+}
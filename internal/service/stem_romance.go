@@ -0,0 +1,601 @@
+package service
+
+import "strings"
+
+const (
+	frenchVowels     = "aeiouyâàëéêèïîôûù"
+	spanishVowels    = "aeiouáéíóúü"
+	portugueseVowels = "aeiouáéíóúâêô"
+	italianVowels    = "aeiouàèìòù"
+)
+
+var (
+	frenchStandardSuffixes = newSuffixGroups(
+		"ance iqUe isme able iste eux ances iqUes ismes ables istes",
+		"atrice ateur ation atrices ateurs ations",
+		"logie logies",
+		"usion ution usions utions",
+		"ence ences",
+		"ement ements",
+		"ité ités",
+		"if ive ifs ives",
+		"eaux",
+		"aux",
+		"euse euses",
+		"issement issements",
+		"amment",
+		"emment",
+		"ment ments",
+	)
+	frenchIVerbSuffixes = suffixList("îmes ît îtes i ie ies ir ira irai iraIent irait iras irent irez iriez " +
+		"irions irons iront is issaIent issais issait issant issante issantes issants isse issent isses issez " +
+		"issiez issions issons it")
+	frenchVerbSuffixes = newSuffixGroups(
+		"ions",
+		"é ée ées és èrent er era erai eraIent erais erait eras erez eriez erions erons eront ez iez",
+		"âmes ât âtes a ai aIent ais ait ant ante antes ants as asse assent asses assiez assions",
+	)
+
+	spanishPronouns         = suffixList("me se sela selo selas selos la le lo las les los nos")
+	spanishStandardSuffixes = newSuffixGroups(
+		"anza anzas ico ica icos icas ismo ismos able ables ible ibles ista istas oso osa osos osas "+
+			"amiento amientos imiento imientos",
+		"adora ador ación adoras adores aciones ante antes ancia ancias",
+		"logía logías",
+		"ución uciones",
+		"encia encias",
+		"amente",
+		"mente",
+		"idad idades",
+		"iva ivo ivas ivos",
+	)
+	spanishYVerbSuffixes = suffixList("ya ye yan yen yeron yendo yo yó yas yes yais yamos")
+	spanishVerbSuffixes  = newSuffixGroups(
+		"en es éis emos",
+		"arían arías arán arás aríais aría aréis aríamos aremos ará aré erían erías erán erás eríais ería "+
+			"eréis eríamos eremos erá eré irían irías irán irás iríais iría iréis iríamos iremos irá iré aba ada "+
+			"ida ía ara iera ad ed id ase iese aste iste an aban ían aran ieran asen iesen aron ieron ado ido "+
+			"ando iendo ió ar er ir as abas adas idas ías aras ieras ases ieses ís áis abais íais arais ierais "+
+			"aseis ieseis asteis isteis ados idos amos ábamos íamos imos áramos iéramos iésemos ásemos",
+	)
+
+	portugueseStandardSuffixes = newSuffixGroups(
+		"eza ezas ico ica icos icas ismo ismos ável ível ista istas oso osa osos osas amento amentos imento "+
+			"imentos adora ador aça~o adoras adores aço~es ante antes ância",
+		"logia logias",
+		"ução uções",
+		"ência ências",
+		"amente",
+		"mente",
+		"idade idades",
+		"iva ivo ivas ivos",
+		"ira iras",
+	)
+	portugueseVerbSuffixes = suffixList("ada ida ia aria eria iria ará ara erá era irá ava asse esse isse aste " +
+		"este iste ei arei erei irei am iam ariam eriam iriam aram eram iram avam em arem erem irem assem essem " +
+		"issem ado ido ando endo indo ara~o era~o ira~o ar er ir as adas idas ias arias erias irias arás aras " +
+		"erás eras irás avas es ardes erdes irdes ares eres ires asses esses isses astes estes istes is ais eis " +
+		"íeis aríeis eríeis iríeis áreis éreis íreis ásseis ésseis ísseis áveis ados idos ámos amos íamos " +
+		"aríamos eríamos iríamos áramos éramos íramos ávamos emos aremos eremos iremos ássemos êssemos íssemos " +
+		"imos armos ermos irmos eu iu ou ira iras")
+
+	italianPronouns = suffixList("ci gli la le li lo mi ne si ti vi sene gliela gliele glieli glielo gliene " +
+		"mela mele meli melo mene tela tele teli telo tene cela cele celi celo cene vela vele veli velo vene")
+	italianStandardSuffixes = newSuffixGroups(
+		"anza anze ico ici ica ice iche ichi ismo ismi abile abili ibile ibili ista iste isti istà istè istì "+
+			"oso osi osa ose mente atrice atrici ante anti",
+		"azione azioni atore atori",
+		"logia logie",
+		"uzione uzioni usione usioni",
+		"enza enze",
+		"amento amenti imento imenti",
+		"amente",
+		"ità",
+		"ivo ivi iva ive",
+	)
+	italianVerbSuffixes = suffixList("ammo ando ano are arono asse assero assi assimo ata ate ati ato ava avamo " +
+		"avano avate avi avo emmo enda ende endi endo erà erai eranno ere erebbe erebbero erei eremmo eremo " +
+		"ereste eresti erete erò erono essero ete eva evamo evano evate evi evo iamo immo irà irai iranno ire " +
+		"irebbe irebbero irei iremmo iremo ireste iresti irete irò irono isca iscano isce isci isco iscono " +
+		"issero ita ite iti ito iva ivamo ivano ivate ivi ivo ar ir")
+)
+
+// stemFrench implements the French Snowball stemmer.
+func stemFrench(word string) string {
+	w := newSnowballWord(word, frenchVowels)
+	for i, r := range w.runes {
+		switch {
+		case (r == 'u' || r == 'i') && w.isVowel(i-1) && w.isVowel(i+1):
+			w.runes[i] = r - 'a' + 'A'
+		case r == 'y' && (w.isVowel(i-1) || w.isVowel(i+1)):
+			w.runes[i] = 'Y'
+		case r == 'u' && i > 0 && w.runes[i-1] == 'q':
+			w.runes[i] = 'U'
+		}
+	}
+	rv := frenchRV(w)
+	r1, r2 := w.regions(0)
+
+	if frenchStandardSuffix(w, rv, r1, r2) || frenchIVerbSuffix(w, rv) || frenchVerbSuffix(w, rv, r2) {
+		if w.hasSuffix("Y") {
+			w.replace("Y", "i")
+		} else if w.hasSuffix("ç") {
+			w.replace("ç", "c")
+		}
+	} else {
+		frenchResidualSuffix(w, rv, r2)
+	}
+
+	if w.longest(suffixList("enn onn ett ell eill")) != "" {
+		w.runes = w.runes[:len(w.runes)-1]
+	}
+
+	i := len(w.runes) - 1
+	for i >= 0 && !w.isVowel(i) {
+		i--
+	}
+	if i >= 0 && i < len(w.runes)-1 && (w.runes[i] == 'é' || w.runes[i] == 'è') {
+		w.runes[i] = 'e'
+	}
+
+	w.mapRunes("IUY", "iuy")
+	return w.String()
+}
+
+// frenchRV returns the RV region of the French algorithm.
+func frenchRV(w *snowballWord) int {
+	n := len(w.runes)
+	word := w.String()
+	switch {
+	case n >= 2 && w.isVowel(0) && w.isVowel(1):
+		return min(3, n)
+	case strings.HasPrefix(word, "par"), strings.HasPrefix(word, "col"), strings.HasPrefix(word, "tap"):
+		return 3
+	}
+	for i := 1; i < n; i++ {
+		if w.isVowel(i) {
+			return i + 1
+		}
+	}
+	return n
+}
+
+// frenchStandardSuffix removes a derivational suffix and reports whether it
+// did. The adverb endings are rewritten without reporting so that the verb
+// suffixes are still looked for.
+func frenchStandardSuffix(w *snowballWord, rv, r1, r2 int) bool {
+	suffix, group := frenchStandardSuffixes.match(w, 0)
+	switch group {
+	case 1:
+		return w.removeIn(suffix, r2)
+	case 2:
+		if !w.removeIn(suffix, r2) {
+			return false
+		}
+		frenchRemoveIc(w, r2)
+	case 3, 4, 5:
+		if !w.in(suffix, r2) {
+			return false
+		}
+		w.replace(suffix, map[int]string{3: "log", 4: "u", 5: "ent"}[group])
+	case 6:
+		if !w.removeIn(suffix, rv) {
+			return false
+		}
+		switch ending := w.longest(suffixList("iv eus abl iqU ièr Ièr")); ending {
+		case "iv":
+			if w.removeIn(ending, r2) {
+				w.removeIn("at", r2)
+			}
+		case "eus":
+			if !w.removeIn(ending, r2) && w.in(ending, r1) {
+				w.replace(ending, "eux")
+			}
+		case "abl", "iqU":
+			w.removeIn(ending, r2)
+		case "ièr", "Ièr":
+			if w.in(ending, rv) {
+				w.replace(ending, "i")
+			}
+		}
+	case 7:
+		if !w.removeIn(suffix, r2) {
+			return false
+		}
+		switch ending := w.longest(suffixList("abil ic iv")); ending {
+		case "abil":
+			if !w.removeIn(ending, r2) {
+				w.replace(ending, "abl")
+			}
+		case "ic":
+			frenchRemoveIc(w, r2)
+		case "iv":
+			w.removeIn(ending, r2)
+		}
+	case 8:
+		if !w.removeIn(suffix, r2) {
+			return false
+		}
+		if w.removeIn("at", r2) {
+			frenchRemoveIc(w, r2)
+		}
+	case 9:
+		w.replace(suffix, "eau")
+	case 10:
+		if !w.in(suffix, r1) {
+			return false
+		}
+		w.replace(suffix, "al")
+	case 11:
+		if w.removeIn(suffix, r2) {
+			return true
+		}
+		if !w.in(suffix, r1) {
+			return false
+		}
+		w.replace(suffix, "eux")
+	case 12:
+		if !w.in(suffix, r1) || w.isVowel(w.start(suffix)-1) {
+			return false
+		}
+		w.remove(suffix)
+	case 13, 14:
+		if w.in(suffix, rv) {
+			w.replace(suffix, map[int]string{13: "ant", 14: "ent"}[group])
+		}
+		return false
+	case 15:
+		if i := w.start(suffix) - 1; i >= rv && w.isVowel(i) {
+			w.remove(suffix)
+		}
+		return false
+	default:
+		return false
+	}
+	return true
+}
+
+// frenchRemoveIc removes a final "ic" in R2, or rewrites it as "iqU".
+func frenchRemoveIc(w *snowballWord, r2 int) {
+	if w.hasSuffix("ic") && !w.removeIn("ic", r2) {
+		w.replace("ic", "iqU")
+	}
+}
+
+func frenchIVerbSuffix(w *snowballWord, rv int) bool {
+	suffix := w.longestIn(frenchIVerbSuffixes, rv)
+	if i := w.start(suffix) - 1; suffix == "" || i < 0 || w.isVowel(i) {
+		return false
+	}
+	w.remove(suffix)
+	return true
+}
+
+func frenchVerbSuffix(w *snowballWord, rv, r2 int) bool {
+	suffix, group := frenchVerbSuffixes.match(w, rv)
+	switch group {
+	case 1:
+		return w.removeIn(suffix, r2)
+	case 2:
+		w.remove(suffix)
+	case 3:
+		w.remove(suffix)
+		if w.hasSuffix("e") {
+			w.remove("e")
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+func frenchResidualSuffix(w *snowballWord, rv, r2 int) {
+	if w.hasSuffix("s") && !w.precededBy("s", "aiouès") {
+		w.remove("s")
+	}
+
+	switch suffix := w.longestIn(suffixList("ion ier ière Ier Ière e ë"), rv); suffix {
+	case "ion":
+		if w.in(suffix, r2) && w.start(suffix) > rv && w.precededBy(suffix, "st") {
+			w.remove(suffix)
+		}
+	case "ier", "ière", "Ier", "Ière":
+		w.replace(suffix, "i")
+	case "e":
+		w.remove(suffix)
+	case "ë":
+		if w.follows(suffix, "gu") {
+			w.remove(suffix)
+		}
+	}
+}
+
+// stemSpanish implements the Spanish Snowball stemmer.
+func stemSpanish(word string) string {
+	w := newSnowballWord(word, spanishVowels)
+	rv := w.romanceRV()
+	r1, r2 := w.regions(0)
+
+	spanishAttachedPronoun(w, rv)
+	if !spanishStandardSuffix(w, r1, r2) && !spanishYVerbSuffix(w, rv) {
+		if suffix, group := spanishVerbSuffixes.match(w, rv); group != 0 {
+			if group == 1 && w.follows(suffix, "gu") {
+				suffix = "u" + suffix
+			}
+			w.remove(suffix)
+		}
+	}
+
+	switch suffix := w.longestIn(suffixList("os a o á í ó e é"), rv); suffix {
+	case "e", "é":
+		w.remove(suffix)
+		if w.in("u", rv) && w.follows("u", "g") {
+			w.remove("u")
+		}
+	case "":
+	default:
+		w.remove(suffix)
+	}
+
+	w.mapRunes("áéíóú", "aeiou")
+	return w.String()
+}
+
+func spanishAttachedPronoun(w *snowballWord, rv int) {
+	pronoun := w.longest(spanishPronouns)
+	if pronoun == "" {
+		return
+	}
+	stem := &snowballWord{runes: w.runes[:w.start(pronoun)], vowels: w.vowels}
+	switch ending := stem.longest(suffixList("iéndo ándo ár ér ír ando iendo ar er ir yendo")); ending {
+	case "iéndo", "ándo", "ár", "ér", "ír":
+		if stem.in(ending, rv) {
+			w.remove(pronoun)
+			w.replace(ending, strings.NewReplacer("á", "a", "é", "e", "í", "i").Replace(ending))
+		}
+	case "ando", "iendo", "ar", "er", "ir":
+		if stem.in(ending, rv) {
+			w.remove(pronoun)
+		}
+	case "yendo":
+		if stem.in(ending, rv) && stem.follows(ending, "u") {
+			w.remove(pronoun)
+		}
+	}
+}
+
+func spanishStandardSuffix(w *snowballWord, r1, r2 int) bool {
+	suffix, group := spanishStandardSuffixes.match(w, 0)
+	switch group {
+	case 1:
+		return w.removeIn(suffix, r2)
+	case 2:
+		if !w.removeIn(suffix, r2) {
+			return false
+		}
+		w.removeIn("ic", r2)
+	case 3, 4, 5:
+		if !w.in(suffix, r2) {
+			return false
+		}
+		w.replace(suffix, map[int]string{3: "log", 4: "u", 5: "ente"}[group])
+	case 6:
+		if !w.removeIn(suffix, r1) {
+			return false
+		}
+		romanceAdverbStem(w, r2, "iv os ic ad")
+	case 7:
+		if !w.removeIn(suffix, r2) {
+			return false
+		}
+		if ending := w.longest(suffixList("ante able ible")); ending != "" {
+			w.removeIn(ending, r2)
+		}
+	case 8:
+		if !w.removeIn(suffix, r2) {
+			return false
+		}
+		if ending := w.longest(suffixList("abil ic iv")); ending != "" {
+			w.removeIn(ending, r2)
+		}
+	case 9:
+		if !w.removeIn(suffix, r2) {
+			return false
+		}
+		w.removeIn("at", r2)
+	default:
+		return false
+	}
+	return true
+}
+
+// romanceAdverbStem removes the longest of endings in R2 left by removing
+// "amente", and an "at" before "iv".
+func romanceAdverbStem(w *snowballWord, r2 int, endings string) {
+	ending := w.longest(suffixList(endings))
+	if ending != "" && w.removeIn(ending, r2) && ending == "iv" {
+		w.removeIn("at", r2)
+	}
+}
+
+func spanishYVerbSuffix(w *snowballWord, rv int) bool {
+	suffix := w.longestIn(spanishYVerbSuffixes, rv)
+	if suffix == "" || !w.follows(suffix, "u") {
+		return false
+	}
+	w.remove(suffix)
+	return true
+}
+
+// stemPortuguese implements the Portuguese Snowball stemmer.
+func stemPortuguese(word string) string {
+	word = strings.NewReplacer("ã", "a~", "õ", "o~").Replace(word)
+	w := newSnowballWord(word, portugueseVowels)
+	rv := w.romanceRV()
+	r1, r2 := w.regions(0)
+
+	if portugueseStandardSuffix(w, rv, r1, r2) || portugueseVerbSuffix(w, rv) {
+		if w.in("i", rv) && w.follows("i", "c") {
+			w.remove("i")
+		}
+	} else if suffix := w.longestIn(suffixList("os a i o á í ó"), rv); suffix != "" {
+		w.remove(suffix)
+	}
+
+	switch suffix := w.longestIn(suffixList("e é ê"), rv); {
+	case suffix != "":
+		w.remove(suffix)
+		if w.in("u", rv) && w.follows("u", "g") {
+			w.remove("u")
+		} else if w.in("i", rv) && w.follows("i", "c") {
+			w.remove("i")
+		}
+	case w.hasSuffix("ç"):
+		w.replace("ç", "c")
+	}
+
+	return strings.NewReplacer("a~", "ã", "o~", "õ").Replace(w.String())
+}
+
+func portugueseStandardSuffix(w *snowballWord, rv, r1, r2 int) bool {
+	suffix, group := portugueseStandardSuffixes.match(w, 0)
+	switch group {
+	case 1:
+		return w.removeIn(suffix, r2)
+	case 2, 3, 4:
+		if !w.in(suffix, r2) {
+			return false
+		}
+		w.replace(suffix, map[int]string{2: "log", 3: "u", 4: "ente"}[group])
+	case 5:
+		if !w.removeIn(suffix, r1) {
+			return false
+		}
+		romanceAdverbStem(w, r2, "iv os ic ad")
+	case 6:
+		if !w.removeIn(suffix, r2) {
+			return false
+		}
+		if ending := w.longest(suffixList("ante avel ível")); ending != "" {
+			w.removeIn(ending, r2)
+		}
+	case 7:
+		if !w.removeIn(suffix, r2) {
+			return false
+		}
+		if ending := w.longest(suffixList("abil ic iv")); ending != "" {
+			w.removeIn(ending, r2)
+		}
+	case 8:
+		if !w.removeIn(suffix, r2) {
+			return false
+		}
+		w.removeIn("at", r2)
+	case 9:
+		if !w.in(suffix, rv) || !w.follows(suffix, "e") {
+			return false
+		}
+		w.replace(suffix, "ir")
+	default:
+		return false
+	}
+	return true
+}
+
+func portugueseVerbSuffix(w *snowballWord, rv int) bool {
+	suffix := w.longestIn(portugueseVerbSuffixes, rv)
+	if suffix == "" {
+		return false
+	}
+	w.remove(suffix)
+	return true
+}
+
+// stemItalian implements the Italian Snowball stemmer.
+func stemItalian(word string) string {
+	w := newSnowballWord(word, italianVowels)
+	w.mapRunes("áéíóú", "àèìòù")
+	for i, r := range w.runes {
+		switch {
+		case r == 'u' && i > 0 && w.runes[i-1] == 'q':
+			w.runes[i] = 'U'
+		case (r == 'u' || r == 'i') && w.isVowel(i-1) && w.isVowel(i+1):
+			w.runes[i] = r - 'a' + 'A'
+		}
+	}
+	rv := w.romanceRV()
+	r1, r2 := w.regions(0)
+
+	if pronoun := w.longest(italianPronouns); pronoun != "" {
+		stem := &snowballWord{runes: w.runes[:w.start(pronoun)], vowels: w.vowels}
+		switch ending := stem.longest(suffixList("ando endo ar er ir")); ending {
+		case "ando", "endo":
+			if stem.in(ending, rv) {
+				w.remove(pronoun)
+			}
+		case "ar", "er", "ir":
+			if stem.in(ending, rv) {
+				w.replace(pronoun, "e")
+			}
+		}
+	}
+
+	if !italianStandardSuffix(w, rv, r1, r2) {
+		if suffix := w.longestIn(italianVerbSuffixes, rv); suffix != "" {
+			w.remove(suffix)
+		}
+	}
+
+	if suffix := w.longestIn(suffixList("a e i o à è ì ò"), rv); suffix != "" {
+		w.remove(suffix)
+		w.removeIn("i", rv)
+	}
+	if w.in("h", rv) && w.follows("h", "c") {
+		w.remove("h")
+	}
+
+	w.mapRunes("IU", "iu")
+	return w.String()
+}
+
+func italianStandardSuffix(w *snowballWord, rv, r1, r2 int) bool {
+	suffix, group := italianStandardSuffixes.match(w, 0)
+	switch group {
+	case 1:
+		return w.removeIn(suffix, r2)
+	case 2:
+		if !w.removeIn(suffix, r2) {
+			return false
+		}
+		w.removeIn("ic", r2)
+	case 3, 4, 5:
+		if !w.in(suffix, r2) {
+			return false
+		}
+		w.replace(suffix, map[int]string{3: "log", 4: "u", 5: "ente"}[group])
+	case 6:
+		return w.removeIn(suffix, rv)
+	case 7:
+		if !w.removeIn(suffix, r1) {
+			return false
+		}
+		romanceAdverbStem(w, r2, "iv os ic abil")
+	case 8:
+		if !w.removeIn(suffix, r2) {
+			return false
+		}
+		if ending := w.longest(suffixList("abil ic iv")); ending != "" {
+			w.removeIn(ending, r2)
+		}
+	case 9:
+		if !w.removeIn(suffix, r2) {
+			return false
+		}
+		if w.removeIn("at", r2) {
+			w.removeIn("ic", r2)
+		}
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+package service
+
+import "strings"
+
+const russianVowels = "аеиоуыэюя"
+
+var (
+	// The suffixes of the first group of these steps are only removed when
+	// preceded by а or я.
+	russianGerundSuffixes = newSuffixGroups(
+		"в вши вшись",
+		"ив ивши ившись ыв ывши ывшись",
+	)
+	russianParticipleSuffixes = newSuffixGroups(
+		"ем нн вш ющ щ",
+		"ивш ывш ующ",
+	)
+	russianVerbSuffixes = newSuffixGroups(
+		"ла на ете йте ли й л ем н ло но ет ют ны ть ешь нно",
+		"ила ыла ена ейте уйте ите или ыли ей уй ил ыл им ым ен ило ыло ено ят ует уют ит ыт ены ить ыть ишь ую ю",
+	)
+	russianAdjectiveSuffixes = suffixList("ее ие ые ое ими ыми ей ий ый ой ем им ым ом его ого ему ому их ых ую юю " +
+		"ая яя ою ею")
+	russianNounSuffixes = suffixList("а ев ов ие ье е иями ями ами еи ии и ией ей ой ий й иям ям ием ем ам ом о у " +
+		"ах иях ях ы ь ию ью ю ия ья я")
+)
+
+// stemRussian implements the Russian Snowball stemmer.
+func stemRussian(word string) string {
+	w := newSnowballWord(strings.ReplaceAll(word, "ё", "е"), russianVowels)
+	rv := len(w.runes)
+	for i := range w.runes {
+		if w.isVowel(i) {
+			rv = i + 1
+			break
+		}
+	}
+	r2 := w.region(w.region(0))
+
+	if !russianRemove(w, rv, russianGerundSuffixes) {
+		if suffix := w.longestIn(suffixList("ся сь"), rv); suffix != "" {
+			w.remove(suffix)
+		}
+		if !russianAdjectival(w, rv) && !russianRemove(w, rv, russianVerbSuffixes) {
+			if suffix := w.longestIn(russianNounSuffixes, rv); suffix != "" {
+				w.remove(suffix)
+			}
+		}
+	}
+
+	w.removeIn("и", rv)
+
+	if suffix := w.longestIn(suffixList("ост ость"), r2); suffix != "" {
+		w.remove(suffix)
+	}
+
+	switch suffix := w.longestIn(suffixList("ейш ейше н ь"), rv); suffix {
+	case "ейш", "ейше":
+		w.remove(suffix)
+		if w.in("нн", rv) {
+			w.remove("н")
+		}
+	case "н":
+		if w.in("нн", rv) {
+			w.remove("н")
+		}
+	case "ь":
+		w.remove(suffix)
+	}
+
+	return w.String()
+}
+
+// russianRemove removes the longest suffix of groups within RV, and reports
+// whether it did.
+func russianRemove(w *snowballWord, rv int, groups suffixGroups) bool {
+	suffix, group := groups.match(w, rv)
+	switch group {
+	case 0:
+		return false
+	case 1:
+		if w.start(suffix) <= rv || !w.precededBy(suffix, "ая") {
+			return false
+		}
+	}
+	w.remove(suffix)
+	return true
+}
+
+// russianAdjectival removes an adjective ending together with a participle
+// suffix preceding it.
+func russianAdjectival(w *snowballWord, rv int) bool {
+	suffix := w.longestIn(russianAdjectiveSuffixes, rv)
+	if suffix == "" {
+		return false
+	}
+	w.remove(suffix)
+	russianRemove(w, rv, russianParticipleSuffixes)
+	return true
+}
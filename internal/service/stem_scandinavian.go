@@ -0,0 +1,109 @@
+package service
+
+const (
+	swedishVowels   = "aeiouyäåö"
+	danishVowels    = "aeiouyæåø"
+	norwegianVowels = "aeiouyæåø"
+)
+
+var (
+	swedishStep1Suffixes = suffixList("a arna erna heterna orna ad e ade ande arne are aste en anden aren heten " +
+		"ern ar er heter or as arnas ernas ornas es ades andes ens arens hetens erns at andet het ast s")
+	danishStep1Suffixes = suffixList("hed ethed ered e erede ende erende ene erne ere en heden eren er heder " +
+		"erer heds es endes erendes enes ernes eres ens hedens erens ers ets erets et eret s")
+	norwegianStep1Suffixes = suffixList("a e ede ande ende ane ene hetene en heten ar er heter as es edes " +
+		"endes enes hetenes ens hetens ers ets et het ast erte ert s")
+)
+
+// stemSwedish implements the Swedish Snowball stemmer.
+func stemSwedish(word string) string {
+	w := newSnowballWord(word, swedishVowels)
+	r1, _ := w.regions(3)
+
+	if suffix := w.longestIn(swedishStep1Suffixes, r1); suffix != "" {
+		if suffix != "s" || w.precededBy(suffix, "bcdfghjklmnoprtvy") {
+			w.remove(suffix)
+		}
+	}
+
+	if w.longestIn(suffixList("dd gd nn dt gt kt tt"), r1) != "" {
+		w.runes = w.runes[:len(w.runes)-1]
+	}
+
+	switch suffix := w.longestIn(suffixList("lig ig els löst fullt"), r1); suffix {
+	case "lig", "ig", "els":
+		w.remove(suffix)
+	case "löst", "fullt":
+		w.runes = w.runes[:len(w.runes)-1]
+	}
+
+	return w.String()
+}
+
+// stemDanish implements the Danish Snowball stemmer.
+func stemDanish(word string) string {
+	w := newSnowballWord(word, danishVowels)
+	r1, _ := w.regions(3)
+
+	if suffix := w.longestIn(danishStep1Suffixes, r1); suffix != "" {
+		if suffix != "s" || w.precededBy(suffix, "abcdfghjklmnoprtvyzå") {
+			w.remove(suffix)
+		}
+	}
+
+	danishConsonantPair(w, r1)
+
+	if w.hasSuffix("igst") {
+		w.remove("st")
+	}
+	switch suffix := w.longestIn(suffixList("ig lig elig els løst"), r1); suffix {
+	case "ig", "lig", "elig", "els":
+		w.remove(suffix)
+		danishConsonantPair(w, r1)
+	case "løst":
+		w.runes = w.runes[:len(w.runes)-1]
+	}
+
+	n := len(w.runes)
+	if n >= 2 && n-2 >= r1 && w.runes[n-1] == w.runes[n-2] && !w.isVowel(n-1) {
+		w.runes = w.runes[:n-1]
+	}
+
+	return w.String()
+}
+
+func danishConsonantPair(w *snowballWord, r1 int) {
+	if w.longestIn(suffixList("gd dt gt kt"), r1) != "" {
+		w.runes = w.runes[:len(w.runes)-1]
+	}
+}
+
+// stemNorwegian implements the Norwegian (Bokmål) Snowball stemmer.
+func stemNorwegian(word string) string {
+	w := newSnowballWord(word, norwegianVowels)
+	r1, _ := w.regions(3)
+
+	if suffix := w.longestIn(norwegianStep1Suffixes, r1); suffix != "" {
+		switch suffix {
+		case "erte", "ert":
+			w.replace(suffix, "er")
+		case "s":
+			if w.precededBy(suffix, "bcdfghjlmnoprtvyz") ||
+				w.precededBy(suffix, "k") && !w.isVowel(w.start(suffix)-2) {
+				w.remove(suffix)
+			}
+		default:
+			w.remove(suffix)
+		}
+	}
+
+	if w.longestIn(suffixList("dt vt"), r1) != "" {
+		w.runes = w.runes[:len(w.runes)-1]
+	}
+
+	if suffix := w.longestIn(suffixList("leg eleg ig eig lig elig els lov elov slov hetslov"), r1); suffix != "" {
+		w.remove(suffix)
+	}
+
+	return w.String()
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"bufio"
+	"embed"
+	"strings"
+	"sync"
+)
+
+//go:embed data/stopwords/*.txt
+var stopwordFiles embed.FS
+
+const defaultStopwordLanguage = "en"
+
+var (
+	stopwordsOnce sync.Once
+	stopwordLists map[string]map[string]bool
+)
+
+// stopwordsFor returns the case-folded stopword list for a language, falling
+// back to English when the language has no list.
+func stopwordsFor(language string) map[string]bool {
+	stopwordsOnce.Do(loadStopwords)
+
+	if words, ok := stopwordLists[language]; ok {
+		return words
+	}
+	return stopwordLists[defaultStopwordLanguage]
+}
+
+func loadStopwords() {
+	stopwordLists = make(map[string]map[string]bool)
+
+	entries, err := stopwordFiles.ReadDir("data/stopwords")
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		file, err := stopwordFiles.Open("data/stopwords/" + entry.Name())
+		if err != nil {
+			continue
+		}
+
+		words := make(map[string]bool)
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			if word := strings.TrimSpace(scanner.Text()); word != "" {
+				words[foldTerm(word)] = true
+			}
+		}
+		_ = file.Close()
+
+		stopwordLists[strings.TrimSuffix(entry.Name(), ".txt")] = words
+	}
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"vm-chan/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	summaryTextRank = "textrank"
+	summaryLexRank  = "lexrank"
+)
+
+const (
+	maxSummarizedRunes = 100000
+	// maxSummarizedSentences bounds the sentences ranked, whose pairs sharing
+	// a word are all compared.
+	maxSummarizedSentences = 3000
+	defaultSummaryRatio    = 0.2
+	// lexRankThreshold is the cosine similarity below which LexRank does not
+	// link two sentences.
+	lexRankThreshold = 0.1
+)
+
+type summarizationService struct {
+	tokenizer Tokenizer
+	logger    *zap.Logger
+}
+
+func NewSummarizationService(tokenizer Tokenizer, logger *zap.Logger) domain.SummarizationService {
+	return &summarizationService{
+		tokenizer: tokenizer,
+		logger:    logger,
+	}
+}
+
+func (s *summarizationService) Summarize(ctx context.Context, req *domain.SummarizeRequest) (*domain.SummarizeResponse, error) {
+	algorithm := req.Algorithm
+	if algorithm == "" {
+		algorithm = summaryTextRank
+	}
+	if algorithm != summaryTextRank && algorithm != summaryLexRank {
+		return nil, fmt.Errorf("%w: unknown algorithm %q, expected %s or %s",
+			domain.ErrInvalidInput, algorithm, summaryTextRank, summaryLexRank)
+	}
+	if req.Sentences < 0 || req.Ratio < 0 || req.Ratio > 1 {
+		return nil, fmt.Errorf("%w: sentences must be positive and ratio between 0 and 1", domain.ErrInvalidInput)
+	}
+	if req.Sentences > 0 && req.Ratio > 0 {
+		return nil, fmt.Errorf("%w: set either sentences or ratio", domain.ErrInvalidInput)
+	}
+	if len([]rune(req.Text)) > maxSummarizedRunes {
+		return nil, fmt.Errorf("%w: text is longer than %d characters", domain.ErrInvalidInput, maxSummarizedRunes)
+	}
+
+	language := primaryLanguage(req.Language)
+	detected := false
+	if language == "" {
+		if detection := detectLanguage(req.Text); detection.Confidence >= minDetectionConfidence {
+			language = detection.Language
+			detected = true
+		}
+	}
+
+	spans := splitSentences(req.Text)
+	if len(spans) > maxSummarizedSentences {
+		return nil, fmt.Errorf("%w: text has more than %d sentences", domain.ErrInvalidInput, maxSummarizedSentences)
+	}
+	length := req.Sentences
+	if length == 0 {
+		ratio := req.Ratio
+		if ratio == 0 {
+			ratio = defaultSummaryRatio
+		}
+		length = int(math.Ceil(ratio * float64(len(spans))))
+	}
+	length = min(length, len(spans))
+
+	words := s.sentenceWords(req.Text, spans, language)
+	var graph [][]sentenceEdge
+	var err error
+	if algorithm == summaryLexRank {
+		graph, err = lexRankGraph(ctx, words)
+	} else {
+		graph, err = textRankGraph(ctx, words)
+	}
+	if err != nil {
+		return nil, err
+	}
+	scores, err := weightedPageRank(ctx, graph)
+	if err != nil {
+		return nil, err
+	}
+
+	ranked := make([]int, len(spans))
+	for i := range ranked {
+		ranked[i] = i
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return scores[ranked[i]] > scores[ranked[j]]
+	})
+	selected := ranked[:length]
+	sort.Ints(selected)
+
+	response := &domain.SummarizeResponse{
+		Language:         language,
+		LanguageDetected: detected,
+		Algorithm:        algorithm,
+		SentenceCount:    len(spans),
+		Sentences:        make([]domain.SummarySentence, 0, length),
+	}
+	texts := make([]string, 0, length)
+	for _, i := range selected {
+		response.Sentences = append(response.Sentences, domain.SummarySentence{
+			Index: i,
+			Text:  spans[i].text,
+			Start: spans[i].start,
+			End:   spans[i].end,
+			Score: round4(scores[i]),
+		})
+		texts = append(texts, spans[i].text)
+	}
+	response.Summary = strings.Join(texts, " ")
+
+	s.logger.Info("Summarization completed",
+		zap.String("language", language),
+		zap.String("algorithm", algorithm),
+		zap.Int("sentences", len(spans)),
+		zap.Int("selected", length),
+	)
+
+	return response, nil
+}
+
+// sentenceWords returns the content words of every sentence, case-folded and
+// stemmed when the language has a stemmer, with their number of occurrences.
+func (s *summarizationService) sentenceWords(text string, spans []sentenceSpan, language string) []map[string]int {
+	stopwords := stopwordsFor(language)
+	stem := stemmers[language]
+	tokens := s.tokenizer.Tokenize(text)
+
+	words := make([]map[string]int, len(spans))
+	sentence := 0
+	for i := range words {
+		words[i] = make(map[string]int)
+	}
+	for _, token := range tokens {
+		for sentence < len(spans)-1 && token.Start >= spans[sentence].end {
+			sentence++
+		}
+		term := foldTerm(token.Text)
+		if token.Kind != TokenWord || stopwords[term] {
+			continue
+		}
+		if stem != nil {
+			term = stem(term)
+		}
+		words[sentence][term]++
+	}
+	return words
+}
+
+// sentenceEdge links a sentence to another with a weight.
+type sentenceEdge struct {
+	to     int
+	weight float64
+}
+
+// sentencePostings returns, for every word, the sentences holding it in
+// increasing order.
+func sentencePostings(words []map[string]int) map[string][]int {
+	postings := make(map[string][]int)
+	for i, sentence := range words {
+		for word := range sentence {
+			postings[word] = append(postings[word], i)
+		}
+	}
+	return postings
+}
+
+// link adds an undirected edge between two sentences.
+func link(graph [][]sentenceEdge, i, j int, weight float64) {
+	graph[i] = append(graph[i], sentenceEdge{to: j, weight: weight})
+	graph[j] = append(graph[j], sentenceEdge{to: i, weight: weight})
+}
+
+// textRankGraph links the sentences sharing words by the number of distinct
+// words they share, normalized by the logarithm of their lengths so that long
+// sentences are not favored. Only the sentences sharing a word are compared.
+func textRankGraph(ctx context.Context, words []map[string]int) ([][]sentenceEdge, error) {
+	postings := sentencePostings(words)
+	graph := make([][]sentenceEdge, len(words))
+	for i := range words {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		shared := make(map[int]int)
+		for word := range words[i] {
+			for _, j := range postings[word] {
+				if j > i {
+					shared[j]++
+				}
+			}
+		}
+		for _, j := range sortedKeys(shared) {
+			weight := float64(shared[j])
+			if norm := math.Log(float64(len(words[i]))) + math.Log(float64(len(words[j]))); norm > 0 {
+				weight /= norm
+			}
+			link(graph, i, j, weight)
+		}
+	}
+	return graph, nil
+}
+
+// lexRankGraph links the sentences by the cosine similarity of their TF-IDF
+// vectors, where the sentences of the text are the documents the inverse
+// document frequencies are computed over. Similarities below
+// lexRankThreshold are dropped.
+func lexRankGraph(ctx context.Context, words []map[string]int) ([][]sentenceEdge, error) {
+	postings := sentencePostings(words)
+
+	vectors := make([]map[string]float64, len(words))
+	norms := make([]float64, len(words))
+	for i, sentence := range words {
+		vectors[i] = make(map[string]float64, len(sentence))
+		for word, count := range sentence {
+			weight := float64(count) * math.Log(float64(len(words))/float64(len(postings[word]))+1)
+			vectors[i][word] = weight
+			norms[i] += weight * weight
+		}
+		norms[i] = math.Sqrt(norms[i])
+	}
+
+	graph := make([][]sentenceEdge, len(words))
+	for i := range vectors {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		dots := make(map[int]float64)
+		for word, weight := range vectors[i] {
+			for _, j := range postings[word] {
+				if j > i {
+					dots[j] += weight * vectors[j][word]
+				}
+			}
+		}
+		for _, j := range sortedKeys(dots) {
+			if norms[i] == 0 || norms[j] == 0 {
+				continue
+			}
+			if similarity := dots[j] / (norms[i] * norms[j]); similarity >= lexRankThreshold {
+				link(graph, i, j, similarity)
+			}
+		}
+	}
+	return graph, nil
+}
+
+func sortedKeys[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// weightedPageRank ranks the nodes of an undirected weighted graph given by
+// the edges of every node. Every node passes its rank on to its neighbors in
+// proportion to the weights of the edges linking them.
+func weightedPageRank(ctx context.Context, graph [][]sentenceEdge) ([]float64, error) {
+	totals := make([]float64, len(graph))
+	for i, edges := range graph {
+		for _, edge := range edges {
+			totals[i] += edge.weight
+		}
+	}
+
+	ranks := make([]float64, len(graph))
+	for i := range ranks {
+		ranks[i] = 1
+	}
+	for iteration := 0; iteration < textRankIterations; iteration++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		next := make([]float64, len(ranks))
+		change := 0.0
+		for i, edges := range graph {
+			sum := 0.0
+			for _, edge := range edges {
+				sum += edge.weight / totals[edge.to] * ranks[edge.to]
+			}
+			next[i] = 1 - textRankDamping + textRankDamping*sum
+			change = math.Max(change, math.Abs(next[i]-ranks[i]))
+		}
+		ranks = next
+		if change < textRankTolerance {
+			break
+		}
+	}
+	return ranks, nil
+}
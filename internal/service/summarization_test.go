@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"vm-chan/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSummarizationService(t *testing.T) {
+	service := NewSummarizationService(NewTokenizer(), zap.NewNop())
+
+	thread := "Hi, my invoice export has failed since Monday. " +
+		"The export of the March invoice stops with a timeout error. " +
+		"Thanks for reaching out! " +
+		"The invoice export timeout happens because the March invoice has too many lines. " +
+		"Have a nice day. " +
+		"We raised the export timeout, so the March invoice export works again."
+
+	summarize := func(t *testing.T, req *domain.SummarizeRequest) *domain.SummarizeResponse {
+		req.Text = thread
+		response, err := service.Summarize(context.Background(), req)
+		require.NoError(t, err)
+		return response
+	}
+
+	for _, algorithm := range []string{"textrank", "lexrank"} {
+		t.Run(algorithm, func(t *testing.T) {
+			response := summarize(t, &domain.SummarizeRequest{Sentences: 2, Algorithm: algorithm})
+
+			assert.Equal(t, "en", response.Language)
+			assert.True(t, response.LanguageDetected)
+			assert.Equal(t, algorithm, response.Algorithm)
+			assert.Equal(t, 6, response.SentenceCount)
+			require.Len(t, response.Sentences, 2)
+			assert.Less(t, response.Sentences[0].Index, response.Sentences[1].Index)
+			for _, sentence := range response.Sentences {
+				assert.NotContains(t, []string{"Thanks for reaching out!", "Have a nice day."}, sentence.Text)
+				assert.Equal(t, sentence.Text, string([]rune(thread)[sentence.Start:sentence.End]))
+			}
+			assert.Equal(t, response.Sentences[0].Text+" "+response.Sentences[1].Text, response.Summary)
+		})
+	}
+
+	t.Run("Ratio", func(t *testing.T) {
+		assert.Len(t, summarize(t, &domain.SummarizeRequest{Ratio: 0.5}).Sentences, 3)
+		assert.Len(t, summarize(t, &domain.SummarizeRequest{}).Sentences, 2)
+		assert.Len(t, summarize(t, &domain.SummarizeRequest{Sentences: 10}).Sentences, 6)
+	})
+
+	t.Run("Invalid requests", func(t *testing.T) {
+		for name, req := range map[string]*domain.SummarizeRequest{
+			"unknown algorithm":   {Text: thread, Algorithm: "lsa"},
+			"negative sentences":  {Text: thread, Sentences: -1},
+			"ratio above one":     {Text: thread, Ratio: 1.5},
+			"sentences and ratio": {Text: thread, Sentences: 2, Ratio: 0.5},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := service.Summarize(context.Background(), req)
+				assert.ErrorIs(t, err, domain.ErrInvalidInput)
+			})
+		}
+	})
+}
+
+func TestWeightedPageRank(t *testing.T) {
+	graph := make([][]sentenceEdge, 3)
+	link(graph, 0, 1, 1)
+	link(graph, 0, 2, 1)
+	ranks, err := weightedPageRank(context.Background(), graph)
+	require.NoError(t, err)
+
+	assert.Greater(t, ranks[0], ranks[1])
+	assert.InDelta(t, ranks[1], ranks[2], 1e-9)
+	assert.InDelta(t, 3, ranks[0]+ranks[1]+ranks[2], 1e-4)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = weightedPageRank(canceled, graph)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSummarizationLimits(t *testing.T) {
+	service := NewSummarizationService(NewTokenizer(), zap.NewNop())
+
+	// Sentences all sharing their one word link every pair.
+	text := strings.Repeat("Word. ", maxSummarizedSentences)
+	start := time.Now()
+	response, err := service.Summarize(context.Background(), &domain.SummarizeRequest{Text: text, Language: "en"})
+	require.NoError(t, err)
+	assert.Equal(t, maxSummarizedSentences, response.SentenceCount)
+	assert.Less(t, time.Since(start), 10*time.Second)
+
+	_, err = service.Summarize(context.Background(), &domain.SummarizeRequest{Text: text + "Word.", Language: "en"})
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = service.Summarize(canceled, &domain.SummarizeRequest{Text: text, Language: "en", Algorithm: "lexrank"})
+	assert.ErrorIs(t, err, context.Canceled)
+}
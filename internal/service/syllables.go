@@ -0,0 +1,76 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	englishSilentEnding = regexp.MustCompile(`(?:[^laeiouy]es|[^laeiouytd]ed|[^laeiouy]e)$`)
+	englishVowelGroup   = regexp.MustCompile(`[aeiouy]+`)
+)
+
+// countSyllables estimates the number of syllables in a word. English uses the
+// usual silent-ending heuristic, other languages count groups of consecutive
+// vowels as classified by the language's phonology table.
+func countSyllables(word string, p *phonology) int {
+	word = strings.ToLower(strings.TrimFunc(norm.NFC.String(word), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}))
+	if word == "" {
+		return 0
+	}
+
+	var syllables int
+	switch p.language {
+	case "en":
+		syllables = englishSyllables(word)
+	case "fr":
+		syllables = vowelGroups(strings.TrimSuffix(strings.TrimSuffix(word, "s"), "e"), p)
+	default:
+		syllables = vowelGroups(word, p)
+	}
+
+	if syllables < 1 {
+		return 1
+	}
+	return syllables
+}
+
+func englishSyllables(word string) int {
+	if len([]rune(word)) <= 3 {
+		return 1
+	}
+
+	word = englishSilentEnding.ReplaceAllString(word, "")
+	word = strings.TrimPrefix(word, "y")
+
+	return len(englishVowelGroup.FindAllString(word, -1))
+}
+
+func vowelGroups(word string, p *phonology) int {
+	runes := []rune(word)
+
+	groups := 0
+	inGroup := false
+	for i, r := range runes {
+		var prev, next rune
+		if i > 0 {
+			prev = runes[i-1]
+		}
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		isVowel := p.classify(r, prev, next) == letterVowel
+		if isVowel && !inGroup {
+			groups++
+		}
+		inGroup = isVowel
+	}
+
+	return groups
+}
@@ -2,67 +2,124 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"strings"
-	"unicode"
 
 	"vm-chan/internal/domain"
+	"vm-chan/internal/logging"
 
 	"go.uber.org/zap"
 )
 
 type textAnalysisService struct {
-	logger *zap.Logger
+	registry     *AnalyzerRegistry
+	tokenizer    Tokenizer
+	fingerprints *FingerprintIndex
+	logger       *zap.Logger
 }
 
-func NewTextAnalysisService(logger *zap.Logger) domain.TextAnalysisService {
+// NewTextAnalysisService returns the service running the requested analyzers.
+// When fingerprints is not nil, the texts of an authenticated user that
+// request it, and the stored documents, are fingerprinted and indexed for the
+// duplicates analyzer once their analyses have run; texts whose fingerprint
+// cannot be stored are analyzed without a document ID.
+func NewTextAnalysisService(registry *AnalyzerRegistry, tokenizer Tokenizer, fingerprints *FingerprintIndex, logger *zap.Logger) domain.TextAnalysisService {
 	return &textAnalysisService{
-		logger: logger,
+		registry:     registry,
+		tokenizer:    tokenizer,
+		fingerprints: fingerprints,
+		logger:       logger,
 	}
 }
 
-func (s *textAnalysisService) AnalyzeText(ctx context.Context, sentence string) (*domain.TextAnalysisResponse, error) {
-	s.logger.Info("Analyzing text", zap.String("sentence", sentence))
-
-	cleanSentence := strings.TrimSpace(sentence)
-	if cleanSentence == "" {
-		return &domain.TextAnalysisResponse{
-			Sentence:       sentence,
-			WordCount:      0,
-			VowelCount:     0,
-			ConsonantCount: 0,
-		}, nil
-	}
+func (s *textAnalysisService) AnalyzeText(ctx context.Context, req *domain.TextAnalysisRequest) (*domain.TextAnalysisResponse, error) {
+	sentence := req.Sentence
+	s.logger.Info("Analyzing text",
+		logging.Sensitive("sentence", sentence),
+		zap.String("language", req.Language),
+		zap.Strings("analyses", req.Analyses),
+	)
 
-	words := strings.Fields(cleanSentence)
-	wordCount := len(words)
+	language := primaryLanguage(req.Language)
+	if len(req.Normalize) > 0 {
+		normalized, err := normalizeText(sentence, req.Normalize, language)
+		if err != nil {
+			return nil, err
+		}
+		sentence = normalized
+	}
 
-	vowelCount := 0
-	consonantCount := 0
-	vowels := "aeiouAEIOU"
+	var detection *domain.LanguageResult
+	if language == "" {
+		detection = detectLanguage(sentence)
+		if detection.Confidence >= minDetectionConfidence {
+			language = detection.Language
+		}
+	}
 
-	for _, char := range cleanSentence {
-		if unicode.IsLetter(char) {
-			if strings.ContainsRune(vowels, char) {
-				vowelCount++
-			} else {
-				consonantCount++
-			}
+	phonology, ok := lookupPhonology(language)
+	if !ok {
+		if !isKnownLanguage(language) {
+			return nil, fmt.Errorf("%w: unsupported language %q, supported languages: %s",
+				domain.ErrInvalidInput, req.Language, strings.Join(supportedLanguages(), ", "))
 		}
+		phonology = defaultPhonology
+	}
+
+	analyzers, err := s.registry.Resolve(req.Analyses)
+	if err != nil {
+		return nil, err
 	}
 
+	doc := newDocument(sentence, phonology, s.tokenizer)
+	doc.Language = language
+	doc.Options = req.Options
+	doc.detection = detection
+	letters := doc.letterCounts()
+
 	response := &domain.TextAnalysisResponse{
-		Sentence:       sentence,
-		WordCount:      wordCount,
-		VowelCount:     vowelCount,
-		ConsonantCount: consonantCount,
+		Sentence:         sentence,
+		Language:         language,
+		LanguageDetected: detection != nil && language != "",
+		WordCount:        len(doc.Tokens()),
+		VowelCount:       letters.vowels,
+		ConsonantCount:   letters.consonants,
+		OtherLetterCount: letters.other,
+	}
+
+	if len(analyzers) > 0 {
+		response.Results = make(map[string]interface{}, len(analyzers))
+	}
+	for _, analyzer := range analyzers {
+		result, err := analyzer.Analyze(ctx, doc)
+		if err != nil {
+			s.logger.Error("Analyzer failed", zap.String("analyzer", analyzer.Name()), zap.Error(err))
+			return nil, fmt.Errorf("analyzer %s: %w", analyzer.Name(), err)
+		}
+		response.Results[analyzer.Name()] = result
+	}
+
+	// A failure to record the fingerprint does not withhold the analysis.
+	if s.fingerprints != nil && (req.KeepFingerprint || req.DocumentID != "") {
+		id, err := recordFingerprint(ctx, s.fingerprints, doc, req.DocumentID)
+		if err != nil {
+			s.logger.Error("Failed to record fingerprint", zap.Error(err))
+		}
+		response.DocumentID = id
 	}
 
 	s.logger.Info("Text analysis completed",
-		zap.String("sentence", sentence),
-		zap.Int("words", wordCount),
-		zap.Int("vowels", vowelCount),
-		zap.Int("consonants", consonantCount),
+		logging.Sensitive("sentence", sentence),
+		zap.String("language", language),
+		zap.Int("words", response.WordCount),
+		zap.Int("vowels", response.VowelCount),
+		zap.Int("consonants", response.ConsonantCount),
+		zap.Int("other_letters", response.OtherLetterCount),
 	)
 
 	return response, nil
 }
+
+func (s *textAnalysisService) ListAnalyzers(ctx context.Context) []domain.AnalyzerInfo {
+	return s.registry.Describe()
+}
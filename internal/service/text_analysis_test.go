@@ -2,22 +2,32 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
+	"vm-chan/internal/domain"
+	"vm-chan/internal/logging"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestTextAnalysisService_AnalyzeText(t *testing.T) {
 	logger := zap.NewNop()
-	service := NewTextAnalysisService(logger)
+	registry, err := NewAnalyzerRegistry(DefaultAnalyzers(AnalyzerResources{})...)
+	require.NoError(t, err)
+	service := NewTextAnalysisService(registry, NewTokenizer(), nil, logger)
 
 	tests := []struct {
 		name               string
 		sentence           string
+		language           string
 		expectedWords      int
 		expectedVowels     int
 		expectedConsonants int
+		expectedOther      int
 	}{
 		{
 			name:               "Simple sentence",
@@ -61,17 +71,195 @@ func TestTextAnalysisService_AnalyzeText(t *testing.T) {
 			expectedVowels:     3,
 			expectedConsonants: 7,
 		},
+		{
+			name:               "French accented vowels",
+			sentence:           "Où est l'été?",
+			language:           "fr",
+			expectedWords:      3,
+			expectedVowels:     5,
+			expectedConsonants: 4,
+		},
+		{
+			name:               "German umlauts and eszett",
+			sentence:           "Grüße aus Köln",
+			language:           "de-DE",
+			expectedWords:      3,
+			expectedVowels:     5,
+			expectedConsonants: 7,
+		},
+		{
+			name:               "Russian Cyrillic",
+			sentence:           "Привет, мир",
+			language:           "ru",
+			expectedWords:      2,
+			expectedVowels:     3,
+			expectedConsonants: 6,
+		},
+		{
+			name:               "Russian soft sign is neither",
+			sentence:           "Мать",
+			language:           "ru",
+			expectedWords:      1,
+			expectedVowels:     1,
+			expectedConsonants: 2,
+			expectedOther:      1,
+		},
+		{
+			name:               "Greek with tonos",
+			sentence:           "Καλημέρα",
+			language:           "el",
+			expectedWords:      1,
+			expectedVowels:     4,
+			expectedConsonants: 4,
+		},
+		{
+			name:               "CJK ideographs are other letters",
+			sentence:           "你好 world",
+			expectedWords:      2,
+			expectedVowels:     1,
+			expectedConsonants: 4,
+			expectedOther:      2,
+		},
+		{
+			name:               "Decomposed input is normalized",
+			sentence:           "cafe\u0301",
+			language:           "fr",
+			expectedWords:      1,
+			expectedVowels:     2,
+			expectedConsonants: 2,
+		},
+		{
+			name:               "English positional y",
+			sentence:           "Yes, my rhythm",
+			language:           "en",
+			expectedWords:      3,
+			expectedVowels:     3,
+			expectedConsonants: 8,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := service.AnalyzeText(context.Background(), tt.sentence)
+			result, err := service.AnalyzeText(context.Background(), &domain.TextAnalysisRequest{
+				Sentence: tt.sentence,
+				Language: tt.language,
+			})
 
 			assert.NoError(t, err)
 			assert.Equal(t, tt.sentence, result.Sentence)
 			assert.Equal(t, tt.expectedWords, result.WordCount)
 			assert.Equal(t, tt.expectedVowels, result.VowelCount)
 			assert.Equal(t, tt.expectedConsonants, result.ConsonantCount)
+			assert.Equal(t, tt.expectedOther, result.OtherLetterCount)
 		})
 	}
+
+	t.Run("Unsupported language", func(t *testing.T) {
+		result, err := service.AnalyzeText(context.Background(), &domain.TextAnalysisRequest{
+			Sentence: "Hello world",
+			Language: "xx",
+		})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		assert.Nil(t, result)
+	})
+
+	t.Run("Detected language selects its rules", func(t *testing.T) {
+		result, err := service.AnalyzeText(context.Background(), &domain.TextAnalysisRequest{
+			Sentence: "Der Hund schläft heute im Garten, weil das Wetter schön ist.",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "de", result.Language)
+		assert.True(t, result.LanguageDetected)
+	})
+
+	t.Run("Language without phonology table", func(t *testing.T) {
+		result, err := service.AnalyzeText(context.Background(), &domain.TextAnalysisRequest{
+			Sentence: "こんにちは世界",
+			Language: "ja",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "ja", result.Language)
+		assert.False(t, result.LanguageDetected)
+		assert.Equal(t, 7, result.OtherLetterCount)
+	})
+
+	t.Run("Selected analyses are keyed by name", func(t *testing.T) {
+		result, err := service.AnalyzeText(context.Background(), &domain.TextAnalysisRequest{
+			Sentence: "Hello world",
+			Analyses: []string{"counts", "counts"},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, result.Results, 1)
+		assert.Equal(t, &domain.CountsResult{
+			WordCount:      2,
+			VowelCount:     3,
+			ConsonantCount: 7,
+		}, result.Results["counts"])
+	})
+
+	t.Run("Unknown analyzer", func(t *testing.T) {
+		result, err := service.AnalyzeText(context.Background(), &domain.TextAnalysisRequest{
+			Sentence: "Hello world",
+			Analyses: []string{"counts", "telepathy"},
+		})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		assert.Contains(t, err.Error(), `"telepathy"`)
+		assert.Contains(t, err.Error(), "available analyzers: counts")
+		assert.Nil(t, result)
+	})
+}
+
+func TestTextAnalysisService_AnalyzeTextLogsNoText(t *testing.T) {
+	registry, err := NewAnalyzerRegistry(DefaultAnalyzers(AnalyzerResources{})...)
+	require.NoError(t, err)
+
+	sentence := "My card was stolen yesterday"
+	tests := []struct {
+		policy   logging.Policy
+		expected map[string]interface{}
+	}{
+		{logging.PolicyOff, map[string]interface{}{}},
+		{logging.PolicyLength, map[string]interface{}{"sentence_length": int64(28)}},
+		{logging.PolicyTruncate, map[string]interface{}{"sentence": "My card…"}},
+		{logging.PolicyHash, map[string]interface{}{"sentence": "sha256:c16dfb57adbe6402"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			core, logs := observer.New(zap.InfoLevel)
+			service := NewTextAnalysisService(registry, NewTokenizer(), nil, logging.WithPolicy(zap.New(core), tt.policy, 7))
+
+			_, err := service.AnalyzeText(context.Background(), &domain.TextAnalysisRequest{Sentence: sentence})
+			require.NoError(t, err)
+
+			require.NotEmpty(t, logs.All())
+			for _, entry := range logs.All() {
+				fields := entry.ContextMap()
+				assert.NotContains(t, fmt.Sprint(fields), "stolen")
+				for key, value := range tt.expected {
+					assert.Equal(t, value, fields[key])
+				}
+				if tt.policy == logging.PolicyOff {
+					assert.NotContains(t, fields, "sentence")
+				}
+			}
+		})
+	}
+
+	t.Run("unwrapped logger", func(t *testing.T) {
+		core, logs := observer.New(zap.InfoLevel)
+		service := NewTextAnalysisService(registry, NewTokenizer(), nil, zap.New(core))
+
+		_, err := service.AnalyzeText(context.Background(), &domain.TextAnalysisRequest{Sentence: sentence})
+		require.NoError(t, err)
+
+		for _, entry := range logs.All() {
+			assert.NotContains(t, fmt.Sprint(entry.ContextMap()), "stolen")
+		}
+	})
 }
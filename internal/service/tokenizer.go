@@ -0,0 +1,129 @@
+package service
+
+import (
+	"unicode"
+)
+
+type TokenKind int
+
+const (
+	TokenWord TokenKind = iota
+	TokenNumber
+)
+
+func (k TokenKind) String() string {
+	if k == TokenNumber {
+		return "number"
+	}
+	return "word"
+}
+
+// Token is a word-like segment of text. Start and End are offsets in Unicode
+// code points into the tokenized text; End is exclusive.
+type Token struct {
+	Text  string
+	Start int
+	End   int
+	Kind  TokenKind
+}
+
+// Tokenizer splits text into words. Whitespace, punctuation and symbols are
+// not returned.
+type Tokenizer interface {
+	Tokenize(text string) []Token
+}
+
+type uax29Tokenizer struct {
+	dictionary *segmentationDictionary
+}
+
+// NewTokenizer returns a tokenizer following the word boundary rules of
+// Unicode Text Segmentation (UAX #29). Runs of Chinese, Japanese, Thai, Lao,
+// Khmer and Burmese characters, which UAX #29 does not join into words, are
+// segmented with the embedded dictionaries.
+func NewTokenizer() Tokenizer {
+	return &uax29Tokenizer{
+		dictionary: defaultSegmentationDictionary(),
+	}
+}
+
+func (t *uax29Tokenizer) Tokenize(text string) []Token {
+	runes := []rune(text)
+	boundaries := wordBoundaries(runes)
+
+	var tokens []Token
+	runStart := -1
+	flushRun := func(end int) {
+		if runStart >= 0 {
+			tokens = append(tokens, t.segmentRun(runes, runStart, end)...)
+			runStart = -1
+		}
+	}
+
+	for i := 0; i+1 < len(boundaries); i++ {
+		start, end := boundaries[i], boundaries[i+1]
+		segment := runes[start:end]
+
+		if isDictionarySegment(segment) {
+			if runStart < 0 {
+				runStart = start
+			}
+			continue
+		}
+		flushRun(start)
+
+		kind, ok := segmentKind(segment)
+		if !ok {
+			continue
+		}
+		tokens = append(tokens, Token{
+			Text:  string(segment),
+			Start: start,
+			End:   end,
+			Kind:  kind,
+		})
+	}
+	flushRun(len(runes))
+
+	return tokens
+}
+
+// segmentRun splits a run of characters from scripts written without spaces
+// into dictionary words.
+func (t *uax29Tokenizer) segmentRun(runes []rune, start, end int) []Token {
+	spans := t.dictionary.segment(runes[start:end])
+
+	tokens := make([]Token, 0, len(spans))
+	for _, span := range spans {
+		tokens = append(tokens, Token{
+			Text:  string(runes[start+span[0] : start+span[1]]),
+			Start: start + span[0],
+			End:   start + span[1],
+			Kind:  TokenWord,
+		})
+	}
+	return tokens
+}
+
+func isDictionarySegment(segment []rune) bool {
+	return isDictionaryScript(segment[0])
+}
+
+// segmentKind classifies a UAX #29 segment. Segments without letters or digits,
+// such as spaces, punctuation and emoji, are not words.
+func segmentKind(segment []rune) (TokenKind, bool) {
+	hasDigit := false
+	for _, r := range segment {
+		if unicode.IsLetter(r) {
+			return TokenWord, true
+		}
+		if unicode.IsDigit(r) {
+			hasDigit = true
+		}
+	}
+
+	if hasDigit {
+		return TokenNumber, true
+	}
+	return TokenWord, false
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenizer_Tokenize(t *testing.T) {
+	tokenizer := NewTokenizer()
+
+	tests := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{name: "Punctuation without spaces", text: "Hello,world", expected: []string{"Hello", "world"}},
+		{name: "Dash is not a word", text: "wait — what", expected: []string{"wait", "what"}},
+		{name: "Apostrophes and contractions", text: "Don't stop O'Neil's car", expected: []string{"Don't", "stop", "O'Neil's", "car"}},
+		{name: "Numbers with separators", text: "Pay 1,234.56 by 3:30", expected: []string{"Pay", "1,234.56", "by", "3", "30"}},
+		{name: "Trailing period", text: "e.g. U.S.A. rocks.", expected: []string{"e.g", "U.S.A", "rocks"}},
+		{name: "Underscores join", text: "snake_case id_42", expected: []string{"snake_case", "id_42"}},
+		{name: "Decomposed accents", text: "café ok", expected: []string{"café", "ok"}},
+		{name: "Katakana runs", text: "コンピューター", expected: []string{"コンピューター"}},
+		{name: "Chinese dictionary", text: "我们是学生。", expected: []string{"我们", "是", "学生"}},
+		{name: "Japanese dictionary", text: "私は学生です", expected: []string{"私", "は", "学生", "です"}},
+		{name: "Thai dictionary", text: "สวัสดีครับ ผมชอบอาหารไทย", expected: []string{"สวัสดี", "ครับ", "ผม", "ชอบ", "อาหาร", "ไทย"}},
+		{name: "Mixed scripts", text: "Hello 世界 123", expected: []string{"Hello", "世界", "123"}},
+		{name: "Unknown ideographs stand alone", text: "龘靐", expected: []string{"龘", "靐"}},
+		{name: "Emoji are skipped", text: "great 👍🏽 job", expected: []string{"great", "job"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := tokenizer.Tokenize(tt.text)
+
+			texts := make([]string, 0, len(tokens))
+			runes := []rune(tt.text)
+			for _, token := range tokens {
+				texts = append(texts, token.Text)
+				assert.Equal(t, token.Text, string(runes[token.Start:token.End]))
+			}
+			assert.Equal(t, tt.expected, texts)
+		})
+	}
+
+	t.Run("Token kinds", func(t *testing.T) {
+		tokens := tokenizer.Tokenize("Room 101")
+
+		assert.Equal(t, TokenWord, tokens[0].Kind)
+		assert.Equal(t, TokenNumber, tokens[1].Kind)
+	})
+}
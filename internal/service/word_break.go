@@ -0,0 +1,245 @@
+package service
+
+import "unicode"
+
+// wordBreakClass is the Word_Break property of Unicode Standard Annex #29,
+// derived from general categories and scripts rather than the full property
+// table.
+type wordBreakClass int
+
+const (
+	wbOther wordBreakClass = iota
+	wbCR
+	wbLF
+	wbNewline
+	wbExtend
+	wbZWJ
+	wbRegionalIndicator
+	wbFormat
+	wbKatakana
+	wbHebrewLetter
+	wbALetter
+	wbSingleQuote
+	wbDoubleQuote
+	wbMidNumLet
+	wbMidLetter
+	wbMidNum
+	wbNumeric
+	wbExtendNumLet
+	wbWSegSpace
+)
+
+// Scripts written without spaces between words. UAX #29 leaves their
+// characters unjoined and they are segmented with a dictionary instead.
+var dictionaryScripts = []*unicode.RangeTable{
+	unicode.Han,
+	unicode.Hiragana,
+	unicode.Thai,
+	unicode.Lao,
+	unicode.Khmer,
+	unicode.Myanmar,
+}
+
+func wordBreakClassOf(r rune) wordBreakClass {
+	switch r {
+	case '\r':
+		return wbCR
+	case '\n':
+		return wbLF
+	case 0x0B, 0x0C, 0x85, 0x2028, 0x2029:
+		return wbNewline
+	case 0x200D:
+		return wbZWJ
+	case 0x200C:
+		return wbExtend
+	case 0x200B:
+		return wbOther
+	case '\'':
+		return wbSingleQuote
+	case '"':
+		return wbDoubleQuote
+	case '.', 0x2018, 0x2019, 0x2024, 0xFE52, 0xFF07, 0xFF0E:
+		return wbMidNumLet
+	case ':', 0xB7, 0x387, 0x55F, 0x5F4, 0x2027, 0xFE13, 0xFE55, 0xFF1A:
+		return wbMidLetter
+	case ',', ';', 0x37E, 0x589, 0x60C, 0x60D, 0x66C, 0x7F8, 0x2044, 0xFE10, 0xFE14, 0xFE50, 0xFE54, 0xFF0C, 0xFF1B:
+		return wbMidNum
+	case 0x202F:
+		return wbExtendNumLet
+	case 0x3031, 0x3032, 0x3033, 0x3034, 0x3035, 0x309B, 0x309C, 0x30A0, 0x30FC, 0xFF70:
+		return wbKatakana
+	}
+
+	switch {
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return wbRegionalIndicator
+	case r >= 0x1F3FB && r <= 0x1F3FF:
+		return wbExtend
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		return wbExtend
+	case unicode.Is(unicode.Cf, r):
+		return wbFormat
+	case unicode.Is(unicode.Katakana, r):
+		return wbKatakana
+	case unicode.Is(unicode.Hebrew, r) && unicode.IsLetter(r):
+		return wbHebrewLetter
+	case unicode.Is(unicode.Nd, r):
+		return wbNumeric
+	case unicode.Is(unicode.Pc, r):
+		return wbExtendNumLet
+	case unicode.Is(unicode.Zs, r):
+		return wbWSegSpace
+	case unicode.IsLetter(r) && !isDictionaryScript(r):
+		return wbALetter
+	}
+
+	return wbOther
+}
+
+func isDictionaryScript(r rune) bool {
+	return unicode.In(r, dictionaryScripts...)
+}
+
+func isExtendedPictographic(r rune) bool {
+	return (r >= 0x1F000 && r <= 0x1FAFF) || (r >= 0x2600 && r <= 0x27BF) || r == 0x00A9 || r == 0x00AE
+}
+
+func (c wordBreakClass) ignorable() bool {
+	return c == wbExtend || c == wbFormat || c == wbZWJ
+}
+
+func (c wordBreakClass) isAHLetter() bool {
+	return c == wbALetter || c == wbHebrewLetter
+}
+
+func (c wordBreakClass) isMidNumLetQ() bool {
+	return c == wbMidNumLet || c == wbSingleQuote
+}
+
+func (c wordBreakClass) isNewline() bool {
+	return c == wbCR || c == wbLF || c == wbNewline
+}
+
+// wordBoundaries returns the positions of all word boundaries in runes,
+// including 0 and len(runes), following rules WB1 to WB999.
+func wordBoundaries(runes []rune) []int {
+	if len(runes) == 0 {
+		return nil
+	}
+
+	classes := make([]wordBreakClass, len(runes))
+	for i, r := range runes {
+		classes[i] = wordBreakClassOf(r)
+	}
+
+	boundaries := []int{0}
+	for i := 1; i < len(runes); i++ {
+		if isWordBreak(runes, classes, i) {
+			boundaries = append(boundaries, i)
+		}
+	}
+
+	return append(boundaries, len(runes))
+}
+
+// isWordBreak reports whether there is a word boundary between runes[i-1] and
+// runes[i].
+func isWordBreak(runes []rune, classes []wordBreakClass, i int) bool {
+	before, after := classes[i-1], classes[i]
+
+	switch {
+	case before == wbCR && after == wbLF: // WB3
+		return false
+	case before.isNewline() || after.isNewline(): // WB3a, WB3b
+		return true
+	case before == wbZWJ && isExtendedPictographic(runes[i]): // WB3c
+		return false
+	case before == wbWSegSpace && after == wbWSegSpace: // WB3d
+		return false
+	case after.ignorable(): // WB4
+		return false
+	}
+
+	// WB4: Extend, Format and ZWJ are transparent for the remaining rules.
+	prev := previousSignificant(classes, i)
+	if prev < 0 {
+		return true
+	}
+	before = classes[prev]
+	beforePrev := wbOther
+	if p := previousSignificant(classes, prev); p >= 0 {
+		beforePrev = classes[p]
+	}
+	afterNext := wbOther
+	if n := nextSignificant(classes, i+1); n >= 0 {
+		afterNext = classes[n]
+	}
+
+	switch {
+	case before.isAHLetter() && after.isAHLetter(): // WB5
+		return false
+	case before.isAHLetter() && (after == wbMidLetter || after.isMidNumLetQ()) && afterNext.isAHLetter(): // WB6
+		return false
+	case beforePrev.isAHLetter() && (before == wbMidLetter || before.isMidNumLetQ()) && after.isAHLetter(): // WB7
+		return false
+	case before == wbHebrewLetter && after == wbSingleQuote: // WB7a
+		return false
+	case before == wbHebrewLetter && after == wbDoubleQuote && afterNext == wbHebrewLetter: // WB7b
+		return false
+	case beforePrev == wbHebrewLetter && before == wbDoubleQuote && after == wbHebrewLetter: // WB7c
+		return false
+	case before == wbNumeric && after == wbNumeric: // WB8
+		return false
+	case before.isAHLetter() && after == wbNumeric: // WB9
+		return false
+	case before == wbNumeric && after.isAHLetter(): // WB10
+		return false
+	case beforePrev == wbNumeric && (before == wbMidNum || before.isMidNumLetQ()) && after == wbNumeric: // WB11
+		return false
+	case before == wbNumeric && (after == wbMidNum || after.isMidNumLetQ()) && afterNext == wbNumeric: // WB12
+		return false
+	case before == wbKatakana && after == wbKatakana: // WB13
+		return false
+	case (before.isAHLetter() || before == wbNumeric || before == wbKatakana || before == wbExtendNumLet) &&
+		after == wbExtendNumLet: // WB13a
+		return false
+	case before == wbExtendNumLet && (after.isAHLetter() || after == wbNumeric || after == wbKatakana): // WB13b
+		return false
+	case before == wbRegionalIndicator && after == wbRegionalIndicator: // WB15, WB16
+		return precedingRegionalIndicators(classes, i)%2 == 0
+	}
+
+	return true // WB999
+}
+
+func previousSignificant(classes []wordBreakClass, i int) int {
+	for j := i - 1; j >= 0; j-- {
+		if !classes[j].ignorable() {
+			return j
+		}
+	}
+	return -1
+}
+
+func nextSignificant(classes []wordBreakClass, i int) int {
+	for j := i; j < len(classes); j++ {
+		if !classes[j].ignorable() {
+			return j
+		}
+	}
+	return -1
+}
+
+func precedingRegionalIndicators(classes []wordBreakClass, i int) int {
+	count := 0
+	for j := i - 1; j >= 0; j-- {
+		if classes[j].ignorable() {
+			continue
+		}
+		if classes[j] != wbRegionalIndicator {
+			break
+		}
+		count++
+	}
+	return count
+}